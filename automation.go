@@ -1,9 +1,9 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"os"
 	"runtime"
 	"strings"
@@ -24,6 +24,10 @@ type Automation struct {
 	stopChan     chan bool
 	itemInCart   bool
 	cachedSKU    string // SKU extracted and validated before login
+	prompter     Prompter
+	reporter     Reporter
+
+	restoredCookies []*http.Cookie // Session restored from the encrypted cache (login prompt is skipped)
 }
 
 func NewAutomation(config *Config) *Automation {
@@ -31,6 +35,8 @@ func NewAutomation(config *Config) *Automation {
 		config:   config,
 		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
 		stopChan: make(chan bool, 1),
+		prompter: NewPrompter(config),
+		reporter: NewReporter(config, "browser"),
 	}
 }
 
@@ -40,7 +46,7 @@ func (a *Automation) Close() {
 	default:
 	}
 
-	fmt.Println(T("cleaning_up"))
+	a.reporter.Info(T("cleaning_up"))
 
 	if a.page != nil {
 		a.page.Close()
@@ -54,7 +60,7 @@ func (a *Automation) Close() {
 		a.launcher.Cleanup()
 	}
 
-	fmt.Println(T("browser_destroyed"))
+	a.reporter.Info(T("browser_destroyed"))
 }
 
 func (a *Automation) isBrowserAlive() bool {
@@ -81,8 +87,9 @@ func (a *Automation) isBrowserAlive() bool {
 
 func (a *Automation) checkBrowserOrExit() {
 	if !a.isBrowserAlive() {
-		fmt.Println(T("browser_closed_by_user"))
-		fmt.Println(T("shutting_down"))
+		a.reporter.Warn(T("browser_closed_by_user"))
+		a.reporter.Info(T("shutting_down"))
+		closeReporter(a.reporter)
 		os.Exit(0)
 	}
 }
@@ -107,7 +114,7 @@ func (a *Automation) randomDelay() {
 	duration := min + a.rand.Float64()*(max-min)
 
 	if !a.config.DebugMode {
-		fmt.Printf(T("waiting_seconds")+"\n", duration)
+		a.reporter.Info(T("waiting_seconds", "seconds", duration))
 	}
 	time.Sleep(time.Duration(duration * float64(time.Second)))
 }
@@ -124,13 +131,16 @@ func (a *Automation) getClickTimeout() time.Duration {
 
 
 func (a *Automation) debugLog(format string, args ...interface{}) {
-	if a.config.DebugMode {
-		fmt.Printf("[DEBUG] "+format+"\n", args...)
+	// Translated messages arrive already formatted and may contain a literal %
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
 	}
+	a.reporter.Debug("[DEBUG] " + redactText(message))
 }
 
 func (a *Automation) setupBrowser() error {
-	fmt.Println(T("browser_launching"))
+	a.reporter.Info(T("browser_launching"))
 
 	// Disable leakless mode on Windows to prevent deadlock
 	// See: https://github.com/go-rod/rod/issues/853
@@ -148,20 +158,20 @@ func (a *Automation) setupBrowser() error {
 	// IMPORTANT: Must set this before Bin() to ensure it's applied
 	if a.config.BrowserProfilePath != "" {
 		a.launcher = a.launcher.UserDataDir(a.config.BrowserProfilePath)
-		a.debugLog(T("browser_profile_path_set", a.config.BrowserProfilePath))
+		a.debugLog(T("browser_profile_path_set", "path", a.config.BrowserProfilePath))
 	}
 
 	if chromeExists {
 		a.launcher = a.launcher.Bin(chromePath)
-		fmt.Println(T("browser_using_system_chrome"))
-		a.debugLog(T("browser_chrome_path_set", chromePath))
+		a.reporter.Info(T("browser_using_system_chrome"))
+		a.debugLog(T("browser_chrome_path_set", "path", chromePath))
 	} else {
-		fmt.Println(T("browser_chrome_not_found"))
+		a.reporter.Info(T("browser_chrome_not_found"))
 		// Will use automatic Chromium download (default behavior)
 	}
 
 	if runtime.GOOS == "windows" {
-		fmt.Println(T("windows_leakless_disabled"))
+		a.reporter.Info(T("windows_leakless_disabled"))
 	}
 
 	url, err := a.launcher.Launch()
@@ -170,35 +180,35 @@ func (a *Automation) setupBrowser() error {
 		if strings.Contains(errMsg, "Opening in existing browser session") ||
 			strings.Contains(errMsg, "ProcessSingleton") ||
 			strings.Contains(errMsg, "SingletonLock") {
-			fmt.Println(T("error_chrome_already_running_header"))
-			fmt.Println(T("error_chrome_fix_instructions"))
-			fmt.Println(T("error_chrome_close_all"))
+			a.reporter.Error(T("error_chrome_already_running_header"))
+			a.reporter.Info(T("error_chrome_fix_instructions"))
+			a.reporter.Info(T("error_chrome_close_all"))
 			if runtime.GOOS == "darwin" {
-				fmt.Println(T("error_chrome_mac_activity_monitor"))
-				fmt.Println(T("error_chrome_mac_killall"))
+				a.reporter.Info(T("error_chrome_mac_activity_monitor"))
+				a.reporter.Info(T("error_chrome_mac_killall"))
 			} else if runtime.GOOS == "windows" {
-				fmt.Println(T("error_chrome_windows_task_manager"))
-				fmt.Println(T("error_chrome_windows_end_processes"))
+				a.reporter.Info(T("error_chrome_windows_task_manager"))
+				a.reporter.Info(T("error_chrome_windows_end_processes"))
 			}
-			fmt.Println(T("error_chrome_try_again"))
-			return fmt.Errorf(T("error_chrome_already_running"))
+			a.reporter.Info(T("error_chrome_try_again"))
+			return TError("error_chrome_already_running")
 		}
 
 		// Check for permission/access errors during download
 		if strings.Contains(errMsg, "Access is denied") || strings.Contains(errMsg, "permission denied") {
-			fmt.Println(T("error_browser_download_permission"))
-			fmt.Println(T("error_browser_download_fix"))
-			fmt.Println(T("error_browser_download_close_chrome"))
+			a.reporter.Error(T("error_browser_download_permission"))
+			a.reporter.Info(T("error_browser_download_fix"))
+			a.reporter.Info(T("error_browser_download_close_chrome"))
 			if runtime.GOOS == "windows" {
-				fmt.Println(T("error_browser_download_delete_windows"))
-				fmt.Println(T("error_browser_download_exclusion_windows"))
+				a.reporter.Info(T("error_browser_download_delete_windows"))
+				a.reporter.Info(T("error_browser_download_exclusion_windows"))
 			} else {
-				fmt.Println(T("error_browser_download_delete_mac"))
+				a.reporter.Info(T("error_browser_download_delete_mac"))
 			}
-			fmt.Println(T("error_browser_download_try_again"))
-			fmt.Println(T("error_browser_download_alternative"))
-			fmt.Println(T("error_browser_download_chrome_url"))
-			return fmt.Errorf(T("error_browser_setup_failed"), err)
+			a.reporter.Info(T("error_browser_download_try_again"))
+			a.reporter.Info(T("error_browser_download_alternative"))
+			a.reporter.Info(T("error_browser_download_chrome_url"))
+			return TError("error_browser_setup_failed", "error", err)
 		}
 
 		return fmt.Errorf("failed to launch browser: %w", err)
@@ -210,16 +220,16 @@ func (a *Automation) setupBrowser() error {
 	go a.watchBrowser()
 	a.debugLog(T("browser_watcher_started"))
 
-	fmt.Println(T("browser_launched"))
+	a.reporter.Info(T("browser_launched"))
 	return nil
 }
 
 func (a *Automation) waitForLogin() error {
-	fmt.Println(T("opening_for_login"))
+	a.reporter.Info(T("opening_for_login"))
 
 	// ALWAYS open homepage first for login (not the item URL)
 	homepageURL := "https://robertsspaceindustries.com"
-	fmt.Printf(T("loading_homepage")+"\n", homepageURL)
+	a.reporter.Info(T("loading_homepage", "url", homepageURL))
 
 	var err error
 	a.page, err = stealth.Page(a.browser)
@@ -229,6 +239,15 @@ func (a *Automation) waitForLogin() error {
 
 	a.debugLog("✓ Stealth mode enabled (anti-bot detection)")
 
+	// A restored session is pushed into the browser before the first navigation so the
+	// page (and reCAPTCHA) run as the logged-in customer
+	if len(a.restoredCookies) > 0 {
+		if err := a.injectSessionCookies(a.restoredCookies); err != nil {
+			a.debugLog("Warning: Failed to inject cached session cookies: %v", err)
+			a.restoredCookies = nil
+		}
+	}
+
 	err = a.page.Navigate(homepageURL)
 	if err != nil {
 		return fmt.Errorf("failed to navigate: %w", err)
@@ -248,46 +267,25 @@ func (a *Automation) waitForLogin() error {
 		return fmt.Errorf("page failed to load: %w", err)
 	}
 
-	fmt.Println(T("browser_configured"))
-
-	// Prompt user to login BEFORE trying to load item page
-	fmt.Println()
-	fmt.Println(T("login_required_header"))
-	fmt.Println()
-	fmt.Println(T("login_instructions"))
-	fmt.Println()
-	fmt.Print(T("login_prompt"))
-
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		input, err := reader.ReadByte()
-		if err != nil {
-			return fmt.Errorf("failed to read input: %w", err)
-		}
-
-		if input == '\n' || input == '\r' {
-			fmt.Println()
-			fmt.Println(T("user_confirmed_ready"))
-			break
-		}
+	a.reporter.Info(T("browser_configured"))
 
-		if input == 27 {
-			fmt.Println()
-			fmt.Println(T("user_requested_exit"))
-			return fmt.Errorf("user canceled operation")
-		}
+	if len(a.restoredCookies) > 0 {
+		// Cached session was validated against the store - no need to log in again
+		a.reporter.Info(T("session_cache_login_skipped"))
+	} else if err := a.waitForUserLogin(); err != nil {
+		return err
 	}
 
 	// AFTER login, navigate to item URL and retry until it's available (not 404)
 	if a.config.ItemURL != "" {
-		fmt.Println()
-		fmt.Printf(T("navigating_to_product_page")+"\n", a.config.ItemURL)
+		a.reporter.Info("")
+		a.reporter.Info(T("navigating_to_product_page", "url", a.config.ItemURL))
 
 		if err := a.navigateToProductPageWithRetry(); err != nil {
 			return err
 		}
 
-		fmt.Println(T("product_page_loaded"))
+		a.reporter.Info(T("product_page_loaded"))
 
 		// Extract and validate SKU AFTER successful navigation
 		if err := a.extractAndCacheSKU(); err != nil {
@@ -296,16 +294,63 @@ func (a *Automation) waitForLogin() error {
 	}
 
 	if a.config.RecaptchaSiteKey != "" {
-		fmt.Println(T("recaptcha_preloading"))
+		a.reporter.Info(T("recaptcha_preloading"))
 		a.preloadRecaptcha()
 
-		fmt.Println(T("building_interaction_history"))
+		a.reporter.Info(T("building_interaction_history"))
 		a.buildInteractionHistory()
 	}
 
 	return nil
 }
 
+// waitForUserLogin asks the user to log in in the browser window and waits for ENTER
+func (a *Automation) waitForUserLogin() error {
+	// Prompt user to login BEFORE trying to load item page
+	a.reporter.Info("")
+	a.reporter.Info(T("login_required_header"))
+	a.reporter.Info("")
+	a.reporter.Info(T("login_instructions"))
+	a.reporter.Info("")
+	answer, err := a.prompter.Choose(Prompt{
+		Kind:    PromptLoginRequired,
+		Message: T("login_prompt"),
+		Options: []PromptAnswer{AnswerProceed, AnswerAbort},
+	})
+	if err != nil {
+		return err
+	}
+
+	if answer == AnswerAbort {
+		a.reporter.Warn(T("user_requested_exit"))
+		return fmt.Errorf("user canceled operation")
+	}
+
+	a.reporter.Info(T("user_confirmed_ready"))
+	return nil
+}
+
+// injectSessionCookies loads cached session cookies into the browser
+func (a *Automation) injectSessionCookies(cookies []*http.Cookie) error {
+	params := make([]*proto.NetworkCookieParam, 0, len(cookies))
+	for _, cookie := range cookies {
+		param := &proto.NetworkCookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+		}
+		if !cookie.Expires.IsZero() {
+			param.Expires = proto.TimeSinceEpoch(cookie.Expires.Unix())
+		}
+		params = append(params, param)
+	}
+
+	return a.browser.SetCookies(params)
+}
+
 // navigateToProductPageWithRetry retries navigation to item URL until it's available (not 404)
 // This is critical for pre-sale scenarios where the product page doesn't exist yet
 func (a *Automation) navigateToProductPageWithRetry() error {
@@ -318,9 +363,9 @@ func (a *Automation) navigateToProductPageWithRetry() error {
 		if err != nil {
 			// Network error - retry after delay
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf("⚠️  Attempt %d: Navigation error - retrying in 2s...\n", attemptNum)
+				a.reporter.Warn(fmt.Sprintf("⚠️  Attempt %d: Navigation error - retrying in 2s...", attemptNum))
 				if attemptNum <= 3 {
-					fmt.Printf("   Error: %v\n", err)
+					a.reporter.Info(fmt.Sprintf("   Error: %v", err))
 				}
 			}
 			time.Sleep(2 * time.Second)
@@ -330,7 +375,7 @@ func (a *Automation) navigateToProductPageWithRetry() error {
 		// Wait for page to load
 		if err := a.page.WaitLoad(); err != nil {
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf("⚠️  Attempt %d: Page load error - retrying in 2s...\n", attemptNum)
+				a.reporter.Warn(fmt.Sprintf("⚠️  Attempt %d: Page load error - retrying in 2s...", attemptNum))
 			}
 			time.Sleep(2 * time.Second)
 			continue
@@ -347,19 +392,19 @@ func (a *Automation) navigateToProductPageWithRetry() error {
 			if status == 404 {
 				// 404 - Page doesn't exist yet, keep retrying
 				if attemptNum == 1 {
-					fmt.Println("⏳ Product page not available yet (404) - waiting for sale to go live...")
-					fmt.Println("💡 The app will keep retrying until the page is available")
-					fmt.Println()
+					a.reporter.Info("⏳ Product page not available yet (404) - waiting for sale to go live...")
+					a.reporter.Info("💡 The app will keep retrying until the page is available")
+					a.reporter.Info("")
 				}
 				if attemptNum%30 == 0 {
-					fmt.Printf("   Still waiting... (attempt %d, checking every 2s)\n", attemptNum)
+					a.reporter.Info(fmt.Sprintf("   Still waiting... (attempt %d, checking every 2s)", attemptNum))
 				}
 				time.Sleep(2 * time.Second)
 				continue
 			} else if status >= 400 {
 				// Other error status - this might be temporary
 				if attemptNum%10 == 0 || attemptNum <= 3 {
-					fmt.Printf("⚠️  Attempt %d: HTTP %d error - retrying in 2s...\n", attemptNum, status)
+					a.reporter.Warn(fmt.Sprintf("⚠️  Attempt %d: HTTP %d error - retrying in 2s...", attemptNum, status))
 				}
 				time.Sleep(2 * time.Second)
 				continue
@@ -377,7 +422,7 @@ func (a *Automation) navigateToProductPageWithRetry() error {
 		if err == nil && !hasSKUData.Value.Bool() {
 			// No SKU data - might be loading or wrong page
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf("⚠️  Attempt %d: Page loaded but no SKU data found - retrying in 2s...\n", attemptNum)
+				a.reporter.Warn(fmt.Sprintf("⚠️  Attempt %d: Page loaded but no SKU data found - retrying in 2s...", attemptNum))
 			}
 			time.Sleep(2 * time.Second)
 			continue
@@ -385,7 +430,7 @@ func (a *Automation) navigateToProductPageWithRetry() error {
 
 		// Success! Page is loaded and valid
 		if attemptNum > 1 {
-			fmt.Printf("✓ Product page is now available! (took %d attempts)\n", attemptNum)
+			a.reporter.Info(fmt.Sprintf("✓ Product page is now available! (took %d attempts)", attemptNum))
 		}
 		return nil
 	}
@@ -452,13 +497,13 @@ func (a *Automation) buildInteractionHistory() {
 	}
 
 	a.debugLog(T("interaction_history_built"))
-	fmt.Println(T("interaction_history_complete"))
+	a.reporter.Info(T("interaction_history_complete"))
 }
 
 func (a *Automation) preloadRecaptcha() {
 	checkExisting, err := a.page.Eval(`() => typeof grecaptcha !== 'undefined' && typeof grecaptcha.enterprise !== 'undefined'`)
 	if err == nil && checkExisting.Value.Bool() {
-		fmt.Println(T("recaptcha_already_loaded"))
+		a.reporter.Info(T("recaptcha_already_loaded"))
 		a.debugLog(T("debug_recaptcha_present"))
 
 		actualKey, err := a.page.Eval(`() => {
@@ -471,9 +516,9 @@ func (a *Automation) preloadRecaptcha() {
 		}`)
 		if err == nil && actualKey != nil && actualKey.Value.Str() != "" {
 			detectedKey := actualKey.Value.Str()
-			a.debugLog(T("recaptcha_detected_key"), detectedKey)
+			a.debugLog(T("recaptcha_detected_key", "key", detectedKey))
 			if detectedKey != a.config.RecaptchaSiteKey {
-				fmt.Printf(T("recaptcha_key_warning")+"\n", a.config.RecaptchaSiteKey, detectedKey)
+				a.reporter.Warn(T("recaptcha_key_warning", "config_key", a.config.RecaptchaSiteKey, "page_key", detectedKey))
 			}
 		}
 
@@ -492,8 +537,8 @@ func (a *Automation) preloadRecaptcha() {
 
 	_, err = a.page.Eval(injectScript)
 	if err != nil {
-		a.debugLog(T("warning_recaptcha_script_inject"), err)
-		fmt.Println(T("recaptcha_injection_failed"))
+		a.debugLog(T("warning_recaptcha_script_inject", "error", err))
+		a.reporter.Warn(T("recaptcha_injection_failed"))
 		return
 	}
 
@@ -506,13 +551,13 @@ func (a *Automation) preloadRecaptcha() {
 
 		readyCheck, err := a.page.Eval(`() => typeof grecaptcha !== 'undefined' && typeof grecaptcha.enterprise !== 'undefined'`)
 		if err == nil && readyCheck.Value.Bool() {
-			fmt.Println(T("recaptcha_ready"))
-			a.debugLog(T("recaptcha_loaded_after"), (i+1)*100)
+			a.reporter.Info(T("recaptcha_ready"))
+			a.debugLog(T("recaptcha_loaded_after", "ms", (i+1)*100))
 			return
 		}
 	}
 
-	fmt.Println(T("recaptcha_timeout"))
+	a.reporter.Warn(T("recaptcha_timeout"))
 	a.debugLog(T("recaptcha_timeout_after"))
 }
 
@@ -520,12 +565,12 @@ func (a *Automation) preloadRecaptcha() {
 // This is called AFTER login to extract the SKU from the authenticated page
 // Uses the browser directly (which has auth cookies) instead of HTTP client
 func (a *Automation) extractAndCacheSKU() error {
-	fmt.Println(T("sku_extracting_validating"))
+	a.reporter.Info(T("sku_extracting_validating"))
 
 	// Get current URL for debugging
 	currentURL, err := a.page.Eval(`() => window.location.href`)
 	if err != nil || currentURL.Value.Str() == "" {
-		return fmt.Errorf(T("sku_could_not_get_url"))
+		return TError("sku_could_not_get_url")
 	}
 	itemURL := currentURL.Value.Str()
 
@@ -542,16 +587,16 @@ func (a *Automation) extractAndCacheSKU() error {
 		select {
 		case <-timeout:
 			// Timeout - SKU not found
-			fmt.Println()
-			fmt.Println(T("sku_validation_failed_header"))
-			fmt.Println()
-			fmt.Printf(T("sku_validation_failed_url")+"\n", itemURL)
-			fmt.Println(T("sku_validation_failed_reason"))
-			fmt.Println()
-			fmt.Println(T("sku_validation_failed_fix"))
-			fmt.Println(T("sku_validation_failed_step1"))
-			fmt.Println(T("sku_validation_failed_step2"))
-			fmt.Println()
+			a.reporter.Info("")
+			a.reporter.Error(T("sku_validation_failed_header"))
+			a.reporter.Info("")
+			a.reporter.Info(T("sku_validation_failed_url", "url", itemURL))
+			a.reporter.Info(T("sku_validation_failed_reason"))
+			a.reporter.Info("")
+			a.reporter.Info(T("sku_validation_failed_fix"))
+			a.reporter.Info(T("sku_validation_failed_step1"))
+			a.reporter.Info(T("sku_validation_failed_step2"))
+			a.reporter.Info("")
 			return fmt.Errorf("SKU validation failed: timeout waiting for SKU component")
 
 		case <-ticker.C:
@@ -606,7 +651,7 @@ func (a *Automation) extractAndCacheSKU() error {
 
 				// Success! Cache and return
 				a.cachedSKU = skuSlugStr
-				fmt.Printf(T("sku_validated_cached")+"\n", skuSlugStr)
+				a.reporter.Info(T("sku_validated_cached", "slug", skuSlugStr))
 				return nil
 			}
 		}
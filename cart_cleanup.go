@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cartCleanupStep is one change needed to bring the cart down to a single target unit
+type cartCleanupStep struct {
+	Item     CartItem
+	Remove   bool
+	Quantity int // New quantity when not removing
+}
+
+// planCartCleanup works out which line items to remove or resize so that the cart holds
+// at most one unit of the target SKU. The first target line is kept (at quantity 1);
+// other items and duplicate target lines are removed.
+func planCartCleanup(targetSKU string, items []CartItem) []cartCleanupStep {
+	var steps []cartCleanupStep
+	keptTarget := false
+
+	for _, item := range items {
+		if item.SKUID != targetSKU || keptTarget {
+			steps = append(steps, cartCleanupStep{Item: item, Remove: true})
+			continue
+		}
+
+		keptTarget = true
+		if item.Quantity != 1 {
+			steps = append(steps, cartCleanupStep{Item: item, Quantity: 1})
+		}
+	}
+
+	return steps
+}
+
+// verifyCleanCart checks the cart is either empty or exactly one unit of the target
+func verifyCleanCart(targetSKU string, items []CartItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if len(items) == 1 && items[0].SKUID == targetSKU && items[0].Quantity == 1 {
+		return nil
+	}
+
+	var contents []string
+	for _, item := range items {
+		contents = append(contents, fmt.Sprintf("%s x%d", item.Name, item.Quantity))
+	}
+	return TError("error_cart_cleanup_not_clean", "contents", strings.Join(contents, ", "))
+}
+
+// CleanCart removes everything except one unit of the target SKU, then re-reads the cart
+// and verifies the result. The returned cart is either empty (target still has to be
+// added) or holds exactly the target.
+func (f *FastCheckout) CleanCart(targetSKU string, items []CartItem) (*CartInfo, error) {
+	f.reporter.Info(T("cart_cleanup_start"))
+	f.markCartChanged()
+
+	for _, step := range planCartCleanup(targetSKU, items) {
+		if step.Item.LineItemID == "" {
+			return nil, TError("error_cart_cleanup_no_line_id", "name", step.Item.Name)
+		}
+
+		if step.Remove {
+			f.reporter.Info(T("cart_cleanup_removing", "name", step.Item.Name, "quantity", step.Item.Quantity))
+			if err := f.RemoveLineItem(step.Item.LineItemID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		f.reporter.Info(T("cart_cleanup_setting_quantity", "name", step.Item.Name, "from", step.Item.Quantity, "to", step.Quantity))
+		if err := f.SetLineItemQuantity(step.Item.LineItemID, step.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	cartInfo, err := f.GetCartTotalsAndItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-check cart after cleanup: %w", err)
+	}
+
+	if err := verifyCleanCart(targetSKU, cartInfo.Items); err != nil {
+		return nil, err
+	}
+
+	if len(cartInfo.Items) == 0 {
+		f.reporter.Info(T("cart_cleanup_verified_empty"))
+	} else {
+		f.reporter.Info(T("cart_cleanup_verified", "name", cartInfo.Items[0].Name, "total", cartInfo.Total))
+	}
+
+	return cartInfo, nil
+}
+
+// RemoveLineItem deletes one line item from the cart
+func (f *FastCheckout) RemoveLineItem(lineItemID string) error {
+	mutation := operation("CartRemoveMutation")
+
+	request := []GraphQLRequest{
+		{
+			OperationName: "CartRemoveMutation",
+			Variables: map[string]interface{}{
+				"id":         lineItemID,
+				"storeFront": "pledge",
+			},
+			Query: mutation,
+		},
+	}
+
+	if _, err := f.graphqlRequestWithLoginRetry(request); err != nil {
+		return TError("error_cart_remove_failed", "error", err)
+	}
+	return nil
+}
+
+// SetLineItemQuantity changes the quantity of one line item
+func (f *FastCheckout) SetLineItemQuantity(lineItemID string, qty int) error {
+	mutation := operation("CartUpdateQtyMutation")
+
+	request := []GraphQLRequest{
+		{
+			OperationName: "CartUpdateQtyMutation",
+			Variables: map[string]interface{}{
+				"id":         lineItemID,
+				"qty":        qty,
+				"storeFront": "pledge",
+			},
+			Query: mutation,
+		},
+	}
+
+	if _, err := f.graphqlRequestWithLoginRetry(request); err != nil {
+		return TError("error_cart_update_qty_failed", "error", err)
+	}
+	return nil
+}
+
+// findCartItem returns the first line item for the given SKU
+func findCartItem(items []CartItem, skuID string) (CartItem, bool) {
+	for _, item := range items {
+		if item.SKUID == skuID {
+			return item, true
+		}
+	}
+	return CartItem{}, false
+}
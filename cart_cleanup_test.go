@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeCartLine is one line of the cart held by fakeCartServer
+type fakeCartLine struct {
+	ID    string
+	SKUID string
+	Title string
+	Cents int
+	Qty   int
+}
+
+// fakeCartServer answers the cart queries and mutations used by cleanup, restore and
+// dry-run rollback
+type fakeCartServer struct {
+	mu         sync.Mutex
+	lines      []fakeCartLine
+	operations []string
+	nextID     int
+	credit     int    // Applied store credit in cents
+	step       string // Active checkout flow step ("" means "cart")
+}
+
+func (s *fakeCartServer) activeStep() string {
+	if s.step == "" {
+		return "cart"
+	}
+	return s.step
+}
+
+func (s *fakeCartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var requests []GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request := requests[0]
+	s.operations = append(s.operations, request.OperationName)
+
+	switch request.OperationName {
+	case "CartRemoveMutation":
+		id := request.Variables["id"].(string)
+		for i, line := range s.lines {
+			if line.ID == id {
+				s.lines = append(s.lines[:i], s.lines[i+1:]...)
+				break
+			}
+		}
+		w.Write([]byte(`[{"data":{"store":{"cart":{"mutations":{"remove":true}}}}}]`))
+
+	case "CartUpdateQtyMutation":
+		id := request.Variables["id"].(string)
+		for i := range s.lines {
+			if s.lines[i].ID == id {
+				s.lines[i].Qty = int(request.Variables["qty"].(float64))
+			}
+		}
+		w.Write([]byte(`[{"data":{"store":{"cart":{"mutations":{"updateQty":true}}}}}]`))
+
+	case "AddCartMultiItemMutation":
+		for _, entry := range request.Variables["query"].([]interface{}) {
+			add := entry.(map[string]interface{})
+			s.nextID++
+			s.lines = append(s.lines, fakeCartLine{
+				ID:    fmt.Sprintf("added-%d", s.nextID),
+				SKUID: add["skuId"].(string),
+				Title: "SKU " + add["skuId"].(string),
+				Cents: 1000,
+				Qty:   int(add["qty"].(float64)),
+			})
+		}
+		w.Write([]byte(`[{"data":{"store":{"cart":{"mutations":{"addMany":{"count":1}}}}}}]`))
+
+	case "AddCreditMutation":
+		s.credit = int(math.Round(request.Variables["amount"].(float64) * 100))
+		w.Write([]byte(`[{"data":{"store":{"cart":{"mutations":{"credit_update":true}}}}}]`))
+
+	case "NextStepMutation":
+		s.step = "billing"
+		w.Write([]byte(`[{"data":{"store":{"cart":{"flow":{"steps":[{"step":"billing","active":true}],"current":{"orderCreated":false}}}}}}]`))
+
+	case "CartFlowResetMutation":
+		s.step = request.Variables["step"].(string)
+		w.Write([]byte(`[{"data":{"store":{"cart":{"mutations":{"flow":{"moveTo":true}}}}}}]`))
+
+	case "CartFlowQuery":
+		fmt.Fprintf(w, `[{"data":{"store":{"cart":{"flow":{"steps":[{"step":%q,"active":true}]}}}}}]`, s.activeStep())
+
+	case "CombinedCartQuery", "StepperQuery":
+		total := 0
+		var lineItems []string
+		for _, line := range s.lines {
+			total += line.Cents * line.Qty
+			lineItems = append(lineItems, fmt.Sprintf(
+				`{"id":%q,"skuId":%s,"sku":{"title":%q},"unitPriceWithTax":{"amount":%d},"qty":%d}`,
+				line.ID, line.SKUID, line.Title, line.Cents, line.Qty))
+		}
+		fmt.Fprintf(w, `[{"data":{"store":{"cart":{"totals":{"total":%d,"credits":{"amount":%d,"maxApplicable":%d}},"lineItems":[%s]}},"customer":{"ledger":{"amount":{"value":100000}}}}}]`,
+			total-s.credit, s.credit, total, strings.Join(lineItems, ","))
+
+	default:
+		http.Error(w, "unexpected operation "+request.OperationName, http.StatusBadRequest)
+	}
+}
+
+func newFakeCartCheckout(t *testing.T, lines []fakeCartLine) (*FastCheckout, *fakeCartServer) {
+	t.Helper()
+
+	cart := &fakeCartServer{lines: lines}
+	server := httptest.NewServer(cart)
+	t.Cleanup(server.Close)
+
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.graphqlURL = server.URL
+	return fc, cart
+}
+
+func TestPlanCartCleanup(t *testing.T) {
+	items := []CartItem{
+		{LineItemID: "a", SKUID: "other", Quantity: 1},
+		{LineItemID: "b", SKUID: "target", Quantity: 3},
+		{LineItemID: "c", SKUID: "target", Quantity: 1},
+	}
+
+	steps := planCartCleanup("target", items)
+	if len(steps) != 3 {
+		t.Fatalf("Expected 3 cleanup steps, got %d", len(steps))
+	}
+	if !steps[0].Remove || steps[0].Item.LineItemID != "a" {
+		t.Errorf("Expected other item to be removed, got %+v", steps[0])
+	}
+	if steps[1].Remove || steps[1].Quantity != 1 {
+		t.Errorf("Expected first target line to be set to quantity 1, got %+v", steps[1])
+	}
+	if !steps[2].Remove || steps[2].Item.LineItemID != "c" {
+		t.Errorf("Expected duplicate target line to be removed, got %+v", steps[2])
+	}
+
+	if steps := planCartCleanup("target", items[2:]); len(steps) != 0 {
+		t.Errorf("Clean cart should need no steps, got %+v", steps)
+	}
+}
+
+func TestVerifyCleanCart(t *testing.T) {
+	if err := verifyCleanCart("target", nil); err != nil {
+		t.Errorf("Empty cart should verify: %v", err)
+	}
+	if err := verifyCleanCart("target", []CartItem{{SKUID: "target", Quantity: 1}}); err != nil {
+		t.Errorf("Single target unit should verify: %v", err)
+	}
+	if err := verifyCleanCart("target", []CartItem{{SKUID: "target", Quantity: 2}}); err == nil {
+		t.Error("Two target units should not verify")
+	}
+	if err := verifyCleanCart("target", []CartItem{{Name: "Other", SKUID: "other", Quantity: 1}}); err == nil {
+		t.Error("Other item should not verify")
+	}
+}
+
+func TestCleanCart(t *testing.T) {
+	fc, cart := newFakeCartCheckout(t, []fakeCartLine{
+		{ID: "line-1", SKUID: "111", Title: "Other Ship", Cents: 4500, Qty: 1},
+		{ID: "line-2", SKUID: "222", Title: "Target Ship", Cents: 2000, Qty: 2},
+	})
+
+	before, err := fc.GetCartTotalsAndItems()
+	if err != nil {
+		t.Fatalf("GetCartTotalsAndItems failed: %v", err)
+	}
+
+	cleaned, err := fc.CleanCart("222", before.Items)
+	if err != nil {
+		t.Fatalf("CleanCart failed: %v", err)
+	}
+
+	if len(cleaned.Items) != 1 || cleaned.Items[0].SKUID != "222" || cleaned.Items[0].Quantity != 1 {
+		t.Errorf("Expected exactly one target unit, got %+v", cleaned.Items)
+	}
+	if !cleaned.Total.Equal(usd(2000)) {
+		t.Errorf("Expected cleaned total $20.00, got %s", cleaned.Total)
+	}
+
+	want := []string{"CombinedCartQuery", "CartRemoveMutation", "CartUpdateQtyMutation", "CombinedCartQuery"}
+	if strings.Join(cart.operations, ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected operations: %v", cart.operations)
+	}
+}
+
+func TestValidateCartContentsClean(t *testing.T) {
+	fc, _ := newFakeCartCheckout(t, []fakeCartLine{
+		{ID: "line-1", SKUID: "111", Title: "Other Ship", Cents: 4500, Qty: 1},
+	})
+	fc.prompter = &ScriptedPrompter{Answers: []PromptAnswer{AnswerClean}}
+
+	cartInfo, err := fc.GetCartTotalsAndItems()
+	if err != nil {
+		t.Fatalf("GetCartTotalsAndItems failed: %v", err)
+	}
+
+	// Target isn't in the cart: cleanup empties it and the target still has to be added
+	shouldAdd, err := fc.ValidateCartContents("222", cartInfo)
+	if err != nil {
+		t.Fatalf("ValidateCartContents failed: %v", err)
+	}
+	if !shouldAdd {
+		t.Error("Expected target to be added after cleaning an unrelated cart")
+	}
+	if len(cartInfo.Items) != 0 || !cartInfo.Total.IsZero() {
+		t.Errorf("Expected cart info to be replaced with the cleaned cart, got %+v", cartInfo)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import "strconv"
+
+// cartActions maps each "specter cart" action to its argument count, the action included
+var cartActions = map[string]int{
+	"show":           1,
+	"clear":          1,
+	"release-credit": 1,
+	"remove":         2,
+	"set-qty":        3,
+	"restore":        2,
+}
+
+// runCartCommand handles "specter cart show|clear|remove|set-qty|release-credit|restore"
+func runCartCommand(env *commandEnv, args []string) error {
+	if len(args) == 0 || cartActions[args[0]] != len(args) {
+		return TError("command_cart_usage")
+	}
+	reporter := env.Reporter
+
+	if args[0] == "restore" {
+		snapshot, err := loadCartSnapshot(args[1])
+		if err != nil {
+			return err
+		}
+		fastCheckout, err := env.Session()
+		if err != nil {
+			return err
+		}
+		return fastCheckout.RestoreCartSnapshot(snapshot)
+	}
+
+	fastCheckout, err := env.Session()
+	if err != nil {
+		return err
+	}
+	cart, err := fastCheckout.GetCartTotalsAndItems()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "show":
+		return showCart(reporter, fastCheckout, cart)
+
+	case "clear":
+		for _, item := range cart.Items {
+			reporter.Info(T("cart_cleanup_removing", "name", item.Name, "quantity", item.Quantity))
+			if err := fastCheckout.RemoveLineItem(item.LineItemID); err != nil {
+				return err
+			}
+		}
+		// Credit left on an empty cart would only be held back from the account
+		if cart.CreditApplied.IsPositive() {
+			if err := releaseCartCredit(reporter, fastCheckout, cart); err != nil {
+				return err
+			}
+		}
+
+	case "release-credit":
+		if !cart.CreditApplied.IsPositive() {
+			reporter.Info(T("cart_command_no_credit_applied"))
+			return nil
+		}
+		if err := releaseCartCredit(reporter, fastCheckout, cart); err != nil {
+			return err
+		}
+
+	case "remove":
+		item, err := findCartLine(cart.Items, args[1])
+		if err != nil {
+			return err
+		}
+		reporter.Info(T("cart_cleanup_removing", "name", item.Name, "quantity", item.Quantity))
+		if err := fastCheckout.RemoveLineItem(item.LineItemID); err != nil {
+			return err
+		}
+
+	case "set-qty":
+		item, err := findCartLine(cart.Items, args[1])
+		if err != nil {
+			return err
+		}
+		qty, err := strconv.Atoi(args[2])
+		if err != nil || qty < 1 {
+			return TError("error_cart_command_bad_quantity", "quantity", args[2])
+		}
+		reporter.Info(T("cart_cleanup_setting_quantity", "name", item.Name, "from", item.Quantity, "to", qty))
+		if err := fastCheckout.SetLineItemQuantity(item.LineItemID, qty); err != nil {
+			return err
+		}
+	}
+
+	// Show the cart as the store has it after the change
+	reporter.Info("")
+	if cart, err = fastCheckout.GetCartTotalsAndItems(); err != nil {
+		return err
+	}
+	return showCart(reporter, fastCheckout, cart)
+}
+
+// showCart prints the line items, totals, applied credit and checkout flow step
+func showCart(reporter Reporter, fastCheckout *FastCheckout, cart *CartInfo) error {
+	step, err := fastCheckout.GetCartFlowStep()
+	if err != nil {
+		return err
+	}
+
+	if len(cart.Items) == 0 {
+		reporter.Info(T("cart_command_empty"))
+	} else {
+		reporter.Info(T("cart_command_header", "count", len(cart.Items)))
+		for i, item := range cart.Items {
+			reporter.Info(T("cart_command_line", "line", i+1, "name", item.Name, "sku", item.SKUID, "qty", item.Quantity, "price", item.Price))
+		}
+	}
+
+	reporter.Info(T("cart_command_total", "total", cart.Total))
+	if cart.Tax.IsPositive() {
+		if cart.TaxIncluded {
+			reporter.Info(T("cart_command_tax_included", "tax", cart.Tax))
+		} else {
+			reporter.Info(T("cart_command_tax_on_top", "tax", cart.Tax))
+		}
+	}
+	reporter.Info(T("cart_command_credit", "credit", cart.CreditApplied, "max_credit", cart.MaxCredit))
+	reporter.Info(T("check_credit_balance", "balance", cart.Ledger))
+	reporter.Info(T("check_flow_step", "step", step))
+	return nil
+}
+
+// releaseCartCredit takes the store credit applied to the cart back to the account
+func releaseCartCredit(reporter Reporter, fastCheckout *FastCheckout, cart *CartInfo) error {
+	reporter.Info(T("cart_command_releasing_credit", "credit", cart.CreditApplied))
+	return fastCheckout.ApplyStoreCredit(newMoney(0, cart.CreditApplied.Currency))
+}
+
+// findCartLine picks a line item by its number in "specter cart show" or by its line id
+func findCartLine(items []CartItem, ref string) (CartItem, error) {
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(items) {
+		return items[n-1], nil
+	}
+	for _, item := range items {
+		if item.LineItemID == ref {
+			return item, nil
+		}
+	}
+	return CartItem{}, TError("error_cart_command_no_line", "line", ref, "count", len(items))
+}
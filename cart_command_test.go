@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFindCartLine(t *testing.T) {
+	withLocale(t, nil)
+
+	items := []CartItem{{LineItemID: "line-a", Name: "First"}, {LineItemID: "line-b", Name: "Second"}}
+	for ref, want := range map[string]string{"1": "First", "2": "Second", "line-b": "Second"} {
+		item, err := findCartLine(items, ref)
+		if err != nil || item.Name != want {
+			t.Errorf("%s: expected %s, got %+v (%v)", ref, want, item, err)
+		}
+	}
+	for _, ref := range []string{"0", "3", "line-c"} {
+		if _, err := findCartLine(items, ref); err == nil {
+			t.Errorf("%s: expected an error", ref)
+		}
+	}
+}
+
+func TestCartCommand(t *testing.T) {
+	withLocale(t, nil)
+
+	fc, cart := newFakeCartCheckout(t, []fakeCartLine{
+		{ID: "line-1", SKUID: "111", Title: "Other Ship", Cents: 4500, Qty: 1},
+		{ID: "line-2", SKUID: "222", Title: "Target Ship", Cents: 2000, Qty: 1},
+	})
+	fc.reporter = &QuietReporter{Out: &bytes.Buffer{}}
+	cart.credit = 2000
+	env := &commandEnv{Config: DefaultConfig(), Reporter: NewReporter(DefaultConfig(), "cart"), fastCheckout: fc}
+
+	for _, args := range [][]string{{}, {"show", "extra"}, {"set-qty", "1"}, {"empty"}} {
+		if err := runCartCommand(env, args); err == nil {
+			t.Errorf("%v: expected a usage error", args)
+		}
+	}
+	if err := runCartCommand(env, []string{"set-qty", "2", "none"}); err == nil {
+		t.Error("Expected an error for a bad quantity")
+	}
+
+	if err := runCartCommand(env, []string{"set-qty", "2", "3"}); err != nil {
+		t.Fatalf("set-qty failed: %v", err)
+	}
+	if cart.lines[1].Qty != 3 {
+		t.Errorf("Expected quantity 3, got %d", cart.lines[1].Qty)
+	}
+
+	if err := runCartCommand(env, []string{"remove", "line-1"}); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if len(cart.lines) != 1 || cart.lines[0].ID != "line-2" {
+		t.Errorf("Unexpected cart after remove: %+v", cart.lines)
+	}
+
+	if err := runCartCommand(env, []string{"release-credit"}); err != nil {
+		t.Fatalf("release-credit failed: %v", err)
+	}
+	if cart.credit != 0 {
+		t.Errorf("Expected credit released, got %d", cart.credit)
+	}
+
+	cart.credit = 1000
+	if err := runCartCommand(env, []string{"clear"}); err != nil {
+		t.Fatalf("clear failed: %v", err)
+	}
+	if len(cart.lines) != 0 || cart.credit != 0 {
+		t.Errorf("Expected an empty cart without credit, got %+v and credit %d", cart.lines, cart.credit)
+	}
+}
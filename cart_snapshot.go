@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cartSnapshotItem is one line of a saved cart
+type cartSnapshotItem struct {
+	SKUID    string `json:"sku_id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"qty"`
+}
+
+// cartSnapshot records what was in the cart before specter changed it, so items
+// parked in the cart by the user can be put back afterwards
+type cartSnapshot struct {
+	TakenAt      time.Time          `json:"taken_at"`
+	TargetSKU    string             `json:"target_sku"`
+	PurchasedSKU string             `json:"purchased_sku,omitempty"` // Set once the target was bought
+	Items        []cartSnapshotItem `json:"items"`
+
+	path    string
+	changed bool // The run removed items or changed quantities since the snapshot
+}
+
+// cartSnapshotDir is where snapshots are kept (~/.specter/cart-snapshots)
+func cartSnapshotDir() string {
+	return filepath.Join(getUserDataDir(), "cart-snapshots")
+}
+
+// needsCartSnapshot reports whether the cart holds anything besides one target unit -
+// an empty cart or one that already matches the purchase has nothing worth restoring
+func needsCartSnapshot(targetSKU string, items []CartItem) bool {
+	if len(items) == 0 {
+		return false
+	}
+	return !(len(items) == 1 && items[0].SKUID == targetSKU && items[0].Quantity == 1)
+}
+
+// newCartSnapshot captures the SKU and quantity of every line item
+func newCartSnapshot(targetSKU string, items []CartItem) *cartSnapshot {
+	snapshot := &cartSnapshot{
+		TakenAt:   time.Now(),
+		TargetSKU: targetSKU,
+	}
+	for _, item := range items {
+		snapshot.Items = append(snapshot.Items, cartSnapshotItem{
+			SKUID:    item.SKUID,
+			Name:     item.Name,
+			Quantity: item.Quantity,
+		})
+	}
+	return snapshot
+}
+
+// save writes the snapshot to dir, or back to the file it was loaded from
+func (s *cartSnapshot) save(dir string) error {
+	if s.path == "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+		s.path = filepath.Join(dir, "cart-"+s.TakenAt.Format("20060102-150405")+".json")
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cart snapshot: %w", err)
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// loadCartSnapshot reads a snapshot written by save
+func loadCartSnapshot(path string) (*cartSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot cartSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse cart snapshot: %w", err)
+	}
+	snapshot.path = path
+	return &snapshot, nil
+}
+
+// restorePlan returns what has to be re-added for the cart to match the snapshot again.
+// Items still in the cart are not added twice, and one unit of the purchased SKU is
+// left out since it was just bought.
+func (s *cartSnapshot) restorePlan(current []CartItem) []cartSnapshotItem {
+	have := make(map[string]int)
+	for _, item := range current {
+		have[item.SKUID] += item.Quantity
+	}
+	if s.PurchasedSKU != "" {
+		have[s.PurchasedSKU]++
+	}
+
+	var missing []cartSnapshotItem
+	for _, item := range s.Items {
+		qty := item.Quantity - have[item.SKUID]
+		if qty <= 0 {
+			have[item.SKUID] -= item.Quantity
+			continue
+		}
+
+		have[item.SKUID] = 0
+		missing = append(missing, cartSnapshotItem{SKUID: item.SKUID, Name: item.Name, Quantity: qty})
+	}
+	return missing
+}
+
+// snapshotCart saves the cart once per run, before anything in it is changed
+func (f *FastCheckout) snapshotCart(targetSKU string, items []CartItem) {
+	if f.cartSnapshot != nil || !needsCartSnapshot(targetSKU, items) {
+		return
+	}
+
+	snapshot := newCartSnapshot(targetSKU, items)
+	if err := snapshot.save(cartSnapshotDir()); err != nil {
+		f.reporter.Warn(T("cart_snapshot_save_failed", "error", err))
+		return
+	}
+
+	f.cartSnapshot = snapshot
+	f.reporter.Info(T("cart_snapshot_saved", "count", len(snapshot.Items), "path", snapshot.path))
+}
+
+// markCartChanged notes that the run is about to change items in the cart, so the
+// snapshot is worth offering back even if the run fails afterwards
+func (f *FastCheckout) markCartChanged() {
+	if f.cartSnapshot != nil {
+		f.cartSnapshot.changed = true
+	}
+}
+
+// cartRestoreDue reports whether a run that ended with err should offer the snapshot
+// back: after every completed run, and after a failed one that changed the cart
+func (f *FastCheckout) cartRestoreDue(err error) bool {
+	return f.cartSnapshot != nil && (err == nil || f.cartSnapshot.changed)
+}
+
+// RestoreCartSnapshot re-adds the snapshot items that are missing from the cart
+func (f *FastCheckout) RestoreCartSnapshot(snapshot *cartSnapshot) error {
+	cartInfo, err := f.GetCartTotalsAndItems()
+	if err != nil {
+		return err
+	}
+
+	missing := snapshot.restorePlan(cartInfo.Items)
+	if len(missing) == 0 {
+		f.reporter.Info(T("cart_restore_nothing_missing"))
+		return nil
+	}
+
+	for _, item := range missing {
+		f.reporter.Info(T("cart_restore_adding", "name", item.Name, "quantity", item.Quantity))
+	}
+	if err := f.AddCartItems(missing); err != nil {
+		return err
+	}
+
+	f.reporter.Info(T("cart_restore_done", "count", len(missing)))
+	return nil
+}
+
+// offerCartRestore asks whether to put the snapshot items back after the run
+func (f *FastCheckout) offerCartRestore(purchased bool) {
+	snapshot := f.cartSnapshot
+	f.cartSnapshot = nil
+
+	if purchased {
+		snapshot.PurchasedSKU = snapshot.TargetSKU
+		if err := snapshot.save(cartSnapshotDir()); err != nil {
+			f.reporter.Warn(T("cart_snapshot_save_failed", "error", err))
+		}
+	}
+
+	f.reporter.Info("")
+	f.reporter.Info(T("cart_restore_offer", "count", len(snapshot.Items), "path", snapshot.path))
+	answer, err := f.prompter.Choose(Prompt{
+		Kind:    PromptCartRestore,
+		Message: T("cart_restore_prompt"),
+		Options: []PromptAnswer{AnswerRestore, AnswerSkip, AnswerAbort},
+	})
+	if err != nil {
+		f.reporter.Warn(T("cart_restore_failed", "error", err))
+		return
+	}
+
+	if answer == AnswerSkip || answer == AnswerAbort {
+		f.reporter.Info(T("cart_restore_skipped", "path", snapshot.path))
+		return
+	}
+
+	if err := f.RestoreCartSnapshot(snapshot); err != nil {
+		f.reporter.Warn(T("cart_restore_failed", "error", err))
+	}
+}
+
+// AddCartItems adds several SKUs in one request (no reCAPTCHA retry loop - this is
+// not time critical)
+func (f *FastCheckout) AddCartItems(items []cartSnapshotItem) error {
+	mutation := operation("AddCartMultiItemMutation")
+
+	query := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		query = append(query, map[string]interface{}{
+			"qty":   item.Quantity,
+			"skuId": item.SKUID,
+		})
+	}
+
+	request := []GraphQLRequest{
+		{
+			OperationName: "AddCartMultiItemMutation",
+			Variables: map[string]interface{}{
+				"query": query,
+			},
+			Query: mutation,
+		},
+	}
+
+	if _, err := f.graphqlRequestWithLoginRetry(request); err != nil {
+		return TError("error_cart_restore_add_failed", "error", err)
+	}
+	return nil
+}
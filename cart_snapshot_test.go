@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNeedsCartSnapshot(t *testing.T) {
+	if needsCartSnapshot("target", nil) {
+		t.Error("Empty cart has nothing to snapshot")
+	}
+	if needsCartSnapshot("target", []CartItem{{SKUID: "target", Quantity: 1}}) {
+		t.Error("Cart holding only the target has nothing to snapshot")
+	}
+	if !needsCartSnapshot("target", []CartItem{{SKUID: "target", Quantity: 2}}) {
+		t.Error("Extra target quantity should be snapshotted")
+	}
+	if !needsCartSnapshot("target", []CartItem{{SKUID: "other", Quantity: 1}}) {
+		t.Error("Unrelated items should be snapshotted")
+	}
+}
+
+func TestCartSnapshotSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	snapshot := newCartSnapshot("222", []CartItem{
+		{Name: "Other Ship", SKUID: "111", Quantity: 2},
+		{Name: "Target Ship", SKUID: "222", Quantity: 1},
+	})
+
+	if err := snapshot.save(dir); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded, err := loadCartSnapshot(snapshot.path)
+	if err != nil {
+		t.Fatalf("loadCartSnapshot failed: %v", err)
+	}
+	if loaded.TargetSKU != "222" || len(loaded.Items) != 2 || loaded.Items[0].Quantity != 2 {
+		t.Errorf("Unexpected snapshot contents: %+v", loaded)
+	}
+
+	// Marking the purchase rewrites the same file
+	loaded.PurchasedSKU = "222"
+	if err := loaded.save(dir); err != nil {
+		t.Fatalf("re-save failed: %v", err)
+	}
+	reloaded, _ := loadCartSnapshot(snapshot.path)
+	if reloaded.PurchasedSKU != "222" {
+		t.Error("Expected purchased SKU to be stored in the snapshot file")
+	}
+}
+
+func TestCartSnapshotRestorePlan(t *testing.T) {
+	snapshot := &cartSnapshot{
+		TargetSKU: "222",
+		Items: []cartSnapshotItem{
+			{SKUID: "111", Name: "Other Ship", Quantity: 2},
+			{SKUID: "222", Name: "Target Ship", Quantity: 2},
+			{SKUID: "333", Name: "Paint", Quantity: 1},
+		},
+	}
+
+	// After a purchase the cart is empty and one target unit was bought
+	snapshot.PurchasedSKU = "222"
+	missing := snapshot.restorePlan(nil)
+	if len(missing) != 3 || missing[0].Quantity != 2 || missing[1].Quantity != 1 || missing[2].Quantity != 1 {
+		t.Errorf("Unexpected restore plan after purchase: %+v", missing)
+	}
+
+	// Items still in the cart are not re-added
+	snapshot.PurchasedSKU = ""
+	missing = snapshot.restorePlan([]CartItem{{SKUID: "111", Quantity: 1}, {SKUID: "222", Quantity: 2}})
+	if len(missing) != 2 || missing[0].SKUID != "111" || missing[0].Quantity != 1 || missing[1].SKUID != "333" {
+		t.Errorf("Unexpected restore plan for partial cart: %+v", missing)
+	}
+}
+
+func TestOfferCartRestoreAfterPurchase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	fc, cart := newFakeCartCheckout(t, nil)
+	fc.prompter = &ScriptedPrompter{Answers: []PromptAnswer{AnswerRestore}}
+
+	fc.snapshotCart("222", []CartItem{
+		{Name: "Other Ship", SKUID: "111", Quantity: 1},
+		{Name: "Target Ship", SKUID: "222", Quantity: 1},
+	})
+	if fc.cartSnapshot == nil {
+		t.Fatal("Expected cart snapshot to be taken")
+	}
+	path := fc.cartSnapshot.path
+
+	fc.offerCartRestore(true)
+
+	if len(cart.lines) != 1 || cart.lines[0].SKUID != "111" {
+		t.Errorf("Expected only the unrelated item to be re-added, got %+v", cart.lines)
+	}
+	if fc.cartSnapshot != nil {
+		t.Error("Snapshot should be consumed after the restore offer")
+	}
+
+	saved, err := loadCartSnapshot(path)
+	if err != nil {
+		t.Fatalf("loadCartSnapshot failed: %v", err)
+	}
+	if saved.PurchasedSKU != "222" {
+		t.Error("Expected snapshot file to record the purchase")
+	}
+}
+
+func TestCartRestoreDueAfterCleanup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	fc, _ := newFakeCartCheckout(t, []fakeCartLine{
+		{ID: "line-1", SKUID: "111", Title: "Other Ship", Cents: 4500, Qty: 1},
+	})
+	fc.prompter = &ScriptedPrompter{Answers: []PromptAnswer{AnswerAbort}}
+
+	cartInfo, err := fc.GetCartTotalsAndItems()
+	if err != nil {
+		t.Fatalf("GetCartTotalsAndItems failed: %v", err)
+	}
+	fc.snapshotCart("222", cartInfo.Items)
+
+	// Aborting leaves the cart as it was: only a completed run offers the snapshot
+	_, err = fc.ValidateCartContents("222", cartInfo)
+	if err == nil || fc.cartRestoreDue(err) {
+		t.Errorf("Abort should not offer a restore (err %v)", err)
+	}
+	if !fc.cartRestoreDue(nil) {
+		t.Error("A completed run should offer the restore")
+	}
+
+	// Once cleanup has removed items, a later failure still offers them back
+	if _, err := fc.CleanCart("222", cartInfo.Items); err != nil {
+		t.Fatalf("CleanCart failed: %v", err)
+	}
+	if !fc.cartRestoreDue(errors.New("validation timed out")) {
+		t.Error("A failed run that cleaned the cart should offer the restore")
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cassetteInteraction is one recorded GraphQL round trip
+type cassetteInteraction struct {
+	Operation       string        `json:"operation"`
+	Request         string        `json:"request"`
+	RequestHeaders  http.Header   `json:"request_headers"`
+	Status          int           `json:"status,omitempty"`
+	ResponseHeaders http.Header   `json:"response_headers,omitempty"`
+	Response        string        `json:"response,omitempty"`
+	Error           string        `json:"error,omitempty"` // Transport error instead of a response
+	Duration        time.Duration `json:"duration_ns"`
+}
+
+// cassette records store traffic to a file, or serves it back from one. Replay
+// answers each operation with its next unused recording, so a sequence such as
+// several 4226 errors followed by a success plays back exactly as it happened.
+//
+// A recording is written as NDJSON: a header line with recorded_at, then one line per
+// interaction, appended as it happens. Cassettes written as a single JSON document
+// with an "interactions" array load as well.
+type cassette struct {
+	RecordedAt   time.Time             `json:"recorded_at"`
+	Interactions []cassetteInteraction `json:"interactions,omitempty"`
+
+	mu       sync.Mutex
+	path     string
+	reporter Reporter // Warns when the recording can't be written
+	file     *os.File // Recording target, opened on the first interaction
+	closed   bool     // Close ran; later interactions are appended to the finished file
+	replay   bool
+	used     []bool
+}
+
+// newCassetteRecorder starts an empty cassette that is written to path as it grows
+func newCassetteRecorder(path string, reporter Reporter) *cassette {
+	return &cassette{RecordedAt: time.Now().UTC(), path: path, reporter: reporter}
+}
+
+// loadCassette reads a cassette for replay
+func loadCassette(path string) (*cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, TError("error_cassette_read", "error", err)
+	}
+
+	c := &cassette{path: path, replay: true}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(c); err != nil {
+		return nil, TError("error_cassette_parse", "path", path, "error", err)
+	}
+	for decoder.More() {
+		var interaction cassetteInteraction
+		if err := decoder.Decode(&interaction); err != nil {
+			return nil, TError("error_cassette_parse", "path", path, "error", err)
+		}
+		c.Interactions = append(c.Interactions, interaction)
+	}
+	c.used = make([]bool, len(c.Interactions))
+	return c, nil
+}
+
+// newCassetteFromConfig opens the cassette selected in config, if any
+func newCassetteFromConfig(config *Config, reporter Reporter) (*cassette, error) {
+	switch {
+	case config.RecordCassette != "" && config.ReplayCassette != "":
+		return nil, errors.New(T("error_cassette_both_modes"))
+	case config.ReplayCassette != "":
+		return loadCassette(config.ReplayCassette)
+	case config.RecordCassette != "":
+		return newCassetteRecorder(config.RecordCassette, reporter), nil
+	}
+	return nil, nil
+}
+
+// roundTrip sends one GraphQL request through the cassette: replayed from the file,
+// or sent to the store and recorded
+func (c *cassette) roundTrip(client *http.Client, req *http.Request, body []byte, operation string) (*http.Response, error) {
+	if c.replay {
+		return c.next(operation)
+	}
+
+	started := time.Now()
+	resp, err := client.Do(req)
+
+	interaction := cassetteInteraction{
+		Operation:      operation,
+		Request:        redactText(string(body)),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+
+	if err == nil {
+		var data []byte
+		data, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+
+		interaction.Status = resp.StatusCode
+		interaction.ResponseHeaders = redactHeaders(resp.Header)
+		interaction.Response = redactText(string(data))
+	}
+	if err != nil {
+		interaction.Error = err.Error()
+	}
+	interaction.Duration = time.Since(started)
+
+	c.append(interaction)
+	return resp, err
+}
+
+// append writes one interaction to the end of the file. Each write is a single line,
+// so a crash mid-run keeps everything recorded so far and a retry storm costs one
+// small write per attempt.
+func (c *cassette) append(interaction cassetteInteraction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.write(interaction); err != nil {
+		c.reporter.Warn(T("cassette_save_failed", "error", err))
+	}
+}
+
+// write appends a line, creating the file with its header first (caller holds mu)
+func (c *cassette) write(interaction cassetteInteraction) error {
+	if c.file == nil && c.closed {
+		file, err := os.OpenFile(c.path, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		c.file, c.closed = file, false
+	}
+	if c.file == nil {
+		if dir := filepath.Dir(c.path); dir != "" {
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				return err
+			}
+		}
+		file, err := os.OpenFile(c.path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		header, err := json.Marshal(c)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := file.Write(append(header, '\n')); err != nil {
+			file.Close()
+			return err
+		}
+		c.file = file
+	}
+
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+	_, err = c.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close flushes the recording to disk and closes the file. Replay cassettes and
+// recordings that never saw a request have nothing to close.
+func (c *cassette) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Sync()
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	c.file, c.closed = nil, true
+	return err
+}
+
+// next replays the first unused recording of operation
+func (c *cassette) next(operation string) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.Interactions {
+		if c.used[i] || interaction.Operation != operation {
+			continue
+		}
+		c.used[i] = true
+
+		if interaction.Error != "" {
+			return nil, errors.New(interaction.Error)
+		}
+
+		header := interaction.ResponseHeaders
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			StatusCode: interaction.Status,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(interaction.Response)),
+		}, nil
+	}
+
+	return nil, TError("error_cassette_exhausted", "operation", operation, "path", c.path)
+}
+
+// remaining counts the recordings replay has not used yet
+func (c *cassette) remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := 0
+	for _, used := range c.used {
+		if !used {
+			count++
+		}
+	}
+	return count
+}
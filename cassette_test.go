@@ -0,0 +1,168 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newReplayCheckout returns a FastCheckout answered from a cassette in testdata
+// with retry delays short enough for tests
+func newReplayCheckout(t *testing.T, name string) *FastCheckout {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.ReplayCassette = filepath.Join("testdata", "cassettes", name)
+	config.Payment4226MinMs = 1
+	config.Payment4226MaxMs = 1
+	config.OutOfStockDelayMs = 1
+	config.GenericErrorDelayMs = 1
+
+	fc, err := NewFastCheckout(config)
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	return fc
+}
+
+func TestCassetteRecordRedacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "Rsi-Token=server-secret")
+		w.Write([]byte(`[{"data":{"customer":{"email":"pilot@example.com"},"store":{"cart":{"flow":{"steps":[{"step":"cart","active":true}]}}}}}]`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "run.json")
+	config := DefaultConfig()
+	config.RecordCassette = path
+
+	fc, err := NewFastCheckout(config)
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.graphqlURL = server.URL
+	fc.csrfToken = "csrf-secret"
+	fc.cookies = []*http.Cookie{{Name: "Rsi-Token", Value: "cookie-secret"}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := fc.GetCartFlowStep(); err != nil {
+			t.Fatalf("GetCartFlowStep failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cassette not written: %v", err)
+	}
+	for _, secret := range []string{"csrf-secret", "cookie-secret", "server-secret", "pilot@example.com"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette leaks %q:\n%s", secret, data)
+		}
+	}
+
+	// Closing flushes the file; a request after that is appended, not a new recording
+	if err := fc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := fc.GetCartFlowStep(); err != nil {
+		t.Fatalf("GetCartFlowStep after Close failed: %v", err)
+	}
+	if err := fc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cassette not written: %v", err)
+	}
+
+	// A header line, then one line per interaction
+	if lines := strings.Count(string(data), "\n"); lines != 4 {
+		t.Errorf("Expected 4 lines, got %d:\n%s", lines, data)
+	}
+
+	recorded, err := loadCassette(path)
+	if err != nil {
+		t.Fatalf("loadCassette failed: %v", err)
+	}
+	if len(recorded.Interactions) != 3 || recorded.Interactions[2].Operation != "CartFlowQuery" || recorded.Interactions[2].Status != 200 {
+		t.Fatalf("Unexpected recording: %+v", recorded.Interactions)
+	}
+
+	// The recording answers the same query without the server
+	replay := &FastCheckout{config: DefaultConfig(), client: http.DefaultClient, cassette: recorded, reporter: NewReporter(DefaultConfig(), "checkout")}
+	for i := 0; i < 3; i++ {
+		step, err := replay.GetCartFlowStep()
+		if err != nil || step != "cart" {
+			t.Errorf("Replay returned %q, %v", step, err)
+		}
+	}
+	if _, err := replay.GetCartFlowStep(); err == nil {
+		t.Error("Expected an error once the cassette is used up")
+	}
+}
+
+func TestCassetteBothModes(t *testing.T) {
+	config := DefaultConfig()
+	config.RecordCassette = "a.json"
+	config.ReplayCassette = "b.json"
+	if _, err := NewFastCheckout(config); err == nil {
+		t.Error("Expected record and replay together to be rejected")
+	}
+}
+
+func TestReplay4226Storm(t *testing.T) {
+	fc := newReplayCheckout(t, "validate-4226-storm.json")
+
+	err := fc.ValidateCartWithDeadline(nil, time.Now().Add(5*time.Second))
+	if err != nil {
+		t.Fatalf("Expected validation to succeed after the storm: %v", err)
+	}
+	if fc.lastOrderSlug != "ORD-4226-OK" {
+		t.Errorf("Expected order slug from the final response, got %q", fc.lastOrderSlug)
+	}
+	if remaining := fc.cassette.remaining(); remaining != 0 {
+		t.Errorf("Expected every recorded attempt to be replayed, %d left", remaining)
+	}
+}
+
+func TestReplayOutOfStockFlip(t *testing.T) {
+	fc := newReplayCheckout(t, "validate-out-of-stock.json")
+
+	err := fc.ValidateCartWithDeadline(nil, time.Now().Add(5*time.Second))
+	if err != nil {
+		t.Fatalf("Expected validation to succeed once back in stock: %v", err)
+	}
+	if fc.lastOrderSlug != "ORD-RESTOCK-OK" {
+		t.Errorf("Expected order slug from the restock response, got %q", fc.lastOrderSlug)
+	}
+	if remaining := fc.cassette.remaining(); remaining != 0 {
+		t.Errorf("Expected every recorded attempt to be replayed, %d left", remaining)
+	}
+}
+
+func TestReplayMidRunLogout(t *testing.T) {
+	fc := newReplayCheckout(t, "validate-mid-run-logout.json")
+	prompter := &ScriptedPrompter{Answers: []PromptAnswer{AnswerProceed}}
+	fc.prompter = prompter
+
+	// The logout interrupts a 4226 storm; with no browser to log in again the attempt
+	// fails and the next one goes through on the recorded session
+	err := fc.ValidateCartWithDeadline(nil, time.Now().Add(5*time.Second))
+	if err != nil {
+		t.Fatalf("Expected validation to succeed after the logout: %v", err)
+	}
+	if len(prompter.Asked) != 1 || prompter.Asked[0] != PromptSessionExpired {
+		t.Errorf("Expected one session expired prompt, got %v", prompter.Asked)
+	}
+	if fc.lastOrderSlug != "ORD-RELOGIN-OK" {
+		t.Errorf("Expected order slug from the response after the logout, got %q", fc.lastOrderSlug)
+	}
+	if remaining := fc.cassette.remaining(); remaining != 0 {
+		t.Errorf("Expected every recorded attempt to be replayed, %d left", remaining)
+	}
+}
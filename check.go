@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// promptPolicyAnswers lists the answers each on_* option accepts
+var promptPolicyAnswers = map[string][]PromptAnswer{
+	"on_login_required":  {AnswerProceed, AnswerAbort},
+	"on_session_expired": {AnswerProceed, AnswerAbort},
+	"on_cart_mismatch":   {AnswerProceed, AnswerClean, AnswerAbort},
+	"on_cart_restore":    {AnswerRestore, AnswerSkip},
+}
+
+// configProblems lists what would stop a run, or make it misbehave, in the config as it
+// stands. Nothing here touches the network.
+func configProblems(config *Config) []string {
+	var problems []string
+
+	if len(config.SaleWindows) == 0 {
+		problems = append(problems, T("check_no_sale_windows"))
+	}
+	if _, err := planWaves(config); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if config.ItemURL == "" && !config.SkipAddToCart {
+		problems = append(problems, T("check_no_item_url"))
+	}
+	if config.PreWaveActivationMinutes < 0 || config.PostWaveTimeoutMinutes < 0 {
+		problems = append(problems, T("check_negative_wave_timing"))
+	}
+	if config.PollingDelayMinMs > config.PollingDelayMaxMs {
+		problems = append(problems, T("check_delay_range", "option", "polling_delay", "min", config.PollingDelayMinMs, "max", config.PollingDelayMaxMs))
+	}
+	if config.RetryDelayMinMs > config.RetryDelayMaxMs {
+		problems = append(problems, T("check_delay_range", "option", "retry_delay", "min", config.RetryDelayMinMs, "max", config.RetryDelayMaxMs))
+	}
+	if !validOutputMode(config.Output) {
+		problems = append(problems, T("error_output_mode_invalid", "mode", config.Output))
+	}
+	if !validLogLevel(config.LogLevel) {
+		problems = append(problems, T("error_log_level_invalid", "level", config.LogLevel))
+	}
+
+	return append(problems, promptPolicyProblems(config)...)
+}
+
+// promptPolicyProblems lists the on_* options set to an answer their prompt doesn't
+// offer. A policy is only consulted when its prompt fires, so this runs at startup.
+func promptPolicyProblems(config *Config) []string {
+	var problems []string
+	policies := map[string]string{
+		"on_login_required":  config.OnLoginRequired,
+		"on_session_expired": config.OnSessionExpired,
+		"on_cart_mismatch":   config.OnCartMismatch,
+		"on_cart_restore":    config.OnCartRestore,
+	}
+	for _, option := range sortedKeys(policies) {
+		answer := policies[option]
+		if answer == "" {
+			continue
+		}
+		allowed := Prompt{Options: promptPolicyAnswers[option]}
+		if !allowed.hasOption(PromptAnswer(answer)) {
+			problems = append(problems, T("check_prompt_policy_invalid", "option", option, "answer", answer, "allowed", fmt.Sprint(promptPolicyAnswers[option])))
+		}
+	}
+	return problems
+}
+
+// runConfigCommand handles "specter config [check]": the effective configuration, with
+// flags applied, or the problems found in it
+func runConfigCommand(env *commandEnv, args []string) error {
+	switch {
+	case len(args) == 0:
+		data, err := yaml.Marshal(env.Config)
+		if err != nil {
+			return err
+		}
+		env.Reporter.Info(strings.TrimRight(string(data), "\n"))
+		return nil
+	case len(args) == 1 && args[0] == "check":
+		problems := configProblems(env.Config)
+		for _, problem := range problems {
+			env.Reporter.Warn(T("check_problem", "problem", problem))
+		}
+		if len(problems) > 0 {
+			return TError("check_problems_found", "count", len(problems))
+		}
+		env.Reporter.Info(T("check_config_ok"))
+		return nil
+	default:
+		return TError("command_config_usage")
+	}
+}
+
+// runCheckCommand handles "specter check": a pre-flight of everything a run depends on,
+// from the config to the clock, the session, the cart and the target item
+func runCheckCommand(env *commandEnv, args []string) error {
+	if len(args) > 0 {
+		return TError("command_check_usage")
+	}
+	config, reporter := env.Config, env.Reporter
+
+	problems := 0
+	problem := func(text string) {
+		problems++
+		reporter.Warn(T("check_problem", "problem", text))
+	}
+
+	reporter.Info(T("check_section_config"))
+	configIssues := configProblems(config)
+	for _, text := range configIssues {
+		problem(text)
+	}
+	if len(configIssues) == 0 {
+		reporter.Info(T("check_config_ok"))
+	}
+	if waves, err := planWaves(config); err == nil && len(waves) > 0 {
+		upcoming := 0
+		for _, wave := range waves {
+			if wave.Status(time.Now()) != WavePassed {
+				upcoming++
+			}
+		}
+		if upcoming == 0 {
+			problem(T("multiwave_all_waves_passed"))
+		} else {
+			reporter.Info(T("check_waves_ahead", "count", upcoming, "total", len(waves)))
+		}
+	}
+
+	reporter.Info("")
+	reporter.Info(T("check_section_clock"))
+	timeSync := NewTimeSync(config.DebugMode)
+	if err := timeSync.Sync(); err != nil {
+		problem(err.Error())
+	} else {
+		reporter.Info(T("check_clock_ok", "offset", timeSync.GetOffset(), "bound", timeSync.GetErrorBound()))
+	}
+
+	reporter.Info("")
+	reporter.Info(T("check_section_session"))
+	fastCheckout, err := env.Session()
+	if err != nil {
+		problem(err.Error())
+		return TError("check_problems_found", "count", problems)
+	}
+	if err := fastCheckout.ValidateSession(); err != nil {
+		problem(T("check_session_invalid", "error", err))
+		return TError("check_problems_found", "count", problems)
+	}
+	reporter.Info(T("check_session_ok"))
+
+	reporter.Info("")
+	reporter.Info(T("check_section_cart"))
+	if cart, err := fastCheckout.GetCartTotalsAndItems(); err != nil {
+		problem(err.Error())
+	} else {
+		reporter.Info(T("check_cart_summary", "count", len(cart.Items), "total", cart.Total, "credit", cart.CreditApplied))
+		reporter.Info(T("check_credit_balance", "balance", cart.Ledger))
+	}
+	if step, err := fastCheckout.GetCartFlowStep(); err != nil {
+		problem(err.Error())
+	} else {
+		reporter.Info(T("check_flow_step", "step", step))
+	}
+
+	if config.ItemURL != "" {
+		reporter.Info("")
+		reporter.Info(T("check_section_item"))
+		if skuID, err := fastCheckout.GetSKUFromURL(config.ItemURL); err != nil {
+			problem(err.Error())
+		} else {
+			reporter.Info(T("check_item_ok", "sku", skuID))
+		}
+	}
+
+	reporter.Info("")
+	if problems > 0 {
+		return TError("check_problems_found", "count", problems)
+	}
+	reporter.Info(T("check_all_ok"))
+	return nil
+}
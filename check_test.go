@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigProblems(t *testing.T) {
+	withLocale(t, nil)
+
+	config := DefaultConfig()
+	config.ItemURL = "https://example.com/item"
+	config.SaleWindows = []string{"2026-03-07 18:00"}
+	if problems := configProblems(config); len(problems) != 0 {
+		t.Errorf("Expected no problems, got %v", problems)
+	}
+
+	config.ItemURL = ""
+	config.SaleWindows = nil
+	config.PollingDelayMinMs = 500
+	config.OnCartMismatch = "clean"
+	config.OnCartRestore = "clean"
+	config.Output = "fancy"
+
+	problems := configProblems(config)
+	for _, want := range []string{"check_no_sale_windows", "check_no_item_url", "check_delay_range option=polling_delay", "check_prompt_policy_invalid option=on_cart_restore", "error_output_mode_invalid"} {
+		found := false
+		for _, problem := range problems {
+			found = found || strings.HasPrefix(problem, want)
+		}
+		if !found {
+			t.Errorf("Expected a problem starting with %q, got %v", want, problems)
+		}
+	}
+	if len(problems) != 5 {
+		t.Errorf("Expected 5 problems, got %d: %v", len(problems), problems)
+	}
+}
+
+func TestPromptPolicyProblems(t *testing.T) {
+	withLocale(t, nil)
+
+	config := DefaultConfig()
+	config.OnCartMismatch = "clean"
+	config.OnCartRestore = "restore"
+	if problems := promptPolicyProblems(config); len(problems) != 0 {
+		t.Errorf("Expected valid policies, got %v", problems)
+	}
+
+	config.OnSessionExpired = "procede"
+	problems := promptPolicyProblems(config)
+	if len(problems) != 1 || !strings.HasPrefix(problems[0], "check_prompt_policy_invalid option=on_session_expired") {
+		t.Errorf("Expected the on_session_expired typo to be reported, got %v", problems)
+	}
+}
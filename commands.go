@@ -0,0 +1,483 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// cliCommand is one "specter <command>" entry point. Every command takes the common
+// flags (config, language, output, debug, cassettes); Flags adds its own.
+type cliCommand struct {
+	Name    string
+	Usage   func() string // Usage line, e.g. "usage: specter cart restore <snapshot.json>"
+	Summary func() string // One line for "specter help"
+
+	// Flags registers the command's own flags. The returned function, if any, applies
+	// them to the config once they're parsed.
+	Flags func(fs *flag.FlagSet) func(config *Config)
+	Run   func(env *commandEnv, args []string) error
+}
+
+// cliCommands lists the commands in the order "specter help" shows them. run is the
+// default when no command is given.
+func cliCommands() []*cliCommand {
+	return []*cliCommand{
+		{Name: "run", Usage: func() string { return T("command_run_usage") }, Summary: func() string { return T("command_run_summary") }, Flags: runFlags, Run: runCheckoutCommand},
+		{Name: "check", Usage: func() string { return T("command_check_usage") }, Summary: func() string { return T("command_check_summary") }, Flags: waveTimingFlags, Run: runCheckCommand},
+		{Name: "waves", Usage: func() string { return T("command_waves_usage") }, Summary: func() string { return T("command_waves_summary") }, Flags: waveTimingFlags, Run: runWavesCommand},
+		{Name: "cart", Usage: func() string { return T("command_cart_usage") }, Summary: func() string { return T("command_cart_summary") }, Run: runCartCommand},
+		{Name: "credit", Usage: func() string { return T("command_credit_usage") }, Summary: func() string { return T("command_credit_summary") }, Run: runCreditCommand},
+		{Name: "sku", Usage: func() string { return T("command_sku_usage") }, Summary: func() string { return T("command_sku_summary") }, Run: runSKUCommand},
+		{Name: "orders", Usage: func() string { return T("command_orders_usage") }, Summary: func() string { return T("command_orders_summary") }, Run: runOrdersCommand},
+		{Name: "report", Usage: func() string { return T("command_report_usage") }, Summary: func() string { return T("command_report_summary") }, Run: runReportCommand},
+		{Name: "config", Usage: func() string { return T("command_config_usage") }, Summary: func() string { return T("command_config_summary") }, Run: runConfigCommand},
+		{Name: "probe", Usage: func() string { return T("command_probe_usage") }, Summary: func() string { return T("command_probe_summary") }, Run: runProbeCommand},
+		{Name: "i18n", Usage: func() string { return T("command_i18n_usage") }, Summary: func() string { return T("command_i18n_summary") }, Run: runI18nCommand},
+		{Name: "help", Usage: func() string { return T("command_help_usage") }, Summary: func() string { return T("command_help_summary") }, Run: runHelpCommand},
+	}
+}
+
+func findCommand(name string) *cliCommand {
+	for _, cmd := range cliCommands() {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// commonOptions are the flags every command takes
+type commonOptions struct {
+	configPath string
+	lang       string
+	output     string
+	debug      bool
+	unattended bool
+	record     string
+	replay     string
+}
+
+func addCommonFlags(fs *flag.FlagSet) *commonOptions {
+	o := &commonOptions{}
+	fs.StringVar(&o.configPath, "config", "config.yaml", "Path to configuration file")
+	fs.StringVar(&o.lang, "lang", "", "Language of messages, e.g. en_US or ru_RU (overrides config and system language)")
+	fs.StringVar(&o.output, "output", "", "How progress is printed: human, json (one object per line), quiet or tui (overrides config)")
+	fs.BoolVar(&o.debug, "debug", false, "Enable detailed debug logging")
+	fs.BoolVar(&o.unattended, "unattended", false, "Never wait for input: fail fast on prompts without a configured policy")
+	fs.StringVar(&o.record, "record", "", "Record store GraphQL traffic into a redacted cassette file")
+	fs.StringVar(&o.replay, "replay", "", "Answer store GraphQL requests from a recorded cassette file")
+	return o
+}
+
+func (o *commonOptions) apply(config *Config) {
+	if o.lang != "" {
+		config.Language = o.lang
+	}
+	if o.output != "" {
+		config.Output = o.output
+	}
+	if o.debug {
+		config.DebugMode = true
+	}
+	if o.unattended {
+		config.Unattended = true
+	}
+	if o.record != "" {
+		config.RecordCassette = o.record
+	}
+	if o.replay != "" {
+		config.ReplayCassette = o.replay
+	}
+}
+
+// waveTimingFlags adds --pre-wave and --post-wave, which always set the wave timing
+func waveTimingFlags(fs *flag.FlagSet) func(config *Config) {
+	preWaveMinutes := fs.Int("pre-wave", 2, "Minutes before wave to start polling (default: 2)")
+	postWaveMinutes := fs.Int("post-wave", 5, "Minutes after wave to timeout (default: 5)")
+	return func(config *Config) {
+		config.PreWaveActivationMinutes = *preWaveMinutes
+		config.PostWaveTimeoutMinutes = *postWaveMinutes
+	}
+}
+
+// newCommandFlags builds the flag set of a command, with its usage as help text
+func newCommandFlags(cmd *cliCommand) (*flag.FlagSet, *commonOptions, func(config *Config)) {
+	fs := flag.NewFlagSet("specter "+cmd.Name, flag.ExitOnError)
+	common := addCommonFlags(fs)
+	var apply func(config *Config)
+	if cmd.Flags != nil {
+		apply = cmd.Flags(fs)
+	}
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintln(out, cmd.Usage())
+		fmt.Fprintln(out, cmd.Summary())
+		fmt.Fprintln(out)
+		fs.PrintDefaults()
+	}
+	return fs, common, apply
+}
+
+// runCLI parses "specter [command] [flags] [arguments]" and runs the command. Without a
+// command the checkout runs, so "specter --dry-run" works as it always has; a command
+// named after the flags ("specter --debug cart restore x") is run too.
+func runCLI(args []string) (string, error) {
+	// Usage and help use the system language until the config is read
+	InitLocale("")
+
+	cmd, explicit := findCommand("run"), false
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if cmd = findCommand(args[0]); cmd == nil {
+			return args[0], TError("command_unknown", "command", args[0])
+		}
+		args, explicit = args[1:], true
+	}
+
+	fs, common, apply := newCommandFlags(cmd)
+	fs.Parse(args)
+
+	if !explicit && fs.NArg() > 0 {
+		name := fs.Arg(0)
+		if findCommand(name) == nil {
+			return name, TError("command_unknown", "command", name)
+		}
+		at := len(args) - fs.NArg()
+		reordered := append([]string{name}, args[:at]...)
+		return runCLI(append(reordered, args[at+1:]...))
+	}
+
+	env, err := bootstrap(cmd, common, apply)
+	if err != nil {
+		return cmd.Name, err
+	}
+	defer env.Close()
+
+	if err := cmd.Run(env, fs.Args()); err != nil {
+		// The failure goes to the log file too, so "specter report" can show it
+		traceLogger.Error(err.Error(), "component", cmd.Name)
+		return cmd.Name, err
+	}
+	return cmd.Name, nil
+}
+
+// commandEnv is what a command runs with: the config, a reporter and, once asked for,
+// a store session. Close releases whatever was opened.
+type commandEnv struct {
+	Config   *Config
+	Reporter Reporter
+
+	fastCheckout *FastCheckout
+	automation   *Automation
+	logFile      io.Closer
+}
+
+// bootstrap loads the config with the command's flags applied, then sets up the
+// language, store operations and logging every command shares
+func bootstrap(cmd *cliCommand, common *commonOptions, apply func(config *Config)) (*commandEnv, error) {
+	config, err := LoadConfig(common.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	common.apply(config)
+	if apply != nil {
+		apply(config)
+	}
+
+	if err := InitLocale(config.Language); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Locale initialization failed, using default English: %v\n", err)
+	}
+
+	// Check for user data directory permission issues (after locale is loaded)
+	checkUserDataDirPermissions()
+
+	if !validOutputMode(config.Output) {
+		return nil, TError("error_output_mode_invalid", "mode", config.Output)
+	}
+	if !validLogLevel(config.LogLevel) {
+		return nil, TError("error_log_level_invalid", "level", config.LogLevel)
+	}
+	// A bad on_* answer would otherwise only surface when its prompt fires mid-wave;
+	// "config check" and "check" list it among the other problems instead
+	if cmd.Name != "config" && cmd.Name != "check" {
+		if problems := promptPolicyProblems(config); len(problems) > 0 {
+			return nil, errors.New(problems[0])
+		}
+	}
+	// The dashboard is for watching a checkout; other commands print as usual
+	if cmd.Name != "run" && config.Output == OutputTUI {
+		config.Output = OutputHuman
+	}
+
+	env := &commandEnv{Config: config}
+
+	// The log file is best-effort: a command goes ahead without it
+	if env.logFile, err = InitLogging(config); err != nil {
+		fmt.Fprintln(os.Stderr, T("log_file_unavailable", "error", err))
+	}
+
+	env.Reporter = NewReporter(config, cmd.Name)
+
+	// Hot-patched store operations (~/.specter/graphql/<OperationName>.graphql)
+	loadOperationOverrides(graphqlOverrideDir(), env.Reporter)
+	return env, nil
+}
+
+// Session logs in for a command that only talks to the store: the cached session while
+// it's still valid, otherwise the usual browser login
+func (e *commandEnv) Session() (*FastCheckout, error) {
+	if e.fastCheckout != nil {
+		return e.fastCheckout, nil
+	}
+
+	fastCheckout, err := NewFastCheckout(e.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize fast checkout: %w", err)
+	}
+	defer e.closeUnused(fastCheckout)
+
+	if e.Config.SessionCache {
+		e.Reporter.Info(T("session_cache_checking"))
+		restored, err := fastCheckout.RestoreCachedSession()
+		if err != nil {
+			e.Reporter.Warn(T("session_cache_load_failed", "error", err))
+		} else if restored {
+			e.fastCheckout = fastCheckout
+			return fastCheckout, nil
+		}
+	}
+
+	// Only the store session is needed, not the product page
+	itemURL := e.Config.ItemURL
+	e.Config.ItemURL = ""
+	defer func() { e.Config.ItemURL = itemURL }()
+
+	e.automation = NewAutomation(e.Config)
+	if err := e.automation.setupBrowser(); err != nil {
+		return nil, fmt.Errorf("failed to setup browser: %w", err)
+	}
+	if err := e.automation.waitForLogin(); err != nil {
+		return nil, fmt.Errorf("failed to wait for login: %w", err)
+	}
+	if err := fastCheckout.LoadSessionFromBrowser(e.automation); err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	e.fastCheckout = fastCheckout
+	return fastCheckout, nil
+}
+
+// Browser logs in through the browser, which stays open on the product page for the
+// checkout. A valid cached session skips the login itself.
+func (e *commandEnv) Browser() (*FastCheckout, *Automation, error) {
+	fastCheckout, err := NewFastCheckout(e.Config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize fast checkout: %w", err)
+	}
+	defer e.closeUnused(fastCheckout)
+
+	e.automation = NewAutomation(e.Config)
+	automation := e.automation
+
+	// A valid cached session lets a restart skip the browser login entirely
+	if e.Config.SessionCache {
+		e.Reporter.Info(T("session_cache_checking"))
+		restored, err := fastCheckout.RestoreCachedSession()
+		if err != nil {
+			e.Reporter.Warn(T("session_cache_load_failed", "error", err))
+		} else if restored {
+			automation.restoredCookies = fastCheckout.cookies
+		}
+	}
+
+	if err := automation.setupBrowser(); err != nil {
+		return nil, nil, fmt.Errorf("failed to setup browser: %w", err)
+	}
+	if err := automation.waitForLogin(); err != nil {
+		return nil, nil, fmt.Errorf("failed to wait for login: %w", err)
+	}
+
+	// Capture the fresh login right away so a restart before the first wave can reuse it
+	if e.Config.SessionCache && len(automation.restoredCookies) == 0 {
+		if err := fastCheckout.LoadSessionFromBrowser(automation); err != nil {
+			e.Reporter.Warn(T("session_cache_save_failed", "error", err))
+		}
+	}
+
+	e.fastCheckout = fastCheckout
+	return fastCheckout, automation, nil
+}
+
+// closeUnused closes a checkout that failed to log in and so never became the
+// command's session, so a recording it started is not left open
+func (e *commandEnv) closeUnused(fastCheckout *FastCheckout) {
+	if e.fastCheckout != fastCheckout {
+		if err := fastCheckout.Close(); err != nil {
+			e.Reporter.Warn(T("cassette_save_failed", "error", err))
+		}
+	}
+}
+
+// Close shuts the browser, flushes a cassette being recorded, gives the terminal back
+// and closes the log file
+func (e *commandEnv) Close() {
+	if e.automation != nil {
+		e.automation.Close()
+	}
+	if e.fastCheckout != nil {
+		if err := e.fastCheckout.Close(); err != nil {
+			e.Reporter.Warn(T("cassette_save_failed", "error", err))
+		}
+	}
+	closeReporter(e.Reporter)
+	if e.logFile != nil {
+		e.logFile.Close()
+	}
+}
+
+// runHelpCommand handles "specter help [command]"
+func runHelpCommand(env *commandEnv, args []string) error {
+	if len(args) == 1 {
+		cmd := findCommand(args[0])
+		if cmd == nil {
+			return TError("command_unknown", "command", args[0])
+		}
+		var usage strings.Builder
+		fs, _, _ := newCommandFlags(cmd)
+		fs.SetOutput(&usage)
+		fs.Usage()
+		env.Reporter.Info(strings.TrimRight(usage.String(), "\n"))
+		return nil
+	}
+	if len(args) > 1 {
+		return TError("command_help_usage")
+	}
+
+	env.Reporter.Info(T("help_header"))
+	env.Reporter.Info("")
+	for _, cmd := range cliCommands() {
+		env.Reporter.Info(fmt.Sprintf("  %-8s %s", cmd.Name, cmd.Summary()))
+	}
+	env.Reporter.Info("")
+	env.Reporter.Info(T("help_footer"))
+	return nil
+}
+
+// runProbeCommand handles "specter probe [update]": a read-only check of the store
+// API against the stored baseline
+func runProbeCommand(env *commandEnv, args []string) error {
+	update := false
+	switch {
+	case len(args) == 0:
+	case len(args) == 1 && args[0] == "update":
+		update = true
+	default:
+		return TError("command_probe_usage")
+	}
+
+	fastCheckout, err := env.Session()
+	if err != nil {
+		return err
+	}
+
+	// The listing queries need the product's slug
+	slug := ""
+	if itemURL := env.Config.ItemURL; itemURL != "" {
+		if slug, err = fastCheckout.GetSKUSlugFromURL(itemURL); err != nil {
+			env.Reporter.Warn(T("probe_slug_failed", "error", err))
+		}
+	}
+
+	env.Reporter.Info("")
+	env.Reporter.Info(T("probe_start"))
+	problems, err := fastCheckout.RunProbe(slug, probeBaselinePath(), update)
+	if err != nil {
+		return err
+	}
+	if problems > 0 {
+		return TError("probe_problems_found", "count", problems)
+	}
+
+	env.Reporter.Info(T("probe_no_drift"))
+	return nil
+}
+
+// runSKUCommand handles "specter sku [url]": the SKU behind a product page, the
+// configured item by default
+func runSKUCommand(env *commandEnv, args []string) error {
+	itemURL := env.Config.ItemURL
+	switch {
+	case len(args) == 1:
+		itemURL = args[0]
+	case len(args) > 1 || itemURL == "":
+		return TError("command_sku_usage")
+	}
+
+	fastCheckout, err := env.Session()
+	if err != nil {
+		return err
+	}
+	slug, err := fastCheckout.GetSKUSlugFromURL(itemURL)
+	if err != nil {
+		return err
+	}
+	skuID, err := fastCheckout.GetSKUIDFromSlug(slug)
+	if err != nil {
+		return err
+	}
+
+	env.Reporter.Info(T("sku_result", "slug", slug, "sku", skuID))
+	return nil
+}
+
+// runOrdersCommand handles "specter orders [show <slug>]": the saved receipts, or an
+// order as the store has it now
+func runOrdersCommand(env *commandEnv, args []string) error {
+	switch {
+	case len(args) == 0:
+		receipts, err := loadReceipts(receiptsDir())
+		if err != nil {
+			return err
+		}
+		if len(receipts) == 0 {
+			env.Reporter.Info(T("orders_none", "path", receiptsDir()))
+			return nil
+		}
+		for _, receipt := range receipts {
+			total, err := receipt.Expected.total()
+			if err != nil {
+				return err
+			}
+			if receipt.Verified {
+				env.Reporter.Info(T("orders_row_verified", "placed", receipt.PlacedAt, "slug", receipt.OrderSlug, "name", receipt.Expected.Name, "total", total))
+			} else {
+				env.Reporter.Info(T("orders_row_unverified", "placed", receipt.PlacedAt, "slug", receipt.OrderSlug, "name", receipt.Expected.Name, "total", total))
+			}
+		}
+		return nil
+
+	case len(args) == 2 && args[0] == "show":
+		fastCheckout, err := env.Session()
+		if err != nil {
+			return err
+		}
+		order, err := fastCheckout.GetOrder(args[1])
+		if err != nil {
+			return err
+		}
+
+		env.Reporter.Info(T("orders_show_header", "slug", order.Slug, "status", order.Status))
+		for _, line := range order.Lines {
+			env.Reporter.Info(T("orders_show_line", "name", line.Name, "sku", line.SKUID, "qty", line.Quantity, "price", line.UnitPrice))
+		}
+		env.Reporter.Info(T("orders_show_credit", "credit", order.CreditUsed))
+		env.Reporter.Info(T("orders_show_total", "total", order.Total))
+		return nil
+
+	default:
+		return TError("command_orders_usage")
+	}
+}
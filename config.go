@@ -45,14 +45,44 @@ type Config struct {
 
 	AutoApplyCredit bool `yaml:"auto_apply_credit"`
 
+	Currency string `yaml:"currency"` // ISO 4217 code of the store's prices (default: USD)
+
+	TargetPrice float64 `yaml:"target_price"` // Price of the item_url target, for "specter credit" when it isn't in the cart (0 = unknown)
+
 	SkipAddToCart bool `yaml:"skip_add_to_cart"`
 
 	Headless        bool `yaml:"headless"`
 	KeepBrowserOpen bool `yaml:"keep_browser_open"`
 
+	// Encrypted session cache (~/.specter/session.enc)
+	SessionCache            bool `yaml:"session_cache"`               // Save the session encrypted with a passphrase and reuse it on restart
+	SessionCacheMaxAgeHours int  `yaml:"session_cache_max_age_hours"` // Max age for cookies without their own expiry (default: 12)
+
+	// Session health checks while waiting between waves
+	SessionCheckIntervalMinutes int `yaml:"session_check_interval_minutes"` // Minutes between authenticated probes while dormant (default: 15, 0 disables)
+
+	// Unattended runs: answers for prompts that would otherwise wait for the user
+	Unattended       bool   `yaml:"unattended"`         // Fail fast on any prompt without a policy instead of blocking
+	OnLoginRequired  string `yaml:"on_login_required"`  // Initial login prompt: proceed|abort (empty = ask)
+	OnSessionExpired string `yaml:"on_session_expired"` // Logged out mid-checkout: proceed (re-read browser session)|abort (empty = ask)
+	OnCartMismatch   string `yaml:"on_cart_mismatch"`   // Unexpected cart contents: proceed|clean (one target unit only)|abort (empty = ask)
+	OnCartRestore    string `yaml:"on_cart_restore"`    // Put saved cart items back after the run: restore|skip (empty = ask)
+
 	DryRun    bool `yaml:"dry_run"`
 	DebugMode bool `yaml:"debug_mode"`
 
+	Language string `yaml:"language"` // Locale for messages, e.g. ru_RU (empty = system language)
+	Output   string `yaml:"output"`   // How progress is printed: human|json (one JSON object per line)|quiet (errors and outcomes only)|tui
+
+	// Log files (~/.specter/logs), written in full whatever the console shows
+	LogLevel     string `yaml:"log_level"`       // Lowest level written: debug|info|warn|error (default: debug)
+	LogMaxSizeMB int    `yaml:"log_max_size_mb"` // Size at which the log file is rotated (default: 10)
+	LogMaxFiles  int    `yaml:"log_max_files"`   // Rotated files kept besides the current one (default: 5)
+
+	// Store traffic cassettes: record a run's GraphQL exchanges, or answer them from a recording
+	RecordCassette string `yaml:"record_cassette"` // Cassette file to record into (empty = off)
+	ReplayCassette string `yaml:"replay_cassette"` // Cassette file to replay from instead of the store (empty = off)
+
 	Selectors SelectorConfig `yaml:"selectors"`
 }
 
@@ -69,37 +99,44 @@ func DefaultConfig() *Config {
 	userDataDir := getUserDataDir()
 
 	return &Config{
-		ItemURL:              "",
-		BrowserProfilePath:   filepath.Join(userDataDir, "browser-profile"),
-		BrowserType:          "chrome",
-		PageLoadTimeout:      30,
-		MinDelayBetween:      0.1,  // Reduced from 0.5 for speed
-		MaxDelayBetween:      0.3,  // Reduced from 1.0 for speed
-		CheckoutReadyDelay:   1,    // Reduced from 2 for speed
-		RetryDurationSeconds:     300,
-		RetryDelayMinMs:          5,    // Ultra-fast retries
-		RetryDelayMaxMs:          20,   // Ultra-fast retries
-		Payment4227MinMs:    1300,  // Payment auth 4227: 1.3-2.1 seconds
-		Payment4227MaxMs:    2100,
-		Payment4226MinMs:    500,   // Payment auth 4226: 500-700ms
-		Payment4226MaxMs:    700,
-		RateLimitMinMs:           50,         // Rate limit: 50-150ms
-		RateLimitMaxMs:           150,
-		OutOfStockDelayMs:        100,        // Out of stock: 100ms
-		GenericErrorDelayMs:      100,        // Generic errors: 100ms
-		SaleWindows:              []string{}, // Sale windows (required: use --waves-date YYYY-MM-DD or configure in config.yaml)
-		PreWaveActivationMinutes: 2,          // Start polling 2 minutes before wave
-		PostWaveTimeoutMinutes:   5,          // Continue 5 minutes after wave before moving to next
-		PollingDelayMinMs:        29,         // Polling delay: 29-139ms (human-like, variable timing)
-		PollingDelayMaxMs:        139,
-		RecaptchaSiteKey:         "6LcZ-cUpAAAAABTy47-ryVJAsZFocXguqi_FgLlJ",
-		RecaptchaAction:          "store/cart/add",
-		Headless:             false,
-		KeepBrowserOpen:      true,
-		AutoApplyCredit:      true,
-		SkipAddToCart:        false,
-		DryRun:               false,
-		DebugMode:            false,
+		ItemURL:                     "",
+		BrowserProfilePath:          filepath.Join(userDataDir, "browser-profile"),
+		BrowserType:                 "chrome",
+		PageLoadTimeout:             30,
+		MinDelayBetween:             0.1, // Reduced from 0.5 for speed
+		MaxDelayBetween:             0.3, // Reduced from 1.0 for speed
+		CheckoutReadyDelay:          1,   // Reduced from 2 for speed
+		RetryDurationSeconds:        300,
+		RetryDelayMinMs:             5,    // Ultra-fast retries
+		RetryDelayMaxMs:             20,   // Ultra-fast retries
+		Payment4227MinMs:            1300, // Payment auth 4227: 1.3-2.1 seconds
+		Payment4227MaxMs:            2100,
+		Payment4226MinMs:            500, // Payment auth 4226: 500-700ms
+		Payment4226MaxMs:            700,
+		RateLimitMinMs:              50, // Rate limit: 50-150ms
+		RateLimitMaxMs:              150,
+		OutOfStockDelayMs:           100,        // Out of stock: 100ms
+		GenericErrorDelayMs:         100,        // Generic errors: 100ms
+		SaleWindows:                 []string{}, // Sale windows (required: use --waves-date YYYY-MM-DD or configure in config.yaml)
+		PreWaveActivationMinutes:    2,          // Start polling 2 minutes before wave
+		PostWaveTimeoutMinutes:      5,          // Continue 5 minutes after wave before moving to next
+		PollingDelayMinMs:           29,         // Polling delay: 29-139ms (human-like, variable timing)
+		PollingDelayMaxMs:           139,
+		RecaptchaSiteKey:            "6LcZ-cUpAAAAABTy47-ryVJAsZFocXguqi_FgLlJ",
+		RecaptchaAction:             "store/cart/add",
+		Headless:                    false,
+		KeepBrowserOpen:             true,
+		AutoApplyCredit:             true,
+		Currency:                    defaultCurrency,
+		SkipAddToCart:               false,
+		SessionCache:                false,
+		SessionCacheMaxAgeHours:     12,
+		SessionCheckIntervalMinutes: 15,
+		DryRun:                      false,
+		DebugMode:                   false,
+		LogLevel:                    "debug",
+		LogMaxSizeMB:                10,
+		LogMaxFiles:                 5,
 		Selectors: SelectorConfig{
 			AddToCartButton:     ".add-to-cart, .js-add-to-cart, button[data-action='add-to-cart']",
 			CartIcon:            ".cart-icon, .shopping-cart, [data-testid='cart']",
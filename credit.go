@@ -0,0 +1,112 @@
+package main
+
+import "time"
+
+// creditCheck is how the credit on the account compares with what the target costs at
+// checkout. Every wave buys the same item_url target, so one check covers them all.
+type creditCheck struct {
+	Due       Money // Target price plus any tax the store adds on top
+	Available Money // Balance plus the credit the cart already holds
+	Left      Money // Credit left after buying the target (negative when short)
+}
+
+// Short reports whether the available credit doesn't cover the target
+func (c creditCheck) Short() bool {
+	return c.Left.Amount < 0
+}
+
+// checkCredit works out the amount due for one unit at price the way the checkout
+// does, and compares it with the balance. Credit applied to the cart is held back
+// from the balance, and the checkout replaces it, so it counts as available.
+func checkCredit(cart *CartInfo, price Money) (creditCheck, error) {
+	due, err := cart.expectedTotal(CartItem{Price: price, Quantity: 1})
+	if err != nil {
+		return creditCheck{}, err
+	}
+	available, err := cart.Ledger.Add(cart.CreditApplied)
+	if err != nil {
+		return creditCheck{}, err
+	}
+	left, err := available.Sub(due)
+	if err != nil {
+		return creditCheck{}, err
+	}
+	return creditCheck{Due: due, Available: available, Left: left}, nil
+}
+
+// runCreditCommand handles "specter credit": the store credit on the account and
+// whether it covers the target at checkout
+func runCreditCommand(env *commandEnv, args []string) error {
+	if len(args) > 0 {
+		return TError("command_credit_usage")
+	}
+	config, reporter := env.Config, env.Reporter
+
+	waves, err := planWaves(config)
+	if err != nil {
+		return err
+	}
+
+	fastCheckout, err := env.Session()
+	if err != nil {
+		return err
+	}
+	cart, err := fastCheckout.GetCartTotalsAndItems()
+	if err != nil {
+		return err
+	}
+
+	reporter.Info(T("check_credit_balance", "balance", cart.Ledger))
+	if cart.CreditApplied.IsPositive() {
+		reporter.Info(T("credit_applied_to_cart", "credit", cart.CreditApplied))
+	}
+
+	if config.ItemURL == "" {
+		reporter.Info(T("credit_no_target"))
+		return nil
+	}
+	skuID, err := fastCheckout.GetSKUFromURL(config.ItemURL)
+	if err != nil {
+		return err
+	}
+
+	// The cart has the store's current price; the config is the fallback
+	var price Money
+	if item, ok := findCartItem(cart.Items, skuID); ok {
+		price = item.Price
+		reporter.Info(T("credit_target_from_cart", "name", item.Name, "sku", skuID, "price", price))
+	} else if config.TargetPrice > 0 {
+		price = moneyFromMajor(config.TargetPrice, fastCheckout.currency())
+		reporter.Info(T("credit_target_from_config", "sku", skuID, "price", price))
+	} else {
+		reporter.Warn(T("credit_price_unknown", "sku", skuID))
+		return nil
+	}
+
+	// target_price is read in the configured currency, which the balance need not share
+	if price.Currency != cart.Ledger.Currency {
+		return TError("credit_currency_mismatch", "balance", cart.Ledger.Currency, "price", price.Currency)
+	}
+
+	check, err := checkCredit(cart, price)
+	if err != nil {
+		return err
+	}
+	reporter.Info(T("credit_target_due", "due", check.Due, "tax", cart.taxOnTop(), "available", check.Available))
+	if !check.Short() {
+		reporter.Info(T("credit_target_covered", "left", check.Left))
+		return nil
+	}
+
+	short := newMoney(-check.Left.Amount, check.Left.Currency)
+	ahead := 0
+	for _, wave := range waves {
+		if wave.Status(time.Now()) != WavePassed {
+			ahead++
+		}
+	}
+	if ahead > 0 {
+		return TError("credit_waves_short", "short", short, "count", ahead)
+	}
+	return TError("credit_target_short", "short", short)
+}
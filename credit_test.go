@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestCheckCredit(t *testing.T) {
+	// $45.00 plus $3.15 sales tax on top, $10.00 of the credit already on the cart
+	cart := &CartInfo{Tax: usd(315), CreditApplied: usd(1000), Ledger: usd(4000)}
+
+	check, err := checkCredit(cart, usd(4500))
+	if err != nil {
+		t.Fatalf("checkCredit failed: %v", err)
+	}
+	if !check.Due.Equal(usd(4815)) || !check.Available.Equal(usd(5000)) {
+		t.Errorf("Expected $48.15 due and $50.00 available, got %s and %s", check.Due, check.Available)
+	}
+	if check.Short() || !check.Left.Equal(usd(185)) {
+		t.Errorf("Expected $1.85 left, got %s", check.Left)
+	}
+
+	// The tax alone makes the difference when it isn't included in the price
+	cart = &CartInfo{Tax: usd(315), Ledger: usd(4600)}
+	if check, err := checkCredit(cart, usd(4500)); err != nil || !check.Short() || !check.Left.Equal(usd(-215)) {
+		t.Errorf("Expected $2.15 short, got %s (%v)", check.Left, err)
+	}
+
+	// VAT is part of the price already
+	cart = &CartInfo{Tax: newMoney(750, "EUR"), TaxIncluded: true, Ledger: newMoney(4500, "EUR")}
+	if check, err := checkCredit(cart, newMoney(4500, "EUR")); err != nil || check.Short() || !check.Left.IsZero() {
+		t.Errorf("Expected the VAT-inclusive price to be covered exactly, got %s (%v)", check.Left, err)
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// accountState is the part of the account a checkout run can change: cart lines,
+// applied store credit, the credit ledger and the checkout flow step
+type accountState struct {
+	Items         []CartItem
+	CreditApplied Money
+	Ledger        Money
+	FlowStep      string
+}
+
+// newAccountState combines a cart query with the active flow step
+func newAccountState(cartInfo *CartInfo, flowStep string) *accountState {
+	return &accountState{
+		Items:         append([]CartItem(nil), cartInfo.Items...),
+		CreditApplied: cartInfo.CreditApplied,
+		Ledger:        cartInfo.Ledger,
+		FlowStep:      flowStep,
+	}
+}
+
+// quantities totals the cart by SKU (one SKU can sit on several lines)
+func (s *accountState) quantities() map[string]int {
+	qty := make(map[string]int)
+	for _, item := range s.Items {
+		qty[item.SKUID] += item.Quantity
+	}
+	return qty
+}
+
+// itemName returns the cart name of a SKU, or the SKU itself when it isn't in the cart
+func (s *accountState) itemName(skuID string) string {
+	if item, ok := findCartItem(s.Items, skuID); ok {
+		return item.Name
+	}
+	return skuID
+}
+
+// diffAccountState lists every difference between two states (nil when they match)
+func diffAccountState(before, after *accountState) []string {
+	var diff []string
+
+	beforeQty := before.quantities()
+	afterQty := after.quantities()
+
+	skus := make([]string, 0, len(beforeQty)+len(afterQty))
+	for sku := range beforeQty {
+		skus = append(skus, sku)
+	}
+	for sku := range afterQty {
+		if _, ok := beforeQty[sku]; !ok {
+			skus = append(skus, sku)
+		}
+	}
+	sort.Strings(skus)
+
+	for _, sku := range skus {
+		if beforeQty[sku] != afterQty[sku] {
+			name := before.itemName(sku)
+			if name == sku {
+				name = after.itemName(sku)
+			}
+			diff = append(diff, T("dry_run_diff_item", "name", name, "before_qty", beforeQty[sku], "after_qty", afterQty[sku]))
+		}
+	}
+
+	if !before.CreditApplied.Equal(after.CreditApplied) {
+		diff = append(diff, T("dry_run_diff_credit", "before", before.CreditApplied, "after", after.CreditApplied))
+	}
+	if !before.Ledger.Equal(after.Ledger) {
+		diff = append(diff, T("dry_run_diff_ledger", "before", before.Ledger, "after", after.Ledger))
+	}
+	if before.FlowStep != after.FlowStep {
+		diff = append(diff, T("dry_run_diff_flow", "before", before.FlowStep, "after", after.FlowStep))
+	}
+
+	return diff
+}
+
+// cartRollbackStep is one change that takes a cart line back to its earlier quantity
+type cartRollbackStep struct {
+	Item     CartItem
+	Remove   bool
+	Quantity int // New quantity when not removing
+}
+
+// planCartRollback works out how to get from the current cart back to the earlier one:
+// surplus lines are shrunk or removed, and whatever went missing is returned for re-adding
+func planCartRollback(before, current *accountState) ([]cartRollbackStep, []cartSnapshotItem) {
+	var steps []cartRollbackStep
+
+	// Quantity of each SKU that may stay in the cart
+	keep := before.quantities()
+	for _, item := range current.Items {
+		allowed := keep[item.SKUID]
+		switch {
+		case allowed >= item.Quantity:
+			keep[item.SKUID] -= item.Quantity
+		case allowed == 0:
+			steps = append(steps, cartRollbackStep{Item: item, Remove: true})
+		default:
+			steps = append(steps, cartRollbackStep{Item: item, Quantity: allowed})
+			keep[item.SKUID] = 0
+		}
+	}
+
+	// Whatever is still owed was taken out of the cart during the run
+	var missing []cartSnapshotItem
+	for _, item := range before.Items {
+		if keep[item.SKUID] > 0 {
+			missing = append(missing, cartSnapshotItem{SKUID: item.SKUID, Name: item.Name, Quantity: keep[item.SKUID]})
+			keep[item.SKUID] = 0
+		}
+	}
+
+	return steps, missing
+}
+
+// captureDryRunState remembers the account as it was before a dry run touched it
+func (f *FastCheckout) captureDryRunState(cartInfo *CartInfo) {
+	flowStep, err := f.GetCartFlowStep()
+	if err != nil {
+		f.reporter.Warn(T("dry_run_state_failed", "error", err))
+		return
+	}
+	f.dryRunBefore = newAccountState(cartInfo, flowStep)
+}
+
+// captureAccountState queries the cart, credit and flow step as they are now
+func (f *FastCheckout) captureAccountState() (*accountState, error) {
+	cartInfo, err := f.GetCartTotalsAndItems()
+	if err != nil {
+		return nil, err
+	}
+	flowStep, err := f.GetCartFlowStep()
+	if err != nil {
+		return nil, err
+	}
+	return newAccountState(cartInfo, flowStep), nil
+}
+
+// rollbackDryRun undoes what a dry run changed - flow step, applied credit and cart
+// lines, in that order - and prints the before/after diff. Returns true when the
+// account ended up exactly as it was.
+func (f *FastCheckout) rollbackDryRun() bool {
+	before := f.dryRunBefore
+	f.dryRunBefore = nil
+
+	f.reporter.Info("")
+	f.reporter.Info(T("dry_run_rollback_start"))
+
+	if err := f.rollbackAccountState(before); err != nil {
+		f.reporter.Warn(T("dry_run_rollback_failed", "error", err))
+	}
+
+	after, err := f.captureAccountState()
+	if err != nil {
+		f.reporter.Warn(T("dry_run_rollback_failed", "error", err))
+		return false
+	}
+
+	f.reporter.Info(T("dry_run_state_before", "count", len(before.Items), "credit", before.CreditApplied, "ledger", before.Ledger, "step", before.FlowStep))
+	f.reporter.Info(T("dry_run_state_after", "count", len(after.Items), "credit", after.CreditApplied, "ledger", after.Ledger, "step", after.FlowStep))
+
+	diff := diffAccountState(before, after)
+	if len(diff) > 0 {
+		f.reporter.Warn(T("dry_run_rollback_mismatch"))
+		for _, line := range diff {
+			f.reporter.Info(fmt.Sprintf("   • %s", line))
+		}
+		return false
+	}
+
+	f.reporter.Info(T("dry_run_rollback_verified"))
+	return true
+}
+
+// rollbackAccountState applies the mutations that take the account back to before
+func (f *FastCheckout) rollbackAccountState(before *accountState) error {
+	current, err := f.captureAccountState()
+	if err != nil {
+		return err
+	}
+
+	// The flow goes back first - the cart can't be edited past the cart step
+	if current.FlowStep != before.FlowStep && before.FlowStep != "" {
+		f.reporter.Info(T("dry_run_rollback_flow", "from", current.FlowStep, "to", before.FlowStep))
+		if err := f.ResetCartFlow(before.FlowStep); err != nil {
+			return err
+		}
+	}
+
+	if !current.CreditApplied.Equal(before.CreditApplied) {
+		f.reporter.Info(T("dry_run_rollback_credit", "from", current.CreditApplied, "to", before.CreditApplied))
+		if err := f.ApplyStoreCredit(before.CreditApplied); err != nil {
+			return err
+		}
+	}
+
+	steps, missing := planCartRollback(before, current)
+	for _, step := range steps {
+		if step.Remove {
+			f.reporter.Info(T("cart_cleanup_removing", "name", step.Item.Name, "quantity", step.Item.Quantity))
+			if err := f.RemoveLineItem(step.Item.LineItemID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		f.reporter.Info(T("cart_cleanup_setting_quantity", "name", step.Item.Name, "from", step.Item.Quantity, "to", step.Quantity))
+		if err := f.SetLineItemQuantity(step.Item.LineItemID, step.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if len(missing) > 0 {
+		for _, item := range missing {
+			f.reporter.Info(T("cart_restore_adding", "name", item.Name, "quantity", item.Quantity))
+		}
+		if err := f.AddCartItems(missing); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetCartFlowStep returns the active checkout flow step ("cart", "billing", ...)
+func (f *FastCheckout) GetCartFlowStep() (string, error) {
+	query := operation("CartFlowQuery")
+
+	request := []GraphQLRequest{
+		{
+			OperationName: "CartFlowQuery",
+			Variables: map[string]interface{}{
+				"storeFront": "pledge",
+			},
+			Query: query,
+		},
+	}
+
+	resp, err := f.graphqlRequestWithLoginRetry(request)
+	if err != nil {
+		return "", TError("error_failed_query_flow", "error", err)
+	}
+
+	flow, err := decodeCartFlow("CartFlowQuery", resp)
+	if err != nil {
+		return "", TError("error_failed_parse_flow", "error", err)
+	}
+
+	return flow.activeStep(), nil
+}
+
+// ResetCartFlow moves the checkout flow back to the given step
+func (f *FastCheckout) ResetCartFlow(step string) error {
+	mutation := operation("CartFlowResetMutation")
+
+	request := []GraphQLRequest{
+		{
+			OperationName: "CartFlowResetMutation",
+			Variables: map[string]interface{}{
+				"step":       step,
+				"storeFront": "pledge",
+			},
+			Query: mutation,
+		},
+	}
+
+	if _, err := f.graphqlRequestWithLoginRetry(request); err != nil {
+		return TError("error_cart_flow_reset_failed", "error", err)
+	}
+	return nil
+}
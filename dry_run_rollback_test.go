@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDiffAccountState(t *testing.T) {
+	before := &accountState{
+		Items:    []CartItem{{Name: "Other Ship", SKUID: "111", Quantity: 1}},
+		Ledger:   usd(100000),
+		FlowStep: "cart",
+	}
+
+	same := &accountState{
+		Items:    []CartItem{{Name: "Other Ship", SKUID: "111", Quantity: 1}},
+		Ledger:   usd(100000),
+		FlowStep: "cart",
+	}
+	if diff := diffAccountState(before, same); len(diff) != 0 {
+		t.Errorf("Expected no differences, got %v", diff)
+	}
+
+	changed := &accountState{
+		Items: []CartItem{
+			{Name: "Other Ship", SKUID: "111", Quantity: 1},
+			{Name: "Target Ship", SKUID: "222", Quantity: 1},
+		},
+		CreditApplied: usd(4500),
+		Ledger:        usd(100000),
+		FlowStep:      "billing",
+	}
+	if diff := diffAccountState(before, changed); len(diff) != 3 {
+		t.Errorf("Expected item, credit and flow differences, got %v", diff)
+	}
+}
+
+func TestPlanCartRollback(t *testing.T) {
+	before := &accountState{Items: []CartItem{
+		{Name: "Other Ship", SKUID: "111", Quantity: 2},
+		{Name: "Paint", SKUID: "333", Quantity: 1},
+	}}
+	current := &accountState{Items: []CartItem{
+		{LineItemID: "a", Name: "Other Ship", SKUID: "111", Quantity: 3},
+		{LineItemID: "b", Name: "Target Ship", SKUID: "222", Quantity: 1},
+	}}
+
+	steps, missing := planCartRollback(before, current)
+	if len(steps) != 2 {
+		t.Fatalf("Expected 2 rollback steps, got %+v", steps)
+	}
+	if steps[0].Remove || steps[0].Item.LineItemID != "a" || steps[0].Quantity != 2 {
+		t.Errorf("Expected other ship to go back to quantity 2, got %+v", steps[0])
+	}
+	if !steps[1].Remove || steps[1].Item.LineItemID != "b" {
+		t.Errorf("Expected added target to be removed, got %+v", steps[1])
+	}
+	if len(missing) != 1 || missing[0].SKUID != "333" || missing[0].Quantity != 1 {
+		t.Errorf("Expected removed paint to be re-added, got %+v", missing)
+	}
+}
+
+func TestRollbackDryRun(t *testing.T) {
+	fc, cart := newFakeCartCheckout(t, []fakeCartLine{
+		{ID: "line-1", SKUID: "111", Title: "Other Ship", Cents: 4500, Qty: 1},
+	})
+
+	cartInfo, err := fc.GetCartTotalsAndItems()
+	if err != nil {
+		t.Fatalf("GetCartTotalsAndItems failed: %v", err)
+	}
+	fc.captureDryRunState(cartInfo)
+	if fc.dryRunBefore == nil {
+		t.Fatal("Expected dry-run state to be captured")
+	}
+
+	// What a dry run leaves behind: cleaned cart, added target, credit, billing step
+	if err := fc.RemoveLineItem("line-1"); err != nil {
+		t.Fatalf("RemoveLineItem failed: %v", err)
+	}
+	if err := fc.AddCartItems([]cartSnapshotItem{{SKUID: "222", Quantity: 1}}); err != nil {
+		t.Fatalf("AddCartItems failed: %v", err)
+	}
+	if err := fc.ApplyStoreCredit(usd(1000)); err != nil {
+		t.Fatalf("ApplyStoreCredit failed: %v", err)
+	}
+	if err := fc.NextStep(); err != nil {
+		t.Fatalf("NextStep failed: %v", err)
+	}
+
+	if !fc.rollbackDryRun() {
+		t.Fatalf("Expected rollback to restore the account, cart is %+v", cart.lines)
+	}
+	if fc.dryRunBefore != nil {
+		t.Error("Dry-run state should be consumed by the rollback")
+	}
+	if len(cart.lines) != 1 || cart.lines[0].SKUID != "111" || cart.credit != 0 || cart.activeStep() != "cart" {
+		t.Errorf("Unexpected state after rollback: lines %+v, credit %d, step %s", cart.lines, cart.credit, cart.step)
+	}
+}
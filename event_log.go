@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// eventLogMutex serializes appends so concurrent writers never interleave lines
+var eventLogMutex sync.Mutex
+
+// eventLogPath is the NDJSON event log under the user data dir (~/.specter/events.log)
+func eventLogPath() string {
+	return filepath.Join(getUserDataDir(), "events.log")
+}
+
+// appendEventLog appends one JSON line describing an event to the event log.
+// Field values pass through the redactor first, so the log is safe to share.
+// The log is best-effort diagnostics: failures to write are silently ignored so
+// they can never interrupt a checkout.
+func appendEventLog(event string, fields map[string]interface{}) {
+	entry := map[string]interface{}{
+		"time":  time.Now().UTC().Format(time.RFC3339Nano),
+		"event": event,
+	}
+	for key, value := range redactFields(fields) {
+		entry[key] = value
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	eventLogMutex.Lock()
+	defer eventLogMutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(eventLogPath()), 0755); err != nil {
+		return
+	}
+	file, err := os.OpenFile(eventLogPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	file.Write(append(line, '\n'))
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -9,7 +8,7 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/cookiejar"
-	"os"
+	"net/http/httptrace"
 	"regexp"
 	"strings"
 	"sync"
@@ -20,6 +19,7 @@ import (
 
 type FastCheckout struct {
 	client           *http.Client
+	transport        *http.Transport // Shared, pre-warmed connection pool for all store traffic
 	config           *Config
 	baseURL          string
 	graphqlURL       string
@@ -28,11 +28,26 @@ type FastCheckout struct {
 	userAgent        string
 	cachedAddressID  string // Cached billing address for speed
 	automation       *Automation // Reference to automation for login retry
+	prompter         Prompter    // Answers login and cart prompts (terminal, policy or script)
+	reporter         Reporter    // Renders progress and outcomes (human, JSON or quiet)
 
 	// reCAPTCHA token caching (tokens valid for 2 minutes, we refresh every 1 minute)
 	cachedRecaptchaToken     string
 	cachedRecaptchaTimestamp time.Time
 	recaptchaMutex           sync.Mutex
+
+	latency latencyRecorder // Per-request httptrace timings for the current run
+
+	sessionKey *sessionCacheKey // Set when the encrypted session cache is enabled
+
+	cartSnapshot *cartSnapshot // Cart contents saved before this run changed them
+
+	lastOrderSlug string        // Slug returned by the last successful cart validation
+	receipt       *orderReceipt // Completed purchase awaiting verification and receipt
+
+	dryRunBefore *accountState // Cart and credit state captured before a dry run changed it
+
+	cassette *cassette // Records store traffic, or replays it instead of calling the store
 }
 
 type GraphQLRequest struct {
@@ -67,9 +82,18 @@ func NewFastCheckout(config *Config) (*FastCheckout, error) {
 		return nil, fmt.Errorf("failed to create cookie jar: %w", err)
 	}
 
+	reporter := NewReporter(config, "checkout")
+	cassette, err := newCassetteFromConfig(config, reporter)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := newStoreTransport()
+
 	client := &http.Client{
-		Timeout: 30 * time.Second,
-		Jar:     jar,
+		Timeout:   30 * time.Second,
+		Jar:       jar,
+		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
@@ -77,77 +101,69 @@ func NewFastCheckout(config *Config) (*FastCheckout, error) {
 
 	return &FastCheckout{
 		client:     client,
+		transport:  transport,
 		config:     config,
 		baseURL:    "https://robertsspaceindustries.com",
 		graphqlURL: "https://robertsspaceindustries.com/graphql",
+		prompter:   NewPrompter(config),
+		reporter:   reporter,
+		cassette:   cassette,
 	}, nil
 }
 
-func (f *FastCheckout) promptForLogin(automation *Automation) error {
-	fmt.Println(T("error_not_logged_in_detected"))
-	fmt.Println(T("error_not_logged_in_instructions"))
-	fmt.Println(T("error_not_logged_in_step1"))
-	fmt.Println(T("error_not_logged_in_step2"))
-	fmt.Println(T("error_not_logged_in_step3"))
-	fmt.Println()
-	fmt.Print(T("error_not_logged_in_prompt"))
-
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		input, err := reader.ReadByte()
-		if err != nil {
-			return fmt.Errorf("failed to read input: %w", err)
-		}
-
-		if input == '\n' || input == '\r' {
-			fmt.Println()
-			fmt.Println(T("error_not_logged_in_retrying"))
-			break
-		}
+// Close releases what the checkout holds open between requests: the cassette being
+// recorded is flushed and closed
+func (f *FastCheckout) Close() error {
+	if f.cassette == nil {
+		return nil
+	}
+	return f.cassette.Close()
+}
 
-		if input == 27 { // ESC key
-			fmt.Println()
-			return fmt.Errorf(T("error_not_logged_in_user_canceled"))
-		}
+func (f *FastCheckout) promptForLogin(automation *Automation) error {
+	f.reporter.Warn(T("error_not_logged_in_detected"))
+	f.reporter.Info(T("error_not_logged_in_instructions"))
+	f.reporter.Info(T("error_not_logged_in_step1"))
+	f.reporter.Info(T("error_not_logged_in_step2"))
+	f.reporter.Info(T("error_not_logged_in_step3"))
+	f.reporter.Info("")
+
+	answer, err := f.prompter.Choose(Prompt{
+		Kind:    PromptSessionExpired,
+		Message: T("error_not_logged_in_prompt"),
+		Options: []PromptAnswer{AnswerProceed, AnswerAbort},
+	})
+	if err != nil {
+		return err
+	}
+	if answer == AnswerAbort {
+		return TError("error_not_logged_in_user_canceled")
 	}
+	f.reporter.Info(T("error_not_logged_in_retrying"))
 
 	// Reload session after login
 	return f.LoadSessionFromBrowser(automation)
 }
 
 func (f *FastCheckout) LoadSessionFromBrowser(automation *Automation) error {
-	fmt.Println(T("session_extracting"))
+	f.reporter.Info(T("session_extracting"))
 
 	if automation == nil || automation.page == nil {
-		return fmt.Errorf(T("session_browser_not_initialized"))
+		return TError("session_browser_not_initialized")
 	}
 
 	// Store automation reference for login retry
 	f.automation = automation
 
-	cookies, err := automation.page.Cookies([]string{f.baseURL})
+	// Start from a clean slate so repeated extractions don't accumulate stale duplicates
+	cookies, err := f.browserCookies(automation)
 	if err != nil {
-		return fmt.Errorf("failed to get cookies: %w", err)
-	}
-
-	for _, cookie := range cookies {
-		var expires time.Time
-		if cookie.Expires > 0 {
-			expires = time.Unix(int64(cookie.Expires), 0)
-		}
-
-		f.cookies = append(f.cookies, &http.Cookie{
-			Name:     cookie.Name,
-			Value:    cookie.Value,
-			Path:     cookie.Path,
-			Domain:   cookie.Domain,
-			Expires:  expires,
-			Secure:   cookie.Secure,
-			HttpOnly: cookie.HTTPOnly,
-		})
+		return err
 	}
+	f.cookies = cookies
+	registerCookieSecrets(cookies)
 
-	fmt.Printf(T("session_cookies_extracted")+"\n", len(f.cookies))
+	f.reporter.Info(T("session_cookies_extracted", "count", len(f.cookies)))
 
 	csrfToken, err := automation.page.Eval(`() => {
 		const meta = document.querySelector('meta[name="csrf-token"]');
@@ -164,9 +180,10 @@ func (f *FastCheckout) LoadSessionFromBrowser(automation *Automation) error {
 	}`)
 	if err == nil && csrfToken.Value.Str() != "" {
 		f.csrfToken = csrfToken.Value.Str()
-		fmt.Printf(T("session_csrf_extracted")+"\n", f.csrfToken[:16])
+		registerSecret(f.csrfToken)
+		f.reporter.Info(T("session_csrf_extracted", "token", maskSecret(f.csrfToken)))
 	} else {
-		fmt.Println(T("session_csrf_not_found"))
+		f.reporter.Warn(T("session_csrf_not_found"))
 	}
 
 	userAgentResult, err := automation.page.Eval(`() => navigator.userAgent`)
@@ -176,15 +193,45 @@ func (f *FastCheckout) LoadSessionFromBrowser(automation *Automation) error {
 		f.userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"
 	}
 
+	f.saveSessionCache()
+
 	return nil
 }
 
+// browserCookies reads the store cookies currently held by the browser
+func (f *FastCheckout) browserCookies(automation *Automation) ([]*http.Cookie, error) {
+	cookies, err := automation.page.Cookies([]string{f.baseURL})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cookies: %w", err)
+	}
+
+	var result []*http.Cookie
+	for _, cookie := range cookies {
+		var expires time.Time
+		if cookie.Expires > 0 {
+			expires = time.Unix(int64(cookie.Expires), 0)
+		}
+
+		result = append(result, &http.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			Expires:  expires,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HTTPOnly,
+		})
+	}
+
+	return result, nil
+}
+
 func (f *FastCheckout) GetSKUSlugFromURL(itemURL string) (string, error) {
-	fmt.Printf(T("sku_extracting_from_url")+"\n", itemURL)
+	f.reporter.Info(T("sku_extracting_from_url", "url", itemURL))
 
 	req, err := http.NewRequest("GET", itemURL, nil)
 	if err != nil {
-		return "", fmt.Errorf(T("error_failed_create_request"), err)
+		return "", TError("error_failed_create_request", "error", err)
 	}
 
 	req.Header.Set("User-Agent", f.userAgent)
@@ -195,46 +242,33 @@ func (f *FastCheckout) GetSKUSlugFromURL(itemURL string) (string, error) {
 
 	resp, err := f.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf(T("error_failed_fetch_product"), err)
+		return "", TError("error_failed_fetch_product", "error", err)
 	}
 	defer resp.Body.Close()
 
-	if f.config.DebugMode {
-		fmt.Printf(T("debug_product_page_status")+"\n", resp.StatusCode)
-	}
+	f.reporter.Debug(T("debug_product_page_status", "status", resp.StatusCode))
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf(T("error_failed_read_response"), err)
+		return "", TError("error_failed_read_response", "error", err)
 	}
 
-	if f.config.DebugMode {
-		fmt.Printf(T("debug_product_page_length")+"\n", len(body))
-	}
+	f.reporter.Debug(T("debug_product_page_length", "length", len(body)))
 
 	re := regexp.MustCompile(`"skuSlug":\s*"([^"]+)"`)
 	matches := re.FindStringSubmatch(string(body))
 	if len(matches) > 1 {
-		fmt.Printf(T("sku_found_slug")+"\n", matches[1])
+		f.reporter.Info(T("sku_found_slug", "slug", matches[1]))
 		return matches[1], nil
 	}
 
-	return "", fmt.Errorf(T("sku_could_not_find"))
+	return "", TError("sku_could_not_find")
 }
 
 func (f *FastCheckout) GetSKUIDFromSlug(skuSlug string) (string, error) {
-	fmt.Println(T("sku_converting_slug"))
-
-	query := `query GetSkuQuery($slug: String!, $storeFront: String!) {
-  store(name: $storeFront) {
-    listing(slug: $slug) {
-      skus {
-        id
-        title
-      }
-    }
-  }
-}`
+	f.reporter.Info(T("sku_converting_slug"))
+
+	query := operation("GetSkuQuery")
 
 	request := []GraphQLRequest{
 		{
@@ -249,32 +283,20 @@ func (f *FastCheckout) GetSKUIDFromSlug(skuSlug string) (string, error) {
 
 	resp, err := f.graphqlRequestWithLoginRetry(request)
 	if err != nil {
-		return "", fmt.Errorf(T("error_failed_query_sku"), err)
-	}
-
-	var responses []struct {
-		Data struct {
-			Store struct {
-				Listing struct {
-					Skus []struct {
-						ID    json.Number `json:"id"`
-						Title string      `json:"title"`
-					} `json:"skus"`
-				} `json:"listing"`
-			} `json:"store"`
-		} `json:"data"`
+		return "", TError("error_failed_query_sku", "error", err)
 	}
 
-	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
-		return "", fmt.Errorf(T("error_failed_parse_sku"), err)
+	skus, err := decodeListingSkus(resp)
+	if err != nil {
+		return "", TError("error_failed_parse_sku", "error", err)
 	}
 
-	if len(responses) == 0 || len(responses[0].Data.Store.Listing.Skus) == 0 {
-		return "", fmt.Errorf(T("sku_no_sku_found"), skuSlug)
+	if len(skus) == 0 || skus[0].ID == "" {
+		return "", TError("sku_no_sku_found", "slug", skuSlug)
 	}
 
-	skuID := responses[0].Data.Store.Listing.Skus[0].ID.String()
-	fmt.Printf(T("sku_id_found_with_title")+"\n", skuID, responses[0].Data.Store.Listing.Skus[0].Title)
+	skuID := skus[0].ID.String()
+	f.reporter.Info(T("sku_id_found_with_title", "sku_id", skuID, "title", skus[0].Title))
 
 	return skuID, nil
 }
@@ -299,26 +321,26 @@ func (f *FastCheckout) GetSKUFromURL(itemURL string) (string, error) {
 func (f *FastCheckout) GetSKUFromActivePage(automation *Automation) (string, error) {
 	// Use cached SKU slug if available (extracted before login via HTTP)
 	if automation != nil && automation.cachedSKU != "" {
-		fmt.Printf(T("sku_using_cached_slug")+"\n", automation.cachedSKU)
+		f.reporter.Info(T("sku_using_cached_slug", "slug", automation.cachedSKU))
 		return f.getSKUIDFromSlug(automation.cachedSKU)
 	}
 
 	// Fallback: Extract fresh using HTTP if not cached
-	fmt.Println(T("sku_extracting_via_http"))
+	f.reporter.Info(T("sku_extracting_via_http"))
 
 	if automation == nil || automation.page == nil {
-		return "", fmt.Errorf(T("sku_browser_not_available"))
+		return "", TError("sku_browser_not_available")
 	}
 
 	// Get current URL from the active page
 	currentURL, err := automation.page.Eval(`() => window.location.href`)
 	if err != nil || currentURL.Value.Str() == "" {
-		return "", fmt.Errorf(T("sku_could_not_get_url"))
+		return "", TError("sku_could_not_get_url")
 	}
 	itemURL := currentURL.Value.Str()
 
 	// Use HTTP client to fetch page (works regardless of login state)
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := f.newClient(30*time.Second, true)
 	req, err := http.NewRequest("GET", itemURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create HTTP request: %w", err)
@@ -352,7 +374,7 @@ func (f *FastCheckout) GetSKUFromActivePage(automation *Automation) (string, err
 
 	if len(matches) > 1 {
 		skuSlugStr := matches[1]
-		fmt.Printf(T("sku_extracted_http")+"\n", skuSlugStr)
+		f.reporter.Info(T("sku_extracted_http", "slug", skuSlugStr))
 		// Cache for future use
 		if automation != nil {
 			automation.cachedSKU = skuSlugStr
@@ -360,29 +382,15 @@ func (f *FastCheckout) GetSKUFromActivePage(automation *Automation) (string, err
 		return f.getSKUIDFromSlug(skuSlugStr)
 	}
 
-	return "", fmt.Errorf(T("sku_slug_not_found"))
+	return "", TError("sku_slug_not_found")
 }
 
 func (f *FastCheckout) getSKUIDFromSlug(skuSlugStr string) (string, error) {
-	fmt.Printf(T("sku_querying_for_slug")+"\n", skuSlugStr)
-
-	if f.config.DebugMode {
-		fmt.Printf(T("debug_sku_slug_details")+"\n", skuSlugStr, len(skuSlugStr))
-	}
-
-	query := `query GetSkus($query: SearchQuery!) {
-  store(name: "pledge", browse: true) {
-    search(query: $query) {
-      resources {
-        id
-        slug
-        __typename
-      }
-      __typename
-    }
-    __typename
-  }
-}`
+	f.reporter.Info(T("sku_querying_for_slug", "slug", skuSlugStr))
+
+	f.reporter.Debug(T("debug_sku_slug_details", "slug", skuSlugStr, "length", len(skuSlugStr)))
+
+	query := operation("GetSkus")
 
 	request := []GraphQLRequest{
 		{
@@ -400,31 +408,20 @@ func (f *FastCheckout) getSKUIDFromSlug(skuSlugStr string) (string, error) {
 
 	resp, err := f.graphqlRequestWithLoginRetry(request)
 	if err != nil {
-		return "", fmt.Errorf(T("error_getskus_failed"), err)
+		return "", TError("error_getskus_failed", "error", err)
 	}
 
-	var responses []struct {
-		Data struct {
-			Store struct {
-				Search struct {
-					Resources []struct {
-						ID string `json:"id"`
-					} `json:"resources"`
-				} `json:"search"`
-			} `json:"store"`
-		} `json:"data"`
-	}
-
-	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
-		return "", fmt.Errorf(T("error_failed_parse_getskus"), err)
+	ids, err := decodeSkuSearch(resp)
+	if err != nil {
+		return "", TError("error_failed_parse_getskus", "error", err)
 	}
 
-	if len(responses) == 0 || len(responses[0].Data.Store.Search.Resources) == 0 {
-		return "", fmt.Errorf(T("sku_no_sku_found"), skuSlugStr)
+	if len(ids) == 0 {
+		return "", TError("sku_no_sku_found", "slug", skuSlugStr)
 	}
 
-	skuID := responses[0].Data.Store.Search.Resources[0].ID
-	fmt.Printf(T("sku_id_found")+"\n", skuID)
+	skuID := ids[0]
+	f.reporter.Info(T("sku_id_found", "sku_id", skuID))
 
 	return skuID, nil
 }
@@ -469,6 +466,24 @@ func isCaptchaError(err error) bool {
 		strings.Contains(errStr, "CAPTCHA")
 }
 
+// classifyValidateError groups a failed cart validation for the dashboard and JSON output
+func classifyValidateError(err error) ValidateErrorClass {
+	is4226, is4227 := isPaymentAuthError(err)
+	switch {
+	case is4227:
+		return ValidatePayment4227
+	case is4226:
+		return ValidatePayment4226
+	case isOutOfStockError(err):
+		return ValidateOutOfStock
+	case isRateLimitError(err):
+		return ValidateRateLimit
+	case isCaptchaError(err):
+		return ValidateCaptcha
+	}
+	return ValidateOther
+}
+
 func isNotLoggedInError(err error) bool {
 	if err == nil {
 		return false
@@ -541,7 +556,7 @@ func (f *FastCheckout) GetRecaptchaToken(automation *Automation, action string)
 
 	checkReady, err := page.Eval(`() => typeof grecaptcha !== 'undefined' && typeof grecaptcha.enterprise !== 'undefined'`)
 	if err != nil || !checkReady.Value.Bool() {
-		return "", fmt.Errorf(T("error_recaptcha_not_loaded"))
+		return "", TError("error_recaptcha_not_loaded")
 	}
 
 	f.simulateHumanBehavior(page)
@@ -607,7 +622,7 @@ func (f *FastCheckout) GetRecaptchaToken(automation *Automation, action string)
 
 	_, err = page.Eval(startScript)
 	if err != nil {
-		return "", fmt.Errorf(T("error_recaptcha_execution_failed"), err)
+		return "", TError("error_recaptcha_execution_failed", "error", err)
 	}
 
 	// OPTIMIZED: Faster token checking for speed
@@ -631,23 +646,18 @@ func (f *FastCheckout) GetRecaptchaToken(automation *Automation, action string)
 			if err == nil {
 				tokenStr := checkToken.Value.Str()
 				if tokenStr != "" && len(tokenStr) > 100 {
-					if f.config.DebugMode {
-						tokenPreview := tokenStr
-						if len(tokenStr) > 50 {
-							tokenPreview = tokenStr[:50] + "..."
-						}
-						fmt.Printf(T("debug_recaptcha_token_success")+"\n", tokenPreview, len(tokenStr))
-					}
+					registerSecret(tokenStr)
+					f.reporter.Debug(T("debug_recaptcha_token_success", "token", redactedValue, "length", len(tokenStr)))
 					return tokenStr, nil
-				} else if f.config.DebugMode && i == 0 {
-					fmt.Printf(T("debug_recaptcha_token_invalid")+"\n", tokenStr, len(tokenStr))
+				} else if i == 0 {
+					f.reporter.Debug(T("debug_recaptcha_token_invalid", "token", tokenStr, "length", len(tokenStr)))
 				}
 			}
-		} else if f.config.DebugMode && i == 0 {
-			fmt.Printf(T("debug_recaptcha_waiting_state")+"\n", debugStr)
+		} else if i == 0 {
+			f.reporter.Debug(T("debug_recaptcha_waiting_state", "state", debugStr))
 		}
 
-		if f.config.DebugMode && i == 0 {
+		if i == 0 {
 			tokenTypeCheck, _ := page.Eval(`() => typeof window.__specterToken`)
 			tokenType := "unknown"
 			if tokenTypeCheck != nil {
@@ -660,8 +670,7 @@ func (f *FastCheckout) GetRecaptchaToken(automation *Automation, action string)
 				callbackInvoked = callbackCheck.Value.Bool()
 			}
 
-			fmt.Printf(T("debug_recaptcha_token_check")+"\n",
-				i, debugStr, tokenType, callbackInvoked)
+			f.reporter.Debug(T("debug_recaptcha_token_check", "check", i, "state", debugStr, "type", tokenType, "callback_invoked", callbackInvoked))
 		}
 
 		checkError, err := page.Eval(`() => {
@@ -672,7 +681,7 @@ func (f *FastCheckout) GetRecaptchaToken(automation *Automation, action string)
 		}`)
 		if err == nil && checkError.Value.Str() != "" {
 			errMsg := checkError.Value.Str()
-			return "", fmt.Errorf(T("error_recaptcha_execution_error"), errMsg)
+			return "", TError("error_recaptcha_execution_error", "error", errMsg)
 		}
 	}
 
@@ -683,10 +692,10 @@ func (f *FastCheckout) GetRecaptchaToken(automation *Automation, action string)
 	}
 
 	if debugStr == "null_token" {
-		return "", fmt.Errorf(T("error_recaptcha_automation_detected"))
+		return "", TError("error_recaptcha_automation_detected")
 	}
 
-	return "", fmt.Errorf(T("error_recaptcha_timeout_debug"), debugStr)
+	return "", TError("error_recaptcha_timeout_debug", "state", debugStr)
 }
 
 // GetOrRefreshCachedRecaptchaToken returns a cached reCAPTCHA token if it's less than 60 seconds old,
@@ -700,19 +709,15 @@ func (f *FastCheckout) GetOrRefreshCachedRecaptchaToken(automation *Automation,
 
 	// Use cached token if it's less than 60 seconds old (well under 2-minute expiration)
 	if f.cachedRecaptchaToken != "" && tokenAge < 60*time.Second {
-		if f.config.DebugMode {
-			fmt.Printf(T("debug_recaptcha_reusing")+"\n",
-				tokenAge.Round(time.Second),
-				(60*time.Second - tokenAge).Round(time.Second))
-		}
+		f.reporter.Debug(T("debug_recaptcha_reusing", "age", tokenAge.Round(time.Second), "valid_for", (60*time.Second - tokenAge).Round(time.Second)))
 		return f.cachedRecaptchaToken, nil
 	}
 
 	// Token is missing or expired (>60s) - generate fresh token
 	if f.cachedRecaptchaToken != "" {
-		fmt.Printf(T("recaptcha_expired_generating")+"\n", tokenAge.Round(time.Second))
+		f.reporter.Info(T("recaptcha_expired_generating", "age", tokenAge.Round(time.Second)))
 	} else {
-		fmt.Println(T("recaptcha_generating_initial"))
+		f.reporter.Info(T("recaptcha_generating_initial"))
 	}
 
 	token, err := f.GetRecaptchaToken(automation, action)
@@ -725,47 +730,25 @@ func (f *FastCheckout) GetOrRefreshCachedRecaptchaToken(automation *Automation,
 	f.cachedRecaptchaTimestamp = now
 
 	if token != "" && len(token) > 10 {
-		tokenPreview := token
-		if len(token) > 30 {
-			tokenPreview = token[:30] + "..."
-		}
-		fmt.Printf(T("recaptcha_cached_fresh")+"\n", tokenPreview)
+		registerSecret(token)
+		f.reporter.Info(T("recaptcha_cached_fresh", "token", maskSecret(token)))
 	}
 
 	return token, nil
 }
 
 func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
-	fmt.Println(T("cart_adding_api_retry"))
-	fmt.Printf(T("cart_debug_sku_id")+"\n", skuID)
+	f.reporter.Info(T("cart_adding_api_retry"))
+	f.reporter.Debug(T("cart_debug_sku_id", "sku_id", skuID))
 
 	retryDuration := f.config.RetryDurationSeconds
-	fmt.Printf(T("cart_will_retry_seconds")+"\n", retryDuration)
+	f.reporter.Info(T("cart_will_retry_seconds", "count", retryDuration))
 
 	startTime := time.Now()
 	retryDeadline := startTime.Add(time.Duration(retryDuration) * time.Second)
 	attemptNum := 0
 
-	mutation := `mutation AddCartMultiItemMutation($query: [CartAddInput!]) {
-  store(name: "pledge") {
-    cart {
-      mutations {
-        addMany(query: $query) {
-          count
-          resources {
-            id
-            title
-            __typename
-          }
-          __typename
-        }
-        __typename
-      }
-      __typename
-    }
-    __typename
-  }
-}`
+	mutation := operation("AddCartMultiItemMutation")
 
 	for {
 		attemptNum++
@@ -787,29 +770,20 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 		select {
 		case token := <-tokenChan:
 			recaptchaToken = token
-			if f.config.DebugMode && attemptNum <= 3 {
-				if recaptchaToken != "" && recaptchaToken != "null" && recaptchaToken != "undefined" {
-					tokenPreview := recaptchaToken
-					if len(recaptchaToken) > 50 {
-						tokenPreview = recaptchaToken[:50] + "..."
-					}
-					fmt.Printf(T("debug_attempt_got_token")+"\n", attemptNum, tokenPreview, len(recaptchaToken))
-				} else {
-					fmt.Printf(T("debug_attempt_invalid_token")+"\n", attemptNum, recaptchaToken)
-				}
+			if recaptchaToken != "" && recaptchaToken != "null" && recaptchaToken != "undefined" {
+				registerSecret(recaptchaToken)
+				debugAttempt(f.reporter, attemptNum, T("debug_attempt_got_token", "attempt", attemptNum, "token", redactedValue, "length", len(recaptchaToken)))
+			} else {
+				debugAttempt(f.reporter, attemptNum, T("debug_attempt_invalid_token", "attempt", attemptNum, "token", redactText(recaptchaToken)))
 			}
 		case err := <-tokenErrChan:
-			if f.config.DebugMode && attemptNum <= 3 {
-				fmt.Printf(T("debug_attempt_error")+"\n", attemptNum, err)
-			}
+			debugAttempt(f.reporter, attemptNum, T("debug_attempt_error", "attempt", attemptNum, "error", err))
 			if strings.Contains(err.Error(), "automation detected") && attemptNum == 1 {
-				fmt.Println(T("recaptcha_warning_automation_detected"))
-				fmt.Println(T("recaptcha_warning_may_fail"))
+				f.reporter.Warn(T("recaptcha_warning_automation_detected"))
+				f.reporter.Info(T("recaptcha_warning_may_fail"))
 			}
 		case <-time.After(5 * time.Second):
-			if f.config.DebugMode && attemptNum <= 3 {
-				fmt.Printf(T("debug_attempt_timeout")+"\n", attemptNum)
-			}
+			debugAttempt(f.reporter, attemptNum, T("debug_attempt_timeout", "attempt", attemptNum))
 		}
 
 		// Match the exact structure from manual browser add-to-cart
@@ -822,12 +796,10 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 			},
 		}
 
-		if f.config.DebugMode && attemptNum <= 3 {
-			if recaptchaToken != "" && recaptchaToken != "null" && recaptchaToken != "undefined" && len(recaptchaToken) > 10 {
-				fmt.Printf(T("debug_attempt_not_using_token")+"\n", attemptNum, len(recaptchaToken))
-			} else {
-				fmt.Printf(T("debug_attempt_no_token")+"\n", attemptNum)
-			}
+		if recaptchaToken != "" && recaptchaToken != "null" && recaptchaToken != "undefined" && len(recaptchaToken) > 10 {
+			debugAttempt(f.reporter, attemptNum, T("debug_attempt_not_using_token", "attempt", attemptNum, "length", len(recaptchaToken)))
+		} else {
+			debugAttempt(f.reporter, attemptNum, T("debug_attempt_no_token", "attempt", attemptNum))
 		}
 
 		request := []GraphQLRequest{
@@ -838,25 +810,23 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 			},
 		}
 
-		if f.config.DebugMode && attemptNum == 1 {
+		if attemptNum == 1 {
 			jsonData, _ := json.MarshalIndent(request, "", "  ")
-			fmt.Printf(T("debug_request_body")+"\n", string(jsonData))
+			f.reporter.Debug(T("debug_request_body", "body", redactText(string(jsonData))))
 		}
 
 		resp, err := f.graphqlRequestWithLoginRetry(request)
 
-		if f.config.DebugMode && attemptNum <= 3 {
-			fmt.Printf(T("debug_attempt_response")+"\n", attemptNum, resp)
-			if err != nil {
-				fmt.Printf(T("debug_attempt_error_details")+"\n", attemptNum, err)
-			}
+		debugAttempt(f.reporter, attemptNum, T("debug_attempt_response", "attempt", attemptNum, "response", redactText(resp)))
+		if err != nil {
+			debugAttempt(f.reporter, attemptNum, T("debug_attempt_error_details", "attempt", attemptNum, "error", redactText(err.Error())))
 		}
 
 		if err == nil {
 			elapsed := time.Since(startTime)
-			fmt.Println(T("cart_added_successfully"))
+			f.reporter.Info(T("cart_added_successfully"))
 			if attemptNum > 1 {
-				fmt.Printf(T("cart_success_after_attempts")+"\n", attemptNum, elapsed)
+				f.reporter.Info(T("cart_success_after_attempts", "count", attemptNum, "elapsed", elapsed))
 			}
 			return nil
 		}
@@ -865,8 +835,8 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 
 		if remaining <= 0 {
 			elapsed := time.Since(startTime)
-			fmt.Printf(T("cart_sale_window_expired")+"\n", attemptNum, elapsed)
-			return fmt.Errorf(T("error_add_cart_attempts"), attemptNum, err)
+			f.reporter.Error(T("cart_sale_window_expired", "count", attemptNum, "elapsed", elapsed))
+			return TError("error_add_cart_attempts", "count", attemptNum, "error", err)
 		}
 
 		var delay time.Duration
@@ -880,16 +850,14 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 			delayMs := f.config.Payment4227MinMs + rand.Intn(f.config.Payment4227MaxMs-f.config.Payment4227MinMs+1)
 			delay = time.Duration(delayMs) * time.Millisecond
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf(T("cart_payment_auth_4227_retry")+"\n",
-					attemptNum, delayMs, remaining.Round(time.Second))
+				f.reporter.Info(T("cart_payment_auth_4227_retry", "attempt", attemptNum, "delay_ms", delayMs, "remaining", remaining.Round(time.Second)))
 			}
 		} else if is4226 {
 			// Payment auth error 4226 - configurable backoff
 			delayMs := f.config.Payment4226MinMs + rand.Intn(f.config.Payment4226MaxMs-f.config.Payment4226MinMs+1)
 			delay = time.Duration(delayMs) * time.Millisecond
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf(T("cart_payment_auth_4226_retry")+"\n",
-					attemptNum, delayMs, remaining.Round(time.Second))
+				f.reporter.Info(T("cart_payment_auth_4226_retry", "attempt", attemptNum, "delay_ms", delayMs, "remaining", remaining.Round(time.Second)))
 			}
 		} else if isCaptchaFail {
 			// Minimal delay for CAPTCHA - just retry immediately with new token
@@ -897,22 +865,19 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 			delay = time.Duration(delayMs) * time.Millisecond
 
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf(T("cart_captcha_fast_retry")+"\n",
-					attemptNum, delayMs, remaining.Round(time.Second))
+				f.reporter.Info(T("cart_captcha_fast_retry", "attempt", attemptNum, "delay_ms", delayMs, "remaining", remaining.Round(time.Second)))
 			}
 		} else if isRateLimited {
 			// Rate limit handling - configurable
 			delayMs := f.config.RateLimitMinMs + rand.Intn(f.config.RateLimitMaxMs-f.config.RateLimitMinMs+1)
 			delay = time.Duration(delayMs) * time.Millisecond
-			fmt.Printf(T("cart_rate_limited_retry")+"\n",
-				attemptNum, delayMs, remaining.Round(time.Second))
+			f.reporter.Warn(T("cart_rate_limited_retry", "attempt", attemptNum, "delay_ms", delayMs, "remaining", remaining.Round(time.Second)))
 		} else if isOutOfStock {
 			// Out of stock - configurable delay
 			delay = time.Duration(f.config.OutOfStockDelayMs) * time.Millisecond
 
 			if attemptNum%10 == 0 {
-				fmt.Printf(T("cart_out_of_stock_retry")+"\n",
-					attemptNum, remaining.Round(time.Second))
+				f.reporter.Info(T("cart_out_of_stock_retry", "attempt", attemptNum, "remaining", remaining.Round(time.Second)))
 			}
 		} else {
 			// Generic/other errors - configurable delay
@@ -920,8 +885,7 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 
 			attemptDuration := time.Since(attemptStart)
 			if attemptNum <= 5 || attemptNum%20 == 0 {
-				fmt.Printf(T("cart_attempt_failed_retry")+"\n",
-					attemptNum, attemptDuration, f.config.GenericErrorDelayMs, remaining.Round(time.Second))
+				f.reporter.Warn(T("cart_attempt_failed_retry", "attempt", attemptNum, "duration", attemptDuration, "delay_ms", f.config.GenericErrorDelayMs, "remaining", remaining.Round(time.Second)))
 			}
 		}
 
@@ -933,29 +897,10 @@ func (f *FastCheckout) AddToCart(skuID string, automation *Automation) error {
 	}
 }
 
-func (f *FastCheckout) GetCartTotals() (cartTotal float64, maxCredit float64, err error) {
-	fmt.Println(T("cart_querying_totals"))
-
-	query := `query CartSummaryViewQuery($storeFront: String) {
-  store(name: $storeFront) {
-    cart {
-      totals {
-        total
-        credits {
-          amount
-          maxApplicable
-        }
-      }
-    }
-  }
-  customer {
-    ledger(ledgerCode: "credit") {
-      amount {
-        value
-      }
-    }
-  }
-}`
+func (f *FastCheckout) GetCartTotals() (cartTotal Money, maxCredit Money, err error) {
+	f.reporter.Info(T("cart_querying_totals"))
+
+	query := operation("CartSummaryViewQuery")
 
 	request := []GraphQLRequest{
 		{
@@ -969,90 +914,78 @@ func (f *FastCheckout) GetCartTotals() (cartTotal float64, maxCredit float64, er
 
 	resp, err := f.graphqlRequestWithLoginRetry(request)
 	if err != nil {
-		return 0, 0, fmt.Errorf(T("error_failed_query_cart_totals"), err)
-	}
-
-	var responses []map[string]interface{}
-	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
-		return 0, 0, fmt.Errorf(T("error_failed_parse_cart_totals"), err)
+		return Money{}, Money{}, TError("error_failed_query_cart_totals", "error", err)
 	}
 
-	if len(responses) == 0 {
-		return 0, 0, fmt.Errorf(T("error_empty_cart_totals"))
+	totals, availableCredit, err := decodeCartTotals(resp, f.currency())
+	if err != nil {
+		return Money{}, Money{}, TError("error_failed_parse_cart_totals", "error", err)
 	}
+	cartTotal = totals.Total
+	maxCredit = totals.MaxCredit
 
-	data := responses[0]["data"].(map[string]interface{})
-	store := data["store"].(map[string]interface{})
-	cart := store["cart"].(map[string]interface{})
-	totals := cart["totals"].(map[string]interface{})
+	f.reporter.Info(T("cart_totals_result", "total", cartTotal))
+	f.reporter.Info(T("cart_available_credit_result", "credit", availableCredit))
+	f.reporter.Info(T("cart_max_credit_result", "max_credit", maxCredit))
 
-	cartTotalCents := totals["total"].(float64)
-	cartTotal = cartTotalCents / 100.0
+	return cartTotal, maxCredit, nil
+}
 
-	credits := totals["credits"].(map[string]interface{})
-	maxCreditCents := credits["maxApplicable"].(float64)
-	maxCredit = maxCreditCents / 100.0
+type CartItem struct {
+	LineItemID string // Cart line id, needed to remove or resize the line
+	Name       string
+	Price      Money // Unit price with any VAT the store includes
+	SKUID      string
+	Quantity   int
+}
 
-	customer := data["customer"].(map[string]interface{})
-	ledger := customer["ledger"].(map[string]interface{})
-	ledgerAmount := ledger["amount"].(map[string]interface{})
-	availableCreditCents := ledgerAmount["value"].(float64)
-	availableCredit := availableCreditCents / 100.0
+type CartInfo struct {
+	Total         Money
+	Tax           Money // Taxes the store reports for the cart
+	TaxIncluded   bool  // Tax is part of the line prices (VAT) rather than added on top (sales tax)
+	MaxCredit     Money
+	CreditApplied Money // Store credit currently applied to the cart
+	Ledger        Money // Store credit balance on the account
+	Items         []CartItem
+}
 
-	fmt.Printf(T("cart_totals_result")+"\n", cartTotal)
-	fmt.Printf(T("cart_available_credit_result")+"\n", availableCredit)
-	fmt.Printf(T("cart_max_credit_result")+"\n", maxCredit)
+// taxOnTop returns the tax charged in addition to the line prices
+func (c *CartInfo) taxOnTop() Money {
+	if c.TaxIncluded {
+		return newMoney(0, c.Total.Currency)
+	}
+	return c.Tax
+}
 
-	return cartTotal, maxCredit, nil
+// expectedTotal is what the cart should cost, before credit, holding nothing but item
+func (c *CartInfo) expectedTotal(item CartItem) (Money, error) {
+	return item.Price.Mul(item.Quantity).Add(c.taxOnTop())
 }
 
-type CartItem struct {
-	Name     string
-	Price    float64
-	SKUID    string
-	Quantity int
+// targetItem returns the line holding skuID. Lines the user chose to keep may come
+// first, so the first line only stands in when the target isn't in the cart at all.
+func (c *CartInfo) targetItem(skuID string) (CartItem, bool) {
+	if item, ok := findCartItem(c.Items, skuID); ok {
+		return item, true
+	}
+	if len(c.Items) > 0 {
+		return c.Items[0], true
+	}
+	return CartItem{}, false
 }
 
-type CartInfo struct {
-	Total      float64
-	MaxCredit  float64
-	Items      []CartItem
+// currency returns the configured store currency
+func (f *FastCheckout) currency() string {
+	if f.config.Currency == "" {
+		return defaultCurrency
+	}
+	return strings.ToUpper(f.config.Currency)
 }
 
 // GetCartTotalsAndItems combines GetCartTotals and GetCartItems into a single query
 // for performance optimization (saves 50-150ms per call)
 func (f *FastCheckout) GetCartTotalsAndItems() (*CartInfo, error) {
-	query := `query CombinedCartQuery($storeFront: String) {
-  store(name: $storeFront) {
-    cart {
-      totals {
-        total
-        credits {
-          amount
-          maxApplicable
-        }
-      }
-      lineItems {
-        id
-        skuId
-        sku {
-          title
-        }
-        unitPriceWithTax {
-          amount
-        }
-        qty
-      }
-    }
-  }
-  customer {
-    ledger(ledgerCode: "credit") {
-      amount {
-        value
-      }
-    }
-  }
-}`
+	query := operation("CombinedCartQuery")
 
 	request := []GraphQLRequest{
 		{
@@ -1066,96 +999,19 @@ func (f *FastCheckout) GetCartTotalsAndItems() (*CartInfo, error) {
 
 	resp, err := f.graphqlRequestWithLoginRetry(request)
 	if err != nil {
-		return nil, fmt.Errorf(T("error_failed_query_cart_info"), err)
-	}
-
-	var responses []struct {
-		Data struct {
-			Store struct {
-				Cart struct {
-					Totals struct {
-						Total   float64 `json:"total"`
-						Credits struct {
-							Amount        float64 `json:"amount"`
-							MaxApplicable float64 `json:"maxApplicable"`
-						} `json:"credits"`
-					} `json:"totals"`
-					LineItems []struct {
-						ID     string      `json:"id"`
-						SkuID  json.Number `json:"skuId"`
-						Sku    struct {
-							Title string `json:"title"`
-						} `json:"sku"`
-						UnitPriceWithTax struct {
-							Amount float64 `json:"amount"`
-						} `json:"unitPriceWithTax"`
-						Qty int `json:"qty"`
-					} `json:"lineItems"`
-				} `json:"cart"`
-			} `json:"store"`
-			Customer struct {
-				Ledger struct {
-					Amount struct {
-						Value float64 `json:"value"`
-					} `json:"amount"`
-				} `json:"ledger"`
-			} `json:"customer"`
-		} `json:"data"`
-	}
-
-	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
-		return nil, fmt.Errorf(T("error_failed_parse_cart_info"), err)
-	}
-
-	if len(responses) == 0 {
-		return nil, fmt.Errorf(T("error_empty_cart_info"))
-	}
-
-	data := responses[0].Data
-	cart := data.Store.Cart
-	totals := cart.Totals
-
-	cartTotal := totals.Total / 100.0
-	maxCredit := totals.Credits.MaxApplicable / 100.0
-
-	items := make([]CartItem, 0, len(cart.LineItems))
-	for _, lineItem := range cart.LineItems {
-		skuIDStr := string(lineItem.SkuID)
-		price := lineItem.UnitPriceWithTax.Amount / 100.0
-
-		items = append(items, CartItem{
-			Name:     lineItem.Sku.Title,
-			Price:    price,
-			SKUID:    skuIDStr,
-			Quantity: lineItem.Qty,
-		})
+		return nil, TError("error_failed_query_cart_info", "error", err)
 	}
 
-	return &CartInfo{
-		Total:     cartTotal,
-		MaxCredit: maxCredit,
-		Items:     items,
-	}, nil
+	cartInfo, err := decodeCartInfo(resp, f.currency())
+	if err != nil {
+		return nil, TError("error_failed_parse_cart_info", "error", err)
+	}
+
+	return cartInfo, nil
 }
 
 func (f *FastCheckout) GetCartItems() ([]CartItem, error) {
-	query := `query StepperQuery($storeFront: String) {
-  store(name: $storeFront) {
-    cart {
-      lineItems {
-        id
-        skuId
-        sku {
-          title
-        }
-        unitPriceWithTax {
-          amount
-        }
-        qty
-      }
-    }
-  }
-}`
+	query := operation("StepperQuery")
 
 	request := []GraphQLRequest{
 		{
@@ -1169,45 +1025,12 @@ func (f *FastCheckout) GetCartItems() ([]CartItem, error) {
 
 	resp, err := f.graphqlRequestWithLoginRetry(request)
 	if err != nil {
-		return nil, fmt.Errorf(T("error_failed_query_cart_items"), err)
-	}
-
-	var responses []struct {
-		Data struct {
-			Store struct {
-				Cart struct {
-					LineItems []struct {
-						ID     string      `json:"id"`
-						SkuID  json.Number `json:"skuId"`
-						Sku    struct {
-							Title string `json:"title"`
-						} `json:"sku"`
-						UnitPriceWithTax struct {
-							Amount float64 `json:"amount"`
-						} `json:"unitPriceWithTax"`
-						Qty int `json:"qty"`
-					} `json:"lineItems"`
-				} `json:"cart"`
-			} `json:"store"`
-		} `json:"data"`
-	}
-
-	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
-		return nil, fmt.Errorf(T("error_failed_parse_cart_items"), err)
-	}
-
-	if len(responses) == 0 {
-		return nil, fmt.Errorf(T("error_empty_cart_items"))
-	}
-
-	items := []CartItem{}
-	for _, lineItem := range responses[0].Data.Store.Cart.LineItems {
-		items = append(items, CartItem{
-			Name:     lineItem.Sku.Title,
-			Price:    lineItem.UnitPriceWithTax.Amount / 100.0, // Convert cents to dollars
-			SKUID:    lineItem.SkuID.String(),
-			Quantity: lineItem.Qty,
-		})
+		return nil, TError("error_failed_query_cart_items", "error", err)
+	}
+
+	items, err := decodeCartItems(resp, f.currency())
+	if err != nil {
+		return nil, TError("error_failed_parse_cart_items", "error", err)
 	}
 
 	return items, nil
@@ -1218,156 +1041,100 @@ func (f *FastCheckout) GetCartItems() ([]CartItem, error) {
 // - (false, nil): Cart has issues but user chose to continue with current contents (skip adding)
 // - (false, error): User cancelled or validation error occurred
 //
-// OPTIMIZATION: Now accepts items as parameter to avoid redundant GraphQL query
-func (f *FastCheckout) ValidateCartContents(expectedSKUID string, cartTotal float64, items []CartItem) (bool, error) {
+// When the cart is cleaned up, cartInfo is replaced with the verified cart.
+//
+// OPTIMIZATION: Now accepts the cart as parameter to avoid redundant GraphQL query
+func (f *FastCheckout) ValidateCartContents(expectedSKUID string, cartInfo *CartInfo) (bool, error) {
+	cartTotal := cartInfo.Total
+	items := cartInfo.Items
 
 	// Empty cart is normal - proceed with adding
 	if len(items) == 0 {
-		fmt.Println(T("cart_empty_will_add"))
+		f.reporter.Info(T("cart_empty_will_add"))
 		return true, nil // Add to cart
 	}
 
 	// Check if cart already has exactly what we want: 1 item, correct SKU, quantity=1
 	if len(items) == 1 && items[0].SKUID == expectedSKUID && items[0].Quantity == 1 {
-		expectedTotal := items[0].Price
-		if cartTotal == expectedTotal {
+		expectedTotal, err := cartInfo.expectedTotal(items[0])
+		if err != nil {
+			return false, err
+		}
+		if cartTotal.Equal(expectedTotal) {
 			// Perfect! Cart already has correct item at full price, don't add again
-			fmt.Printf(T("cart_already_contains_target")+"\n", items[0].Name, items[0].Price)
-			fmt.Println(T("cart_skip_duplicate"))
+			f.reporter.Info(T("cart_already_contains_target", "name", items[0].Name, "price", items[0].Price))
+			f.reporter.Info(T("cart_skip_duplicate"))
 			return false, nil // Don't add, proceed with existing cart
-		} else if cartTotal == 0 {
+		} else if cartTotal.IsZero() {
 			// Cart total is $0 - store credit already applied from previous run
-			fmt.Printf(T("cart_already_contains_target")+"\n", items[0].Name, items[0].Price)
-			fmt.Println(T("cart_credit_already_applied"))
-			fmt.Println(T("cart_skip_add_and_credit"))
+			f.reporter.Info(T("cart_already_contains_target", "name", items[0].Name, "price", items[0].Price))
+			f.reporter.Info(T("cart_credit_already_applied"))
+			f.reporter.Info(T("cart_skip_add_and_credit"))
 			return false, nil // Don't add, proceed with existing cart
 		}
 		// If price doesn't match and isn't $0, fall through to show warning
 	}
 
 	// Cart has issues - multiple items, wrong items, quantity > 1, or price mismatch
-
-	// Cart has issues - either multiple items, wrong items, or quantity > 1
-	fmt.Println()
-	fmt.Println(T("cart_warning_header"))
-	fmt.Println()
-
-	// Calculate total items across all line items (considering quantities)
-	totalItems := 0
-	for _, item := range items {
-		totalItems += item.Quantity
-	}
-
-	if len(items) == 1 {
-		fmt.Printf(T("cart_warning_single_quantity")+"\n\n", items[0].Quantity, items[0].Name)
-	} else {
-		fmt.Printf(T("cart_warning_multiple_items")+"\n\n", totalItems, len(items))
-	}
-
-	for i, item := range items {
-		marker := "  "
-		if item.SKUID == expectedSKUID {
-			marker = "→ "
-		}
-
-		if item.Quantity > 1 {
-			fmt.Printf("%s%d. %s (Quantity: %d)\n", marker, i+1, item.Name, item.Quantity)
-		} else {
-			fmt.Printf("%s%d. %s\n", marker, i+1, item.Name)
-		}
-
-		fmt.Printf(T("cart_item_price_line")+"\n", item.Price, item.Quantity, item.Price*float64(item.Quantity))
-
-		if item.SKUID == expectedSKUID {
-			fmt.Println(T("cart_item_target_marker"))
-			if item.Quantity > 1 {
-				fmt.Printf(T("cart_item_quantity_warning")+"\n", item.Quantity)
-			}
-		}
-		fmt.Println()
-	}
-
-	fmt.Printf(T("cart_total_label")+"\n", cartTotal)
-
-	// Check if cart total matches expected for single item
-	if len(items) == 1 && items[0].SKUID == expectedSKUID && items[0].Quantity == 1 {
-		expectedTotal := items[0].Price
-		if cartTotal != expectedTotal {
-			fmt.Printf(T("cart_expected_total")+"\n", expectedTotal, items[0].Name)
+	warning := CartWarning{
+		Reason:    CartWarningWrongItem,
+		TargetSKU: expectedSKUID,
+		Items:     items,
+		Total:     cartTotal,
+		TaxOnTop:  cartInfo.taxOnTop(),
+	}
+	switch {
+	case len(items) > 1:
+		warning.Reason = CartWarningMultipleItems
+	case items[0].SKUID == expectedSKUID && items[0].Quantity > 1:
+		warning.Reason = CartWarningQuantity
+	case items[0].SKUID == expectedSKUID:
+		// Single correct item but cart total doesn't match
+		warning.Reason = CartWarningTotalMismatch
+		expected, err := cartInfo.expectedTotal(items[0])
+		if err != nil {
+			return false, err
 		}
+		warning.Expected = expected
 	}
-	fmt.Println()
-
-	// Determine the warning message
-	if len(items) > 1 {
-		fmt.Println(T("cart_multiple_items_warning"))
-	} else if len(items) == 1 && items[0].SKUID == expectedSKUID && items[0].Quantity > 1 {
-		fmt.Printf(T("cart_quantity_warning")+"\n", items[0].Quantity)
-		fmt.Printf(T("cart_quantity_purchase_details")+"\n",
-			items[0].Quantity, items[0].Name, items[0].Price*float64(items[0].Quantity))
-		fmt.Println()
-		fmt.Println(T("cart_quantity_limit_note"))
-	} else if len(items) == 1 && items[0].SKUID == expectedSKUID && items[0].Quantity == 1 {
-		// Single correct item but cart total doesn't match
-		expectedTotal := items[0].Price
-		fmt.Printf(T("cart_total_mismatch")+"\n", cartTotal, expectedTotal)
-		fmt.Println(T("cart_total_mismatch_reason"))
-	} else {
-		fmt.Println(T("wrong_item_warning"))
+	f.reporter.CartWarning(warning)
+	answer, err := f.prompter.Choose(Prompt{
+		Kind:    PromptCartMismatch,
+		Message: T("cart_choice_prompt"),
+		Options: []PromptAnswer{AnswerProceed, AnswerClean, AnswerAbort},
+	})
+	if err != nil {
+		return false, err
 	}
 
-	fmt.Println()
-	fmt.Println(T("cart_options_header"))
-	fmt.Println(T("cart_option_continue"))
-	fmt.Println(T("cart_option_cancel"))
-	fmt.Println()
-	fmt.Print(T("cart_choice_prompt"))
+	if answer == AnswerAbort {
+		f.reporter.Warn(T("cart_user_canceled"))
+		return false, TError("cart_error_user_canceled")
+	}
 
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		input, err := reader.ReadByte()
+	if answer == AnswerClean {
+		cleaned, err := f.CleanCart(expectedSKUID, items)
 		if err != nil {
-			return false, fmt.Errorf(T("cart_error_read_input"), err)
-		}
-
-		if input == '\n' || input == '\r' {
-			fmt.Println()
-			fmt.Println(T("cart_user_confirmed_current"))
-			return false, nil // Don't add to cart, use existing cart
-		}
-
-		if input == 27 { // ESC key
-			fmt.Println()
-			fmt.Println(T("cart_user_canceled"))
-			return false, fmt.Errorf(T("cart_error_user_canceled"))
+			return false, TError("error_cart_cleanup_failed", "error", err)
 		}
+		*cartInfo = *cleaned
+		return len(cleaned.Items) == 0, nil // Add the target only if cleanup emptied the cart
 	}
+
+	f.reporter.Info(T("cart_user_confirmed_current"))
+	return false, nil // Don't add to cart, use existing cart
 }
 
-func (f *FastCheckout) ApplyStoreCredit(amount float64) error {
-	fmt.Printf(T("credit_applying_api")+"\n", amount)
-
-	mutation := `mutation AddCreditMutation($amount: Float!, $storeFront: String) {
-  store(name: $storeFront) {
-    cart {
-      mutations {
-        credit_update(amount: $amount)
-      }
-      totals {
-        total
-        credits {
-          amount
-        }
-      }
-    }
-  }
-}`
+func (f *FastCheckout) ApplyStoreCredit(amount Money) error {
+	f.reporter.Info(T("credit_applying_api", "amount", amount))
+
+	mutation := operation("AddCreditMutation")
 
 	request := []GraphQLRequest{
 		{
 			OperationName: "AddCreditMutation",
 			Variables: map[string]interface{}{
-				"amount":     amount,
+				"amount":     amount.Major(),
 				"storeFront": "pledge",
 			},
 			Query: mutation,
@@ -1381,45 +1148,25 @@ func (f *FastCheckout) ApplyStoreCredit(amount float64) error {
 		if strings.Contains(errStr, "You don't have that many credits available") ||
 			strings.Contains(errStr, "CFUValidationException") && strings.Contains(errStr, "amount:") {
 			// User-friendly error message for insufficient credits
-			fmt.Println()
-			fmt.Println(T("credit_insufficient_error_header"))
-			fmt.Println()
-			fmt.Println(T("credit_insufficient_error_message"))
-			fmt.Printf(T("credit_insufficient_attempted_amount")+"\n", amount)
-			fmt.Println()
-			fmt.Println(T("credit_insufficient_instructions"))
-			fmt.Println()
+			f.reporter.Info("")
+			f.reporter.Info(T("credit_insufficient_error_header"))
+			f.reporter.Info("")
+			f.reporter.Info(T("credit_insufficient_error_message"))
+			f.reporter.Info(T("credit_insufficient_attempted_amount", "amount", amount))
+			f.reporter.Info("")
+			f.reporter.Info(T("credit_insufficient_instructions"))
+			f.reporter.Info("")
 			return fmt.Errorf("insufficient store credits available")
 		}
 		return fmt.Errorf("apply credit failed: %w", err)
 	}
 
-	fmt.Printf(T("credit_applied_response")+"\n", resp)
+	f.reporter.Info(T("credit_applied_response", "response", redactText(resp)))
 	return nil
 }
 
 func (f *FastCheckout) NextStep() error {
-	mutation := `mutation NextStepMutation($storeFront: String) {
-  store(name: $storeFront) {
-    cart {
-      mutations {
-        flow {
-          moveNext
-        }
-      }
-      flow {
-        steps {
-          step
-          action
-          active
-        }
-        current {
-          orderCreated
-        }
-      }
-    }
-  }
-}`
+	mutation := operation("NextStepMutation")
 
 	request := []GraphQLRequest{
 		{
@@ -1436,79 +1183,28 @@ func (f *FastCheckout) NextStep() error {
 		return fmt.Errorf("next step failed: %w", err)
 	}
 
-	var responses []struct {
-		Data struct {
-			Store struct {
-				Cart struct {
-					Flow struct {
-						Steps []struct {
-							Step   string `json:"step"`
-							Action string `json:"action"`
-							Active bool   `json:"active"`
-						} `json:"steps"`
-						Current struct {
-							OrderCreated bool `json:"orderCreated"`
-						} `json:"current"`
-					} `json:"flow"`
-				} `json:"cart"`
-			} `json:"store"`
-		} `json:"data"`
-	}
-
-	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
+	flow, err := decodeCartFlow("NextStepMutation", resp)
+	if err != nil {
 		return fmt.Errorf("failed to parse NextStep response: %w", err)
 	}
 
-	if len(responses) > 0 {
-		flow := responses[0].Data.Store.Cart.Flow
-
-		activeStep := "unknown"
-		for _, step := range flow.Steps {
-			if step.Active {
-				activeStep = step.Step
-				break
-			}
-		}
-
-		fmt.Printf(T("step_moved_to")+"\n", activeStep)
+	activeStep := flow.activeStep()
+	if activeStep == "" {
+		activeStep = "unknown"
+	}
+	f.reporter.Info(T("step_moved_to", "step", activeStep))
 
-		if flow.Current.OrderCreated {
-			fmt.Println(T("validation_order_created"))
-		}
+	if flow.Current != nil && flow.Current.OrderCreated {
+		f.reporter.Info(T("validation_order_created"))
 	}
 
 	return nil
 }
 
 func (f *FastCheckout) GetDefaultBillingAddress() (string, error) {
-	fmt.Println(T("address_fetching"))
-
-	query := `query AddressBookQuery($storeFront: String) {
-  store(name: $storeFront) {
-    addressBook {
-      id
-      defaultBilling
-      defaultShipping
-      company
-      firstname
-      lastname
-      addressLine
-      postalCode
-      phone
-      city
-      country {
-        id
-        name
-        code
-      }
-      region {
-        id
-        code
-        name
-      }
-    }
-  }
-}`
+	f.reporter.Info(T("address_fetching"))
+
+	query := operation("AddressBookQuery")
 
 	request := []GraphQLRequest{
 		{
@@ -1525,66 +1221,33 @@ func (f *FastCheckout) GetDefaultBillingAddress() (string, error) {
 		return "", fmt.Errorf("failed to query address book: %w", err)
 	}
 
-	var responses []struct {
-		Data struct {
-			Store struct {
-				AddressBook []struct {
-					ID             string `json:"id"`
-					DefaultBilling bool   `json:"defaultBilling"`
-					Firstname      string `json:"firstname"`
-					Lastname       string `json:"lastname"`
-					City           string `json:"city"`
-				} `json:"addressBook"`
-			} `json:"store"`
-		} `json:"data"`
-	}
-
-	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
+	addresses, err := decodeAddressBook(resp)
+	if err != nil {
 		return "", fmt.Errorf("failed to parse address book: %w", err)
 	}
 
-	if len(responses) == 0 || len(responses[0].Data.Store.AddressBook) == 0 {
+	if len(addresses) == 0 {
 		return "", fmt.Errorf("no addresses found in address book")
 	}
 
-	var addressID string
-	var addressName string
-	for _, addr := range responses[0].Data.Store.AddressBook {
+	addressID := addresses[0].ID
+	for _, addr := range addresses {
 		if addr.DefaultBilling {
 			addressID = addr.ID
-			addressName = fmt.Sprintf("%s %s, %s", addr.Firstname, addr.Lastname, addr.City)
 			break
 		}
 	}
 
-	if addressID == "" {
-		addr := responses[0].Data.Store.AddressBook[0]
-		addressID = addr.ID
-		addressName = fmt.Sprintf("%s %s, %s", addr.Firstname, addr.Lastname, addr.City)
-	}
-
-	fmt.Printf(T("address_found")+"\n", addressName, addressID)
+	// Billing names and cities never reach the console or logs
+	registerSecret(addressID)
+	f.reporter.Info(T("address_found", "address_id", maskSecret(addressID)))
 	return addressID, nil
 }
 
 func (f *FastCheckout) AssignBillingAddress(addressID string) error {
-	fmt.Printf(T("address_assigning")+"\n", addressID)
-
-	mutation := `mutation CartAddressAssignMutation($billing: ID, $shipping: ID, $storeFront: String) {
-  store(name: $storeFront) {
-    cart {
-      mutations {
-        assignAddresses(assign: {billing: $billing, shipping: $shipping})
-      }
-      billingAddress {
-        id
-        firstname
-        lastname
-        city
-      }
-    }
-  }
-}`
+	f.reporter.Info(T("address_assigning", "address_id", maskSecret(addressID)))
+
+	mutation := operation("CartAddressAssignMutation")
 
 	request := []GraphQLRequest{
 		{
@@ -1602,7 +1265,7 @@ func (f *FastCheckout) AssignBillingAddress(addressID string) error {
 		return fmt.Errorf("failed to assign address: %w", err)
 	}
 
-	fmt.Printf(T("address_assigned")+"\n", resp)
+	f.reporter.Info(T("address_assigned", "response", redactText(resp)))
 	return nil
 }
 
@@ -1611,7 +1274,7 @@ func (f *FastCheckout) ValidateCart(automation *Automation) error {
 }
 
 func (f *FastCheckout) ValidateCartWithDeadline(automation *Automation, deadline time.Time) error {
-	fmt.Println(T("validation_completing"))
+	f.reporter.Info(T("validation_completing"))
 
 	startTime := time.Now()
 
@@ -1620,10 +1283,10 @@ func (f *FastCheckout) ValidateCartWithDeadline(automation *Automation, deadline
 	if !deadline.IsZero() {
 		retryDeadline = deadline
 		remaining := deadline.Sub(startTime)
-		fmt.Printf(T("validation_retry_until_end")+"\n", remaining.Round(time.Second))
+		f.reporter.Info(T("validation_retry_until_end", "remaining", remaining.Round(time.Second)))
 	} else {
 		retryDeadline = startTime.Add(time.Duration(f.config.RetryDurationSeconds) * time.Second)
-		fmt.Printf(T("validation_retry_for_seconds")+"\n", f.config.RetryDurationSeconds)
+		f.reporter.Info(T("validation_retry_for_seconds", "count", f.config.RetryDurationSeconds))
 	}
 
 	// Generate mark ONCE and reuse for all retry attempts (matches browser behavior)
@@ -1643,49 +1306,20 @@ func (f *FastCheckout) ValidateCartWithDeadline(automation *Automation, deadline
 		if attemptNum == 1 || attemptNum%50 == 0 {
 			if !deadline.IsZero() {
 				// Timed sale mode - show time remaining in window
-				fmt.Printf(T("validation_attempt_sale_window")+"\n", attemptNum, remaining.Round(time.Second))
+				f.reporter.Info(T("validation_attempt_sale_window", "attempt", attemptNum, "remaining", remaining.Round(time.Second)))
 			} else if attemptNum%50 == 0 {
 				// Normal mode - only show every 50 attempts to reduce spam
-				fmt.Printf(T("validation_attempt_regular")+"\n", attemptNum, remaining.Round(time.Second))
+				f.reporter.Info(T("validation_attempt_regular", "attempt", attemptNum, "remaining", remaining.Round(time.Second)))
 			}
 		}
 
 		// Use cached token (refreshed automatically every 60 seconds)
 		recaptchaToken, err := f.GetOrRefreshCachedRecaptchaToken(automation, "store/cart/validate")
 		if err != nil {
-			fmt.Printf(T("validation_recaptcha_warning")+"\n", err)
+			f.reporter.Warn(T("validation_recaptcha_warning", "error", err))
 		}
 
-		mutation := `mutation CartValidateCartMutation($storeFront: String, $token: String, $mark: String) {
-  store(name: $storeFront) {
-    cart {
-      mutations {
-        validate(mark: $mark, token: $token)
-        __typename
-      }
-      flow {
-        steps {
-          step
-          action
-          finalStep
-          active
-          __typename
-        }
-        current {
-          orderCreated
-          __typename
-        }
-        __typename
-      }
-      __typename
-    }
-    order {
-      slug
-      __typename
-    }
-    __typename
-  }
-}`
+		mutation := operation("CartValidateCartMutation")
 
 		variables := map[string]interface{}{
 			"storeFront": "pledge",
@@ -1697,12 +1331,10 @@ func (f *FastCheckout) ValidateCartWithDeadline(automation *Automation, deadline
 		}
 
 		// Debug logging for validation mutation
-		if f.config.DebugMode && attemptNum <= 3 {
-			if recaptchaToken != "" && recaptchaToken != "null" && recaptchaToken != "undefined" && len(recaptchaToken) > 10 {
-				fmt.Printf("[DEBUG] Validation Attempt %d: Using reCAPTCHA token (len=%d) with mark=%s\n", attemptNum, len(recaptchaToken), mark)
-			} else {
-				fmt.Printf("[DEBUG] Validation Attempt %d: No valid reCAPTCHA token! mark=%s\n", attemptNum, mark)
-			}
+		if recaptchaToken != "" && recaptchaToken != "null" && recaptchaToken != "undefined" && len(recaptchaToken) > 10 {
+			debugAttempt(f.reporter, attemptNum, fmt.Sprintf("[DEBUG] Validation Attempt %d: Using reCAPTCHA token (len=%d) with mark=%s", attemptNum, len(recaptchaToken), mark))
+		} else {
+			debugAttempt(f.reporter, attemptNum, fmt.Sprintf("[DEBUG] Validation Attempt %d: No valid reCAPTCHA token! mark=%s", attemptNum, mark))
 		}
 
 		request := []GraphQLRequest{
@@ -1713,63 +1345,47 @@ func (f *FastCheckout) ValidateCartWithDeadline(automation *Automation, deadline
 			},
 		}
 
-		if f.config.DebugMode && attemptNum == 1 {
+		if attemptNum == 1 {
 			jsonData, _ := json.MarshalIndent(request, "", "  ")
-			fmt.Printf("[DEBUG] CartValidateCartMutation request body:\n%s\n", string(jsonData))
+			f.reporter.Debug(fmt.Sprintf("[DEBUG] CartValidateCartMutation request body:\n%s", redactText(string(jsonData))))
 		}
 
 		resp, err := f.graphqlRequestWithLoginRetry(request)
 
-		if f.config.DebugMode && attemptNum <= 3 {
-			fmt.Printf("[DEBUG] Validation Attempt %d response: %s\n", attemptNum, resp)
-			if err != nil {
-				fmt.Printf("[DEBUG] Validation Attempt %d error: %v\n", attemptNum, err)
-			}
+		debugAttempt(f.reporter, attemptNum, fmt.Sprintf("[DEBUG] Validation Attempt %d response: %s", attemptNum, redactText(resp)))
+		if err != nil {
+			debugAttempt(f.reporter, attemptNum, fmt.Sprintf("[DEBUG] Validation Attempt %d error: %v", attemptNum, redactText(err.Error())))
 		}
 
 		if err == nil {
-			var responses []struct {
-				Data struct {
-					Store struct {
-						Cart struct {
-							Flow struct {
-								Current struct {
-									OrderCreated bool `json:"orderCreated"`
-								} `json:"current"`
-							} `json:"flow"`
-						} `json:"cart"`
-						Order struct {
-							Slug string `json:"slug"`
-						} `json:"order"`
-					} `json:"store"`
-				} `json:"data"`
-			}
-
-			if err := json.Unmarshal([]byte(resp), &responses); err == nil && len(responses) > 0 {
-				orderSlug := responses[0].Data.Store.Order.Slug
-				orderCreated := responses[0].Data.Store.Cart.Flow.Current.OrderCreated
-
-				fmt.Printf(T("validation_order_slug")+"\n", orderSlug)
+			orderSlug, orderCreated, decodeErr := decodeValidateCart(resp)
+			if decodeErr != nil {
+				err = decodeErr
+			} else {
+				f.reporter.Info(T("validation_order_slug", "slug", orderSlug))
+				f.lastOrderSlug = orderSlug
 
 				if orderCreated {
-					fmt.Println(T("validation_order_created"))
+					f.reporter.Info(T("validation_order_created"))
 				}
 
 				elapsed := time.Since(startTime)
-				fmt.Printf(T("validation_success_attempts")+"\n", attemptNum, elapsed)
+				f.reporter.Info(T("validation_success_attempts", "count", attemptNum, "elapsed", elapsed))
 				return nil
 			}
 		}
 
+		f.reporter.ValidateAttempt(ValidateAttempt{Attempt: attemptNum, Class: classifyValidateError(err)})
+
 		remaining = retryDeadline.Sub(time.Now())
 
 		if remaining <= 0 {
 			elapsed := time.Since(startTime)
 			if !deadline.IsZero() {
-				fmt.Printf(T("validation_window_expired")+"\n", attemptNum, elapsed)
+				f.reporter.Error(T("validation_window_expired", "count", attemptNum, "elapsed", elapsed))
 				return fmt.Errorf("cart validation failed after %d attempts - sale window expired: %w", attemptNum, err)
 			} else {
-				fmt.Printf(T("validation_timeout")+"\n", attemptNum, elapsed)
+				f.reporter.Error(T("validation_timeout", "count", attemptNum, "elapsed", elapsed))
 				return fmt.Errorf("cart validation failed after %d attempts: %w", attemptNum, err)
 			}
 		}
@@ -1783,24 +1399,21 @@ func (f *FastCheckout) ValidateCartWithDeadline(automation *Automation, deadline
 			delayMs := f.config.Payment4227MinMs + rand.Intn(f.config.Payment4227MaxMs-f.config.Payment4227MinMs+1)
 			delay = time.Duration(delayMs) * time.Millisecond
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf(T("validation_payment_auth_4227")+"\n",
-					attemptNum, delayMs, remaining.Round(time.Second))
+				f.reporter.Info(T("validation_payment_auth_4227", "attempt", attemptNum, "delay_ms", delayMs, "remaining", remaining.Round(time.Second)))
 			}
 		} else if is4226 {
 			// Payment auth error 4226 - configurable backoff
 			delayMs := f.config.Payment4226MinMs + rand.Intn(f.config.Payment4226MaxMs-f.config.Payment4226MinMs+1)
 			delay = time.Duration(delayMs) * time.Millisecond
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf(T("validation_payment_auth_4226")+"\n",
-					attemptNum, delayMs, remaining.Round(time.Second))
+				f.reporter.Info(T("validation_payment_auth_4226", "attempt", attemptNum, "delay_ms", delayMs, "remaining", remaining.Round(time.Second)))
 			}
 		} else if isOutOfStock {
 			// Out of stock - configurable delay
 			delay = time.Duration(f.config.OutOfStockDelayMs) * time.Millisecond
 
 			if attemptNum%10 == 0 {
-				fmt.Printf(T("validation_out_of_stock")+"\n",
-					attemptNum, remaining.Round(time.Second))
+				f.reporter.Info(T("validation_out_of_stock", "attempt", attemptNum, "remaining", remaining.Round(time.Second)))
 			}
 		} else {
 			// Generic/other errors - configurable delay
@@ -1808,8 +1421,7 @@ func (f *FastCheckout) ValidateCartWithDeadline(automation *Automation, deadline
 
 			attemptDuration := time.Since(attemptStart)
 			if attemptNum <= 5 || attemptNum%20 == 0 {
-				fmt.Printf(T("validation_failed_retry")+"\n",
-					attemptNum, attemptDuration, f.config.GenericErrorDelayMs, remaining.Round(time.Second))
+				f.reporter.Warn(T("validation_failed_retry", "attempt", attemptNum, "duration", attemptDuration, "delay_ms", f.config.GenericErrorDelayMs, "remaining", remaining.Round(time.Second)))
 			}
 		}
 
@@ -1846,10 +1458,27 @@ func (f *FastCheckout) graphqlRequest(requests []GraphQLRequest) (string, error)
 		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	operationNames := make([]string, 0, len(requests))
+	for _, request := range requests {
+		operationNames = append(operationNames, request.OperationName)
+	}
+	timing := requestTiming{
+		Operation: strings.Join(operationNames, "+"),
+		Started:   time.Now(),
+	}
+	trace := newTimingTrace(timing.Started)
+	defer func() {
+		timing.Total = time.Since(timing.Started)
+		trace.fill(&timing)
+		f.latency.record(timing)
+		f.reporter.With("operation", timing.Operation, "duration", timing.Total).Trace("GraphQL request")
+	}()
+
 	req, err := http.NewRequest("POST", f.graphqlURL, bytes.NewReader(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", f.userAgent)
@@ -1866,11 +1495,17 @@ func (f *FastCheckout) graphqlRequest(requests []GraphQLRequest) (string, error)
 		req.Header.Set("x-csrf-token", f.csrfToken)
 	}
 
-	resp, err := f.client.Do(req)
+	var resp *http.Response
+	if f.cassette != nil {
+		resp, err = f.cassette.roundTrip(f.client, req, jsonData, timing.Operation)
+	} else {
+		resp, err = f.client.Do(req)
+	}
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	timing.Status = resp.StatusCode
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -1878,7 +1513,7 @@ func (f *FastCheckout) graphqlRequest(requests []GraphQLRequest) (string, error)
 	}
 
 	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("HTTP error %d: %s", resp.StatusCode, redactText(string(body)))
 	}
 
 	var responses []GraphQLResponse
@@ -1895,6 +1530,9 @@ func (f *FastCheckout) graphqlRequest(requests []GraphQLRequest) (string, error)
 				if details, ok := gqlErr.Extensions["details"].(map[string]interface{}); ok {
 					errMsg += "\n     Details:"
 					for key, value := range details {
+						if isSensitiveKey(key) {
+							value = redactedValue
+						}
 						errMsg += fmt.Sprintf("\n       • %s: %v", key, value)
 					}
 				}
@@ -1935,7 +1573,7 @@ func isNetworkError(err error) bool {
 
 // retryOnNetworkError wraps an operation with retry logic for network/timeout errors
 // Retries indefinitely until success or non-network error
-func retryOnNetworkError(operation func() error, operationName string) error {
+func (f *FastCheckout) retryOnNetworkError(operation func() error, operationName string) error {
 	attemptNum := 0
 	for {
 		attemptNum++
@@ -1950,11 +1588,12 @@ func retryOnNetworkError(operation func() error, operationName string) error {
 		if isNetworkError(err) {
 			// Network error - retry after a short delay
 			delay := time.Duration(500+rand.Intn(1000)) * time.Millisecond // 500-1500ms
+			f.reporter.With("operation", operationName, "attempt", attemptNum).Trace(fmt.Sprintf("Network error, retrying in %dms: %v", delay.Milliseconds(), err))
 			if attemptNum%10 == 0 || attemptNum <= 3 {
-				fmt.Printf("⚠️  %s failed (attempt %d): network error - retrying in %dms...\n",
-					operationName, attemptNum, delay.Milliseconds())
+				f.reporter.Warn(fmt.Sprintf("⚠️  %s failed (attempt %d): network error - retrying in %dms...",
+					operationName, attemptNum, delay.Milliseconds()))
 				if attemptNum <= 3 {
-					fmt.Printf("   Error: %v\n", err)
+					f.reporter.Info(fmt.Sprintf("   Error: %v", err))
 				}
 			}
 			time.Sleep(delay)
@@ -1966,14 +1605,46 @@ func retryOnNetworkError(operation func() error, operationName string) error {
 	}
 }
 
+// RunFastCheckout runs one checkout attempt and prints where the time went afterwards
 func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
+	f.latency.reset()
+	f.receipt = nil
+	f.dryRunBefore = nil
+	f.lastOrderSlug = "" // A slug from an earlier attempt or wave must not reach this receipt
 	startTime := time.Now()
+	err := f.runFastCheckout(automation)
 
-	fmt.Println(T("checkout_fast_header_line1"))
-	fmt.Println(T("checkout_fast_header_line2"))
-	fmt.Println(T("checkout_fast_header_line3"))
-	fmt.Println(T("checkout_fast_header_line4"))
-	fmt.Println()
+	// A placed order is looked up by slug before the timings are reported, so the
+	// receipt carries every step including the verification itself
+	if err == nil && f.receipt != nil {
+		f.verifyPlacedOrder()
+	}
+	f.reportLatency(err)
+	if err == nil && f.receipt != nil {
+		f.finishReceipt(time.Since(startTime))
+	}
+
+	// Dry runs undo their own mutations, which also puts any parked items back
+	if f.dryRunBefore != nil && f.rollbackDryRun() {
+		f.cartSnapshot = nil
+	}
+
+	// Items parked in the cart before the run can go back once it's over
+	if f.cartRestoreDue(err) {
+		f.offerCartRestore(err == nil && !f.config.DryRun)
+	}
+
+	return err
+}
+
+func (f *FastCheckout) runFastCheckout(automation *Automation) error {
+	startTime := time.Now()
+
+	f.reporter.Info(T("checkout_fast_header_line1"))
+	f.reporter.Info(T("checkout_fast_header_line2"))
+	f.reporter.Info(T("checkout_fast_header_line3"))
+	f.reporter.Info(T("checkout_fast_header_line4"))
+	f.reporter.Info("")
 
 	if err := f.LoadSessionFromBrowser(automation); err != nil {
 		return fmt.Errorf("failed to load session: %w", err)
@@ -1988,28 +1659,36 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 	}
 
 	// Check cart state BEFORE trying to add to cart
-	fmt.Println(T("cart_checking_state"))
+	f.reporter.CheckoutStep(CheckoutStep{Step: StepCheckCart})
 	// OPTIMIZATION: Use combined query to get totals and items in single round trip (saves 50-150ms)
 	cartInfo, err := f.GetCartTotalsAndItems()
 	if err != nil {
 		return fmt.Errorf("failed to query cart info: %w", err)
 	}
 
+	// Save what the user had in the cart before anything below changes it
+	f.snapshotCart(skuID, cartInfo.Items)
+
+	// A dry run rolls back everything below, so remember the state it must return to
+	if f.config.DryRun && f.dryRunBefore == nil {
+		f.captureDryRunState(cartInfo)
+	}
+
 	// FAST PATH: If cart already has correct item with credits applied ($0 total),
 	// skip directly to final validation step. This supports --skip-cart for repeat runs.
 	if len(cartInfo.Items) == 1 &&
 		cartInfo.Items[0].SKUID == skuID &&
 		cartInfo.Items[0].Quantity == 1 &&
-		cartInfo.Total == 0 {
+		cartInfo.Total.IsZero() {
 
-		fmt.Println(T("cart_ready_to_checkout"))
-		fmt.Printf(T("cart_ready_item")+"\n", cartInfo.Items[0].Name)
-		fmt.Println(T("cart_ready_skipping_to_validation"))
+		f.reporter.Info(T("cart_ready_to_checkout"))
+		f.reporter.Info(T("cart_ready_item", "name", cartInfo.Items[0].Name))
+		f.reporter.Info(T("cart_ready_skipping_to_validation"))
 
 		// Get/cache the billing address
 		if f.cachedAddressID == "" {
 			var addressID string
-			err := retryOnNetworkError(func() error {
+			err := f.retryOnNetworkError(func() error {
 				var err error
 				addressID, err = f.GetDefaultBillingAddress()
 				return err
@@ -2021,8 +1700,8 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 		}
 
 		// Move to billing/addresses step
-		fmt.Println(T("checkout_moving_billing"))
-		err := retryOnNetworkError(func() error {
+		f.reporter.CheckoutStep(CheckoutStep{Step: StepBilling})
+		err := f.retryOnNetworkError(func() error {
 			return f.NextStep()
 		}, "Move to Billing Step")
 		if err != nil {
@@ -2030,7 +1709,7 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 		}
 
 		// Assign billing address
-		err = retryOnNetworkError(func() error {
+		err = f.retryOnNetworkError(func() error {
 			return f.AssignBillingAddress(f.cachedAddressID)
 		}, "Assign Billing Address")
 		if err != nil {
@@ -2039,41 +1718,44 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 
 		// Complete the order
 		if !f.config.DryRun {
-			fmt.Println(T("checkout_completing_order"))
-			err := retryOnNetworkError(func() error {
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepCompleteOrder})
+			err := f.retryOnNetworkError(func() error {
 				return f.ValidateCart(automation)
 			}, "Validate Cart")
 			if err != nil {
 				return fmt.Errorf("failed to validate cart: %w", err)
 			}
-			fmt.Println(T("checkout_order_completed"))
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepOrderCompleted})
+
+			// Total was already zero, so the credit on the cart covered everything
+			item := cartInfo.Items[0]
+			f.recordOrder(orderExpectation{SKUID: skuID, Name: item.Name, Quantity: 1, Price: item.Price, Tax: cartInfo.taxOnTop(), Credit: cartInfo.CreditApplied})
 		} else {
-			fmt.Println(T("checkout_dry_run_stop"))
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepDryRunStop})
 		}
 
-		elapsed := time.Since(startTime)
-		fmt.Println(T("checkout_total_time"), elapsed)
-		fmt.Printf(T("checkout_target_vs_actual")+"\n", elapsed)
-		if elapsed.Milliseconds() < 1000 {
-			fmt.Println(T("checkout_achieved_subsecond"))
-		}
+		f.reporter.Success(Success{Elapsed: time.Since(startTime), DryRun: f.config.DryRun})
 		return nil
 	}
 
 	// Validate existing cart contents before adding
-	shouldAdd, err := f.ValidateCartContents(skuID, cartInfo.Total, cartInfo.Items)
+	shouldAdd, err := f.ValidateCartContents(skuID, cartInfo)
 	if err != nil {
 		return fmt.Errorf("cart validation failed: %w", err)
 	}
 
 	cartTotal := cartInfo.Total
 	maxCredit := cartInfo.MaxCredit
-	var itemPrice float64
+	itemPrice := newMoney(0, f.currency())
+	amountDue := itemPrice
 
-	// Calculate item price from cart (for tax handling)
-	// Store credit items don't have tax, so we use item price as the true total
-	if len(cartInfo.Items) > 0 {
-		itemPrice = cartInfo.Items[0].Price
+	// The target's price plus any tax added on top is what credit has to cover;
+	// other items the user chose to keep are left for the payment step
+	if item, ok := cartInfo.targetItem(skuID); ok {
+		itemPrice = item.Price
+		if amountDue, err = cartInfo.expectedTotal(CartItem{Price: itemPrice, Quantity: 1}); err != nil {
+			return fmt.Errorf("failed to work out the amount due: %w", err)
+		}
 	}
 
 	// Now add to cart if not skipping AND if cart validation says it's safe to add
@@ -2089,50 +1771,65 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 		}
 		cartTotal = cartInfo.Total
 		maxCredit = cartInfo.MaxCredit
-		if len(cartInfo.Items) > 0 {
-			itemPrice = cartInfo.Items[0].Price
+		if item, ok := cartInfo.targetItem(skuID); ok {
+			itemPrice = item.Price
+			if amountDue, err = cartInfo.expectedTotal(CartItem{Price: itemPrice, Quantity: 1}); err != nil {
+				return fmt.Errorf("failed to work out the amount due: %w", err)
+			}
 		}
 
 		// OPTIMIZATION: Skip post-add validation - we just successfully added the item,
 		// so we know the cart state. This saves 50-150ms by avoiding an extra GraphQL query.
 		// The pre-add validation already ensured cart was in a good state.
-		fmt.Println(T("cart_item_added_success"))
+		f.reporter.CheckoutStep(CheckoutStep{Step: StepItemAdded})
 	} else if !shouldAdd {
-		fmt.Println(T("checkout_skip_add_cart_current"))
+		f.reporter.Info(T("checkout_skip_add_cart_current"))
 	} else {
-		fmt.Println(T("checkout_skip_add_cart_exists"))
+		f.reporter.Info(T("checkout_skip_add_cart_exists"))
 	}
 
+	creditApplied := cartInfo.CreditApplied
 	if f.config.AutoApplyCredit {
-		// Use item price instead of cart total (handles tax in some regions)
-		// Store credit items don't have tax, item price is the correct amount
-		creditToApply := itemPrice
-		if creditToApply > maxCredit {
-			fmt.Printf(T("credit_total_exceeds_max_apply")+"\n", itemPrice, maxCredit)
-			fmt.Printf(T("credit_applying_maximum")+"\n", maxCredit)
+		creditToApply := amountDue
+		cmp, err := creditToApply.Cmp(maxCredit)
+		if err != nil {
+			return fmt.Errorf("failed to compare the amount due with the credit limit: %w", err)
+		}
+		if cmp > 0 {
+			f.reporter.Warn(T("credit_total_exceeds_max_apply", "amount_due", amountDue, "max_credit", maxCredit))
+			f.reporter.Info(T("credit_applying_maximum", "max_credit", maxCredit))
 			creditToApply = maxCredit
 		}
 
-		if creditToApply > 0 {
-			err := retryOnNetworkError(func() error {
+		if creditToApply.IsPositive() {
+			err := f.retryOnNetworkError(func() error {
 				return f.ApplyStoreCredit(creditToApply)
 			}, "Apply Store Credit")
 			if err != nil {
 				return fmt.Errorf("failed to apply credit: %w", err)
 			}
-			// OPTIMIZATION: We know the total is $0 after applying credit, no need to re-query
-			cartTotal = 0
-			if f.config.DebugMode {
-				fmt.Printf(T("debug_cart_total_optimized")+"\n", cartTotal)
+			// OPTIMIZATION: The credit replaces whatever was applied before, so the new
+			// total is known without re-querying
+			if cartTotal, err = cartTotal.Add(creditApplied); err == nil {
+				cartTotal, err = cartTotal.Sub(creditToApply)
 			}
+			if err != nil {
+				return fmt.Errorf("failed to work out the cart total after credit: %w", err)
+			}
+			creditApplied = creditToApply
+			f.reporter.Debug(T("debug_cart_total_optimized", "total", cartTotal))
 		} else {
-			fmt.Println(T("checkout_no_credit_needed"))
+			f.reporter.Info(T("checkout_no_credit_needed"))
 		}
 	}
 
-	if cartTotal == 0 {
-		fmt.Println(T("checkout_moving_billing"))
-		err := retryOnNetworkError(func() error {
+	// Both ways of completing the order leave a receipt for the same expectation
+	target, _ := findCartItem(cartInfo.Items, skuID)
+	expected := orderExpectation{SKUID: skuID, Name: target.Name, Quantity: 1, Price: itemPrice, Tax: cartInfo.taxOnTop(), Credit: creditApplied}
+
+	if cartTotal.IsZero() {
+		f.reporter.CheckoutStep(CheckoutStep{Step: StepBilling})
+		err := f.retryOnNetworkError(func() error {
 			return f.NextStep()
 		}, "Move to Billing Step")
 		if err != nil {
@@ -2142,7 +1839,7 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 		// OPTIMIZATION: Cache address ID if not already cached
 		if f.cachedAddressID == "" {
 			var addressID string
-			err := retryOnNetworkError(func() error {
+			err := f.retryOnNetworkError(func() error {
 				var err error
 				addressID, err = f.GetDefaultBillingAddress()
 				return err
@@ -2151,14 +1848,12 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 				return fmt.Errorf("failed to get billing address: %w", err)
 			}
 			f.cachedAddressID = addressID
-			if f.config.DebugMode {
-				fmt.Printf(T("debug_cached_address")+"\n", addressID)
-			}
-		} else if f.config.DebugMode {
-			fmt.Printf(T("debug_using_cached_address")+"\n", f.cachedAddressID)
+			f.reporter.Debug(T("debug_cached_address", "address_id", maskSecret(addressID)))
+		} else {
+			f.reporter.Debug(T("debug_using_cached_address", "address_id", maskSecret(f.cachedAddressID)))
 		}
 
-		err = retryOnNetworkError(func() error {
+		err = f.retryOnNetworkError(func() error {
 			return f.AssignBillingAddress(f.cachedAddressID)
 		}, "Assign Billing Address")
 		if err != nil {
@@ -2166,20 +1861,21 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 		}
 
 		if !f.config.DryRun {
-			fmt.Println(T("checkout_completing_order"))
-			err := retryOnNetworkError(func() error {
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepCompleteOrder})
+			err := f.retryOnNetworkError(func() error {
 				return f.ValidateCart(automation)
 			}, "Validate Cart")
 			if err != nil {
 				return fmt.Errorf("failed to validate cart: %w", err)
 			}
-			fmt.Println(T("checkout_order_completed"))
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepOrderCompleted})
+			f.recordOrder(expected)
 		} else {
-			fmt.Println(T("checkout_dry_run_stop"))
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepDryRunStop})
 		}
 	} else {
-		fmt.Printf(T("checkout_moving_payment")+"\n", cartTotal)
-		err := retryOnNetworkError(func() error {
+		f.reporter.CheckoutStep(CheckoutStep{Step: StepPayment, Balance: cartTotal})
+		err := f.retryOnNetworkError(func() error {
 			return f.NextStep()
 		}, "Move to Payment Step")
 		if err != nil {
@@ -2187,27 +1883,23 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 		}
 
 		if !f.config.DryRun {
-			fmt.Println(T("checkout_completing_payment"))
-			err := retryOnNetworkError(func() error {
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepCompletePayment})
+			err := f.retryOnNetworkError(func() error {
 				return f.NextStep()
 			}, "Complete Order")
 			if err != nil {
 				return fmt.Errorf("failed to complete order: %w", err)
 			}
-			fmt.Println(T("checkout_order_completed"))
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepOrderCompleted})
+
+			// NextStep returns no order slug, so this receipt is written unverified
+			f.recordOrder(expected)
 		} else {
-			fmt.Println(T("checkout_dry_run_stop"))
+			f.reporter.CheckoutStep(CheckoutStep{Step: StepDryRunStop})
 		}
 	}
 
-	elapsed := time.Since(startTime)
-	fmt.Println(T("checkout_total_time"), elapsed)
-	fmt.Printf(T("checkout_target_vs_actual")+"\n", elapsed)
-
-	if elapsed.Milliseconds() < 1000 {
-		fmt.Println(T("checkout_achieved_subsecond"))
-	}
-
+	f.reporter.Success(Success{Elapsed: time.Since(startTime), DryRun: f.config.DryRun})
 	return nil
 }
 
@@ -2216,26 +1908,7 @@ func (f *FastCheckout) RunFastCheckout(automation *Automation) error {
 // addToCartSingleAttempt tries to add to cart once without retrying
 func (f *FastCheckout) addToCartSingleAttempt(skuID string, automation *Automation) error {
 	// No reCAPTCHA needed for AddCartMultiItemMutation
-	mutation := `mutation AddCartMultiItemMutation($query: [CartAddInput!]) {
-  store(name: "pledge") {
-    cart {
-      mutations {
-        addMany(query: $query) {
-          count
-          resources {
-            id
-            title
-            __typename
-          }
-          __typename
-        }
-        __typename
-      }
-      __typename
-    }
-    __typename
-  }
-}`
+	mutation := operation("AddCartMultiItemMutation")
 
 	variables := map[string]interface{}{
 		"query": []map[string]interface{}{
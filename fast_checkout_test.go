@@ -902,19 +902,19 @@ func TestTimedSaleMode_Phase1SkipLogic(t *testing.T) {
 func TestCartInfoStructure(t *testing.T) {
 	// Test that CartInfo properly combines totals and items
 	cartInfo := &CartInfo{
-		Total:     20.00,
-		MaxCredit: 15.00,
+		Total:     usd(2000),
+		MaxCredit: usd(1500),
 		Items: []CartItem{
-			{Name: "Aurora ES", Price: 20.00, SKUID: "12345", Quantity: 1},
+			{Name: "Aurora ES", Price: usd(2000), SKUID: "12345", Quantity: 1},
 		},
 	}
 
-	if cartInfo.Total != 20.00 {
-		t.Errorf("Expected Total 20.00, got %.2f", cartInfo.Total)
+	if !cartInfo.Total.Equal(usd(2000)) {
+		t.Errorf("Expected Total $20.00, got %s", cartInfo.Total)
 	}
 
-	if cartInfo.MaxCredit != 15.00 {
-		t.Errorf("Expected MaxCredit 15.00, got %.2f", cartInfo.MaxCredit)
+	if !cartInfo.MaxCredit.Equal(usd(1500)) {
+		t.Errorf("Expected MaxCredit $15.00, got %s", cartInfo.MaxCredit)
 	}
 
 	if len(cartInfo.Items) != 1 {
@@ -928,6 +928,35 @@ func TestCartInfoStructure(t *testing.T) {
 	t.Log("✓ CartInfo structure correctly combines totals and items")
 }
 
+// Test that the amount due is worked out from the target, not a kept item listed first
+func TestCartInfoTargetItemSkipsKeptItems(t *testing.T) {
+	cartInfo := &CartInfo{
+		Total: usd(5815),
+		Tax:   usd(315),
+		Items: []CartItem{
+			{Name: "Kept Paint", Price: usd(1000), SKUID: "111", Quantity: 1},
+			{Name: "Target Ship", Price: usd(4500), SKUID: "222", Quantity: 1},
+		},
+	}
+
+	item, ok := cartInfo.targetItem("222")
+	if !ok || item.Name != "Target Ship" {
+		t.Fatalf("Expected the target line, got %+v", item)
+	}
+	amountDue, err := cartInfo.expectedTotal(CartItem{Price: item.Price, Quantity: 1})
+	if err != nil || !amountDue.Equal(usd(4815)) {
+		t.Errorf("Expected $48.15 due for the target, got %s (%v)", amountDue, err)
+	}
+
+	// Without the target in the cart the first line stands in, as before the add
+	if item, ok := cartInfo.targetItem("333"); !ok || item.SKUID != "111" {
+		t.Errorf("Expected the first line as fallback, got %+v", item)
+	}
+	if _, ok := (&CartInfo{}).targetItem("222"); ok {
+		t.Error("Expected no target in an empty cart")
+	}
+}
+
 // Test performance optimization - combined query saves a round trip
 func TestCombinedQueryOptimization(t *testing.T) {
 	// Conceptual test: verify that GetCartTotalsAndItems exists and returns combined data
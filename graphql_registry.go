@@ -0,0 +1,139 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Store GraphQL operations live in graphql/<OperationName>.graphql and are compiled
+// into the binary. A file with the same name in ~/.specter/graphql replaces the
+// built-in operation, so a changed store API can be patched without a rebuild.
+//
+//go:embed graphql/*.graphql
+var embeddedOperations embed.FS
+
+var (
+	operationsMu sync.RWMutex
+	operations   = mustLoadEmbeddedOperations()
+	// Operations replaced from disk, name → file path
+	operationOverrides = map[string]string{}
+)
+
+// operationHeader matches the declaration of an operation: "query Name(" or "mutation Name {"
+var operationHeader = regexp.MustCompile(`(?m)^\s*(query|mutation)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// mustLoadEmbeddedOperations reads the built-in operations; a broken file is a build
+// mistake, so it fails at startup rather than at checkout time
+func mustLoadEmbeddedOperations() map[string]string {
+	entries, err := embeddedOperations.ReadDir("graphql")
+	if err != nil {
+		panic(fmt.Sprintf("graphql: %v", err))
+	}
+
+	ops := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := embeddedOperations.ReadFile("graphql/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("graphql: %v", err))
+		}
+		name, query, err := parseOperationFile(entry.Name(), data)
+		if err != nil {
+			panic(fmt.Sprintf("graphql: %v", err))
+		}
+		ops[name] = query
+	}
+	return ops
+}
+
+// parseOperationFile checks that a .graphql file declares the operation it is named after
+func parseOperationFile(fileName string, data []byte) (string, string, error) {
+	name := strings.TrimSuffix(filepath.Base(fileName), ".graphql")
+	query := strings.TrimRight(string(data), "\n")
+
+	match := operationHeader.FindStringSubmatch(query)
+	if match == nil {
+		return "", "", TError("error_graphql_no_operation", "file_name", fileName)
+	}
+	if match[2] != name {
+		return "", "", TError("error_graphql_name_mismatch", "file_name", fileName, "name", match[2])
+	}
+	return name, query, nil
+}
+
+// operation returns the query text of a store operation by name
+func operation(name string) string {
+	operationsMu.RLock()
+	defer operationsMu.RUnlock()
+
+	query, ok := operations[name]
+	if !ok {
+		panic(fmt.Sprintf("graphql: unknown operation %s", name))
+	}
+	return query
+}
+
+// operationNames lists every registered operation, sorted
+func operationNames() []string {
+	operationsMu.RLock()
+	defer operationsMu.RUnlock()
+
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// graphqlOverrideDir is where operation overrides are picked up (~/.specter/graphql)
+func graphqlOverrideDir() string {
+	return filepath.Join(getUserDataDir(), "graphql")
+}
+
+// loadOperationOverrides replaces built-in operations with the .graphql files in dir.
+// Only known operations can be overridden; invalid files are reported and skipped.
+// Returns the names of the operations that were replaced.
+func loadOperationOverrides(dir string, reporter Reporter) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+	sort.Strings(files)
+
+	operationsMu.Lock()
+	defer operationsMu.Unlock()
+
+	var replaced []string
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			reporter.Warn(T("graphql_override_skipped", "path", path, "error", err))
+			continue
+		}
+
+		name, query, err := parseOperationFile(path, data)
+		if err != nil {
+			reporter.Warn(T("graphql_override_skipped", "path", path, "error", err))
+			continue
+		}
+		if _, ok := operations[name]; !ok {
+			reporter.Warn(T("graphql_override_skipped", "path", path, "error", T("error_graphql_unknown_operation", "name", name)))
+			continue
+		}
+
+		operations[name] = query
+		operationOverrides[name] = path
+		replaced = append(replaced, name)
+	}
+
+	for _, name := range replaced {
+		reporter.Info(T("graphql_override_loaded", "name", name, "path", operationOverrides[name]))
+	}
+	return replaced
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedOperations(t *testing.T) {
+	names := operationNames()
+	if len(names) == 0 {
+		t.Fatal("No operations embedded")
+	}
+
+	for _, name := range names {
+		match := operationHeader.FindStringSubmatch(operation(name))
+		if match == nil || match[2] != name {
+			t.Errorf("%s.graphql does not declare operation %s", name, name)
+		}
+	}
+
+	// Both add-to-cart paths must send the same mutation
+	if !strings.Contains(operation("AddCartMultiItemMutation"), "resources") {
+		t.Error("AddCartMultiItemMutation is missing the added resources")
+	}
+}
+
+func TestLoadOperationOverrides(t *testing.T) {
+	original := operation("CartFlowQuery")
+	t.Cleanup(func() {
+		operationsMu.Lock()
+		operations["CartFlowQuery"] = original
+		delete(operationOverrides, "CartFlowQuery")
+		operationsMu.Unlock()
+	})
+
+	dir := t.TempDir()
+	patched := "query CartFlowQuery($storeFront: String) {\n  store(name: $storeFront) { cart { flow { current { step } } } }\n}\n"
+	files := map[string]string{
+		"CartFlowQuery.graphql":     patched,
+		"CombinedCartQuery.graphql": "query SomethingElse { store { cart { id } } }",
+		"UnknownQuery.graphql":      "query UnknownQuery { store { id } }",
+		"NextStepMutation.graphql":  "{ store { id } }",
+		"notes.txt":                 "query CartFlowQuery { ignored }",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	combined := operation("CombinedCartQuery")
+	next := operation("NextStepMutation")
+
+	replaced := loadOperationOverrides(dir, NewReporter(DefaultConfig(), "graphql"))
+	if len(replaced) != 1 || replaced[0] != "CartFlowQuery" {
+		t.Fatalf("Expected only CartFlowQuery to be replaced, got %v", replaced)
+	}
+	if operation("CartFlowQuery") != strings.TrimRight(patched, "\n") {
+		t.Errorf("Override not applied: %s", operation("CartFlowQuery"))
+	}
+	if operation("CombinedCartQuery") != combined || operation("NextStepMutation") != next {
+		t.Error("Invalid overrides must not replace built-in operations")
+	}
+}
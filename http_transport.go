@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// newStoreTransport builds the tuned transport shared by every HTTP call specter makes.
+// One transport means one connection pool: the pre-wave HEAD polls, the SKU page fetch
+// and the GraphQL mutations all ride the same warm HTTP/2 connection to the store host.
+func newStoreTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second, // TCP keep-alive probes
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,             // Custom DialContext disables h2 unless forced
+		MaxIdleConns:          32,               // Store host + time server + reCAPTCHA leftovers
+		MaxIdleConnsPerHost:   16,               // Aggressive retry loops never wait for a free conn
+		IdleConnTimeout:       10 * time.Minute, // Outlive the gap between warm-up and T0
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// newClient returns a lightweight client that shares the store transport.
+// Clients are cheap - only the transport holds connections - so each caller can pick
+// its own timeout and redirect policy without opening a separate pool.
+func (f *FastCheckout) newClient(timeout time.Duration, followRedirects bool) *http.Client {
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: f.transport,
+	}
+
+	if !followRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return client
+}
+
+// WarmUp opens (or refreshes) the connection to the GraphQL host so the first T0 mutation
+// does not pay for DNS, TCP and TLS. The response itself is irrelevant - any status code
+// means the handshake is done and the connection is parked in the idle pool.
+func (f *FastCheckout) WarmUp() error {
+	startTime := time.Now()
+
+	req, err := http.NewRequest("HEAD", f.graphqlURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create warm-up request: %w", err)
+	}
+
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("warm-up request failed: %w", err)
+	}
+
+	// Drain the body so the connection goes back to the pool instead of being closed
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	f.reporter.Info(T("connection_warmed_up", "protocol", resp.Proto, "elapsed", time.Since(startTime).Round(time.Millisecond)))
+	return nil
+}
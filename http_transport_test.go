@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+	"time"
+)
+
+func TestNewStoreTransport(t *testing.T) {
+	transport := newStoreTransport()
+
+	if !transport.ForceAttemptHTTP2 {
+		t.Error("Expected HTTP/2 to be forced on the store transport")
+	}
+
+	if transport.MaxIdleConnsPerHost < 2 {
+		t.Errorf("Expected MaxIdleConnsPerHost >= 2 for retry loops, got %d", transport.MaxIdleConnsPerHost)
+	}
+
+	if transport.IdleConnTimeout < 5*time.Minute {
+		t.Errorf("Expected IdleConnTimeout to outlive the pre-wave window, got %v", transport.IdleConnTimeout)
+	}
+}
+
+func TestFastCheckoutSharesTransport(t *testing.T) {
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+
+	if fc.client.Transport != fc.transport {
+		t.Error("GraphQL client does not use the shared transport")
+	}
+
+	pollClient := fc.newClient(5*time.Second, false)
+	if pollClient.Transport != fc.transport {
+		t.Error("Polling client does not use the shared transport")
+	}
+	if pollClient.CheckRedirect == nil {
+		t.Error("Expected polling client to refuse redirects")
+	}
+
+	if fc.newClient(5*time.Second, true).CheckRedirect != nil {
+		t.Error("Expected redirect-following client to use default redirect policy")
+	}
+}
+
+func TestWarmUpKeepsConnectionAlive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.baseURL = server.URL
+	fc.graphqlURL = server.URL + "/graphql"
+
+	if err := fc.WarmUp(); err != nil {
+		t.Fatalf("WarmUp failed: %v", err)
+	}
+
+	// The next request must reuse the warmed connection instead of dialing again
+	reused := false
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
+	}
+
+	req, err := http.NewRequest("POST", fc.graphqlURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := fc.client.Do(req)
+	if err != nil {
+		t.Fatalf("Request after warm-up failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if !reused {
+		t.Error("Expected request after WarmUp to reuse the pooled connection")
+	}
+}
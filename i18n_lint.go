@@ -0,0 +1,307 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// translationCall is one T("key", ...) or TError("key", ...) in the Go sources
+type translationCall struct {
+	Key      string
+	Params   []string // Parameter names given as string literals
+	Dynamic  bool     // Some parameter names aren't string literals
+	Location string   // file:line
+}
+
+// lintReport collects what "specter i18n lint" found
+type lintReport struct {
+	Problems []string
+	Unused   []string // Not problems: kept translations may come back into use
+}
+
+func (r *lintReport) problem(key string, params ...interface{}) {
+	r.Problems = append(r.Problems, T(key, params...))
+}
+
+// scanTranslationCalls finds every translation call with a literal key in the non-test
+// Go files of dir
+func scanTranslationCalls(dir string) ([]translationCall, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var calls []translationCall
+	scanned := 0
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		scanned++
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if fn, ok := call.Fun.(*ast.Ident); !ok || (fn.Name != "T" && fn.Name != "TError") {
+				return true
+			}
+			key, ok := stringLiteral(call.Args[0])
+			if !ok {
+				return true
+			}
+
+			pos := fset.Position(call.Pos())
+			tc := translationCall{Key: key, Location: fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)}
+			for i := 1; i < len(call.Args); i += 2 {
+				name, ok := stringLiteral(call.Args[i])
+				if !ok {
+					tc.Dynamic = true
+					continue
+				}
+				tc.Params = append(tc.Params, name)
+			}
+			calls = append(calls, tc)
+			return true
+		})
+	}
+
+	if scanned == 0 {
+		return nil, TError("error_i18n_no_sources", "dir", dir)
+	}
+	return calls, nil
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// loadLintLocales reads every locale file in dir, falling back to the built-in ones
+func loadLintLocales(dir string) (map[string]*Locale, error) {
+	locales := map[string]*Locale{}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".yaml")
+		l, err := parseLocale(name, data)
+		if err != nil {
+			return nil, TError("error_i18n_locale_parse", "path", path, "error", err)
+		}
+		locales[name] = l
+	}
+	if len(locales) > 0 {
+		return locales, nil
+	}
+
+	entries, err := embeddedLocales.ReadDir("lang")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := embeddedLocales.ReadFile("lang/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		if locales[name], err = parseLocale(name, data); err != nil {
+			return nil, err
+		}
+	}
+	return locales, nil
+}
+
+// placeholders maps each {name} of a message to its format verb or style ("" when plain),
+// across all plural forms
+func (m message) placeholders() map[string]string {
+	found := map[string]string{}
+	texts := []string{m.Text}
+	for _, text := range m.Plural {
+		texts = append(texts, text)
+	}
+	for _, text := range texts {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			found[match[1]] = match[2]
+		}
+	}
+	return found
+}
+
+// formatPlaceholders renders placeholders as "{a}, {b:%q}" for reports
+func formatPlaceholders(found map[string]string) string {
+	if len(found) == 0 {
+		return "-"
+	}
+	var parts []string
+	for name, verb := range found {
+		if verb != "" {
+			name += ":" + verb
+		}
+		parts = append(parts, "{"+name+"}")
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// lintTranslations checks the locale files against each other and against the calls
+// in the sources. en_US is the reference every other locale is compared with.
+func lintTranslations(calls []translationCall, locales map[string]*Locale) *lintReport {
+	report := &lintReport{}
+	reference := locales["en_US"]
+	if reference == nil {
+		report.problem("i18n_lint_no_reference")
+		return report
+	}
+
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	used := map[string]bool{}
+	for _, call := range calls {
+		used[call.Key] = true
+	}
+
+	// Keys the code uses must exist everywhere
+	firstUse := map[string]string{}
+	for _, call := range calls {
+		if _, seen := firstUse[call.Key]; !seen {
+			firstUse[call.Key] = call.Location
+		}
+	}
+	usedKeys := sortedKeys(firstUse)
+	for _, name := range names {
+		for _, key := range usedKeys {
+			if _, ok := locales[name].translations[key]; !ok {
+				report.problem("i18n_lint_missing", "locale", name, "key", key, "location", firstUse[key])
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(reference.translations) {
+		if !used[key] {
+			report.Unused = append(report.Unused, key)
+		}
+	}
+
+	// Every locale must have the placeholders and plural forms the reference has
+	for _, name := range names {
+		l := locales[name]
+		for _, key := range sortedKeys(l.translations) {
+			msg := l.translations[key]
+			if msg.Plural != nil {
+				for _, category := range pluralCategories(name) {
+					if _, ok := msg.Plural[category]; !ok {
+						report.problem("i18n_lint_plural_form", "locale", name, "key", key, "form", category)
+					}
+				}
+			}
+
+			refMsg, ok := reference.translations[key]
+			if name == "en_US" {
+				continue
+			}
+			if !ok {
+				report.problem("i18n_lint_not_in_reference", "locale", name, "key", key)
+				continue
+			}
+			want, have := formatPlaceholders(refMsg.placeholders()), formatPlaceholders(msg.placeholders())
+			if want != have {
+				report.problem("i18n_lint_placeholders", "locale", name, "key", key, "have", have, "want", want)
+			}
+		}
+	}
+
+	// Calls must pass exactly the parameters the reference message uses
+	for _, call := range calls {
+		msg, ok := reference.translations[call.Key]
+		if !ok || call.Dynamic {
+			continue
+		}
+		// Only names matter here; the verbs belong to the translation
+		expected := map[string]string{}
+		for name := range msg.placeholders() {
+			expected[name] = ""
+		}
+		given := map[string]string{}
+		for _, param := range call.Params {
+			given[param] = ""
+		}
+		if msg.Plural != nil {
+			// count picks the plural form even when the text doesn't show it
+			expected["count"] = ""
+		}
+		if want, have := formatPlaceholders(expected), formatPlaceholders(given); want != have {
+			report.problem("i18n_lint_params", "location", call.Location, "key", call.Key, "have", have, "want", want)
+		}
+	}
+
+	return report
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// runI18nCommand handles "specter i18n lint [source directory]"
+func runI18nCommand(env *commandEnv, args []string) error {
+	if len(args) < 1 || len(args) > 2 || args[0] != "lint" {
+		return TError("command_i18n_usage")
+	}
+	dir := "."
+	if len(args) == 2 {
+		dir = args[1]
+	}
+
+	calls, err := scanTranslationCalls(dir)
+	if err != nil {
+		return err
+	}
+	locales, err := loadLintLocales(filepath.Join(dir, "lang"))
+	if err != nil {
+		return err
+	}
+
+	report := lintTranslations(calls, locales)
+	fmt.Println(T("i18n_lint_summary", "calls", len(calls), "locales", strings.Join(sortedKeys(locales), ", ")))
+	for _, key := range report.Unused {
+		fmt.Println(T("i18n_lint_unused", "key", key))
+	}
+	for _, problem := range report.Problems {
+		fmt.Println(problem)
+	}
+
+	if len(report.Problems) > 0 {
+		return TError("error_i18n_lint_problems", "count", len(report.Problems))
+	}
+	fmt.Println(T("i18n_lint_clean"))
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanTranslationCalls(t *testing.T) {
+	dir := t.TempDir()
+	source := `package main
+
+func example(err error, name string) {
+	println(T("plain"))
+	println(T("with_params", "name", name, "count", 2))
+	_ = TError("wrapped", "error", err)
+	println(T(name))
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "example.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "example_test.go"), []byte("package main\n\nvar _ = T(\"test_only\")\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls, err := scanTranslationCalls(dir)
+	if err != nil {
+		t.Fatalf("scanTranslationCalls failed: %v", err)
+	}
+	var got []string
+	for _, call := range calls {
+		got = append(got, call.Key+"("+strings.Join(call.Params, ",")+")@"+call.Location)
+	}
+	want := "plain()@example.go:4 with_params(name,count)@example.go:5 wrapped(error)@example.go:6"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Expected %s, got %v", want, got)
+	}
+}
+
+func TestLintTranslations(t *testing.T) {
+	en, _ := parseLocale("en_US", []byte(`
+greeting: "Hello, {name}!"
+waves:
+  one: "{count} wave"
+  other: "{count} waves"
+wait: "Waiting {seconds:%.2f}s"
+unused_key: "Nobody calls me"
+`))
+	ru, _ := parseLocale("ru_RU", []byte(`
+greeting: "Привет, {user}!"
+waves:
+  one: "{count} волна"
+  other: "{count} волны"
+wait: "Ожидание {seconds}с"
+extra_key: "Только по-русски"
+`))
+	calls := []translationCall{
+		{Key: "greeting", Params: []string{"name"}, Location: "a.go:1"},
+		{Key: "waves", Params: []string{"total"}, Location: "a.go:2"},
+		{Key: "wait", Params: []string{"seconds"}, Location: "a.go:3"},
+		{Key: "missing", Location: "a.go:4"},
+	}
+
+	report := lintTranslations(calls, map[string]*Locale{"en_US": en, "ru_RU": ru})
+
+	if strings.Join(report.Unused, ",") != "unused_key" {
+		t.Errorf("Expected unused_key to be reported, got %v", report.Unused)
+	}
+	want := []string{
+		"i18n_lint_missing locale=en_US key=missing location=a.go:4",
+		"i18n_lint_missing locale=ru_RU key=missing location=a.go:4",
+		"i18n_lint_not_in_reference locale=ru_RU key=extra_key",
+		"i18n_lint_placeholders locale=ru_RU key=greeting have={user} want={name}",
+		"i18n_lint_placeholders locale=ru_RU key=wait have={seconds} want={seconds:%.2f}",
+		"i18n_lint_plural_form locale=ru_RU key=waves form=few",
+		"i18n_lint_plural_form locale=ru_RU key=waves form=many",
+		"i18n_lint_params location=a.go:2 key=waves have={total} want={count}",
+	}
+	if strings.Join(report.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected problems:\n%s\nwant:\n%s", strings.Join(report.Problems, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// The shipped locale files must pass the lint, so a key added in one file only fails CI
+func TestRepositoryTranslationsLintClean(t *testing.T) {
+	calls, err := scanTranslationCalls(".")
+	if err != nil {
+		t.Fatalf("scanTranslationCalls failed: %v", err)
+	}
+	locales, err := loadLintLocales("lang")
+	if err != nil {
+		t.Fatalf("loadLintLocales failed: %v", err)
+	}
+	for _, problem := range lintTranslations(calls, locales).Problems {
+		t.Error(problem)
+	}
+}
+
+func TestInitLocaleLanguageOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	original := globalLocale
+	t.Cleanup(func() { globalLocale = original })
+
+	for _, requested := range []string{"ru", "ru-RU", "ru_RU.UTF-8"} {
+		if err := InitLocale(requested); err != nil {
+			t.Fatalf("InitLocale(%q) failed: %v", requested, err)
+		}
+		if GetLocale() != "ru_RU" {
+			t.Errorf("InitLocale(%q) picked %s, expected ru_RU", requested, GetLocale())
+		}
+	}
+}
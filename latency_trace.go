@@ -0,0 +1,212 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestTiming captures where the time went for a single GraphQL round trip
+type requestTiming struct {
+	Operation string        `json:"operation"`
+	Started   time.Time     `json:"started"`
+	DNS       time.Duration `json:"dns_ns"`
+	Connect   time.Duration `json:"connect_ns"`
+	TLS       time.Duration `json:"tls_ns"`
+	TTFB      time.Duration `json:"ttfb_ns"` // Request start to first response byte (includes server time)
+	Total     time.Duration `json:"total_ns"`
+	Reused    bool          `json:"reused"`
+	Status    int           `json:"status"`
+}
+
+// BodyRead is the time spent reading the response after the first byte arrived
+func (rt requestTiming) BodyRead() time.Duration {
+	if rt.TTFB == 0 || rt.Total < rt.TTFB {
+		return 0
+	}
+	return rt.Total - rt.TTFB
+}
+
+// latencyRecorder collects request timings for the current checkout run
+type latencyRecorder struct {
+	mu      sync.Mutex
+	timings []requestTiming
+}
+
+func (r *latencyRecorder) record(timing requestTiming) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings = append(r.timings, timing)
+}
+
+func (r *latencyRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings = nil
+}
+
+func (r *latencyRecorder) snapshot() []requestTiming {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]requestTiming(nil), r.timings...)
+}
+
+// timingTrace collects the connection phases of one request. The httptrace hooks fire
+// on the transport's dialer goroutines, possibly after the round trip has returned, so
+// every field is guarded by mu and only copied into the timing through fill.
+type timingTrace struct {
+	mu                               sync.Mutex
+	started                          time.Time
+	dnsStart, connectStart, tlsStart time.Time
+	dns, connect, tls, ttfb          time.Duration
+	reused                           bool
+}
+
+// newTimingTrace starts tracing a request sent at started.
+// All durations are measured from the moment the hook fires, so a reused connection
+// simply leaves DNS/Connect/TLS at zero.
+func newTimingTrace(started time.Time) *timingTrace {
+	return &timingTrace{started: started}
+}
+
+// clientTrace returns the httptrace hook set that records into t
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	locked := func(update func()) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		update()
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { locked(func() { t.dnsStart = time.Now() }) },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			locked(func() {
+				if !t.dnsStart.IsZero() {
+					t.dns = time.Since(t.dnsStart)
+				}
+			})
+		},
+		ConnectStart: func(network, addr string) { locked(func() { t.connectStart = time.Now() }) },
+		ConnectDone: func(network, addr string, err error) {
+			locked(func() {
+				if !t.connectStart.IsZero() {
+					t.connect = time.Since(t.connectStart)
+				}
+			})
+		},
+		TLSHandshakeStart: func() { locked(func() { t.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			locked(func() {
+				if !t.tlsStart.IsZero() {
+					t.tls = time.Since(t.tlsStart)
+				}
+			})
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			locked(func() { t.reused = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			locked(func() { t.ttfb = time.Since(t.started) })
+		},
+	}
+}
+
+// fill copies the phases recorded so far into timing
+func (t *timingTrace) fill(timing *requestTiming) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timing.DNS, timing.Connect, timing.TLS, timing.TTFB = t.dns, t.connect, t.tls, t.ttfb
+	timing.Reused = t.reused
+}
+
+// timingStep is one row of the breakdown table: consecutive calls of the same operation
+// (e.g. 40 validation retries) are folded into a single row with summed durations.
+type timingStep struct {
+	Operation string        `json:"operation"`
+	Calls     int           `json:"calls"`
+	DNS       time.Duration `json:"dns_ns"`
+	Connect   time.Duration `json:"connect_ns"`
+	TLS       time.Duration `json:"tls_ns"`
+	TTFB      time.Duration `json:"ttfb_ns"`
+	BodyRead  time.Duration `json:"body_read_ns"`
+	Total     time.Duration `json:"total_ns"`
+}
+
+// summarizeTimings folds consecutive calls of the same operation into steps
+func summarizeTimings(timings []requestTiming) []timingStep {
+	var steps []timingStep
+	for _, timing := range timings {
+		if len(steps) == 0 || steps[len(steps)-1].Operation != timing.Operation {
+			steps = append(steps, timingStep{Operation: timing.Operation})
+		}
+		step := &steps[len(steps)-1]
+		step.Calls++
+		step.DNS += timing.DNS
+		step.Connect += timing.Connect
+		step.TLS += timing.TLS
+		step.TTFB += timing.TTFB
+		step.BodyRead += timing.BodyRead()
+		step.Total += timing.Total
+	}
+	return steps
+}
+
+// formatTimingTable renders the per-step breakdown shown at the end of each checkout
+func formatTimingTable(steps []timingStep) string {
+	columns := strings.Split(T("timing_table_columns"), "|")
+	if len(columns) != 8 {
+		columns = []string{"Step", "Calls", "DNS", "Connect", "TLS", "TTFB", "Body", "Total"}
+	}
+
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-28s %5s %9s %9s %9s %9s %9s %10s\n",
+		columns[0], columns[1], columns[2], columns[3], columns[4], columns[5], columns[6], columns[7])
+
+	var total timingStep
+	for _, step := range steps {
+		fmt.Fprintf(&b, "%-28s %5d %9s %9s %9s %9s %9s %10s\n",
+			step.Operation, step.Calls, ms(step.DNS), ms(step.Connect), ms(step.TLS), ms(step.TTFB), ms(step.BodyRead), ms(step.Total))
+		total.Calls += step.Calls
+		total.DNS += step.DNS
+		total.Connect += step.Connect
+		total.TLS += step.TLS
+		total.TTFB += step.TTFB
+		total.BodyRead += step.BodyRead
+		total.Total += step.Total
+	}
+
+	fmt.Fprintf(&b, "%-28s %5d %9s %9s %9s %9s %9s %10s",
+		strings.ToUpper(columns[7]), total.Calls, ms(total.DNS), ms(total.Connect), ms(total.TLS), ms(total.TTFB), ms(total.BodyRead), ms(total.Total))
+
+	return b.String()
+}
+
+// reportLatency prints the step breakdown for the run that just finished and appends
+// the raw timings to the event log so slow runs can be analysed afterwards
+func (f *FastCheckout) reportLatency(runErr error) {
+	timings := f.latency.snapshot()
+	if len(timings) == 0 {
+		return
+	}
+
+	f.reporter.Info("")
+	f.reporter.Info(T("timing_breakdown_header"))
+	f.reporter.Info(formatTimingTable(summarizeTimings(timings)))
+
+	outcome := "success"
+	if runErr != nil {
+		outcome = "failed"
+	}
+
+	appendEventLog("checkout_timings", map[string]interface{}{
+		"outcome":  outcome,
+		"requests": timings,
+	})
+}
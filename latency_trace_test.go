@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarizeTimingsFoldsRetries(t *testing.T) {
+	timings := []requestTiming{
+		{Operation: "CombinedCartQuery", TTFB: 40 * time.Millisecond, Total: 50 * time.Millisecond, DNS: 5 * time.Millisecond},
+		{Operation: "CartValidateCartMutation", TTFB: 80 * time.Millisecond, Total: 90 * time.Millisecond},
+		{Operation: "CartValidateCartMutation", TTFB: 70 * time.Millisecond, Total: 75 * time.Millisecond},
+		{Operation: "NextStepMutation", TTFB: 30 * time.Millisecond, Total: 30 * time.Millisecond},
+	}
+
+	steps := summarizeTimings(timings)
+	if len(steps) != 3 {
+		t.Fatalf("Expected 3 steps, got %d", len(steps))
+	}
+
+	validate := steps[1]
+	if validate.Calls != 2 {
+		t.Errorf("Expected validation retries folded into 2 calls, got %d", validate.Calls)
+	}
+	if validate.Total != 165*time.Millisecond {
+		t.Errorf("Expected summed total 165ms, got %v", validate.Total)
+	}
+	if validate.BodyRead != 15*time.Millisecond {
+		t.Errorf("Expected body read 15ms, got %v", validate.BodyRead)
+	}
+}
+
+func TestRequestTimingBodyRead(t *testing.T) {
+	timing := requestTiming{TTFB: 20 * time.Millisecond, Total: 35 * time.Millisecond}
+	if timing.BodyRead() != 15*time.Millisecond {
+		t.Errorf("Expected 15ms body read, got %v", timing.BodyRead())
+	}
+
+	// No first byte (request failed before response) means no body read time
+	failed := requestTiming{Total: 35 * time.Millisecond}
+	if failed.BodyRead() != 0 {
+		t.Errorf("Expected 0 body read for failed request, got %v", failed.BodyRead())
+	}
+}
+
+func TestFormatTimingTable(t *testing.T) {
+	table := formatTimingTable([]timingStep{
+		{Operation: "CombinedCartQuery", Calls: 1, TTFB: 40 * time.Millisecond, Total: 50 * time.Millisecond},
+		{Operation: "CartValidateCartMutation", Calls: 3, Total: 300 * time.Millisecond},
+	})
+
+	for _, want := range []string{"CombinedCartQuery", "CartValidateCartMutation", "350.0ms", "TTFB"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("Expected timing table to contain %q:\n%s", want, table)
+		}
+	}
+}
+
+func TestGraphQLRequestRecordsTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte(`[{"data":{}}]`))
+	}))
+	defer server.Close()
+
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.graphqlURL = server.URL
+
+	if _, err := fc.graphqlRequest([]GraphQLRequest{{OperationName: "CombinedCartQuery"}}); err != nil {
+		t.Fatalf("graphqlRequest failed: %v", err)
+	}
+
+	timings := fc.latency.snapshot()
+	if len(timings) != 1 {
+		t.Fatalf("Expected 1 recorded timing, got %d", len(timings))
+	}
+
+	timing := timings[0]
+	if timing.Operation != "CombinedCartQuery" {
+		t.Errorf("Expected operation CombinedCartQuery, got %q", timing.Operation)
+	}
+	if timing.Status != 200 {
+		t.Errorf("Expected status 200, got %d", timing.Status)
+	}
+	if timing.TTFB < 5*time.Millisecond || timing.Total < timing.TTFB {
+		t.Errorf("Unexpected timing phases: ttfb=%v total=%v", timing.TTFB, timing.Total)
+	}
+	if timing.Connect == 0 {
+		t.Error("Expected connect time on a fresh connection")
+	}
+}
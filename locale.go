@@ -1,34 +1,59 @@
 package main
 
 import (
+	"embed"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Locale is one loaded language file. Keys missing from it are looked up in the
+// fallback chain: requested locale → language → en_US → the key itself.
 type Locale struct {
-	translations map[string]string
+	translations map[string]message
 	locale       string
+	fallback     *Locale
+}
+
+// message is one translation: plain text, or plural forms keyed by CLDR category
+// ("one", "few", "many", "other") and selected by the "count" parameter
+type message struct {
+	Text   string
+	Plural map[string]string
+}
+
+// UnmarshalYAML accepts both `key: "text"` and a map of plural forms
+func (m *message) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		return node.Decode(&m.Plural)
+	}
+	return node.Decode(&m.Text)
 }
 
 var globalLocale *Locale
 
-// InitLocale initializes the global locale system
-func InitLocale() error {
-	locale := DetectSystemLocale()
+// The language files are built into the binary so it works from any directory
+//
+//go:embed lang/*.yaml
+var embeddedLocales embed.FS
 
-	l, err := LoadLocale(locale)
+// InitLocale initializes the global locale system with the requested locale
+// (--lang or language: in config.yaml), or the system locale when none is given
+func InitLocale(requested string) error {
+	locale := normalizeLocale(requested)
+	if locale == "" {
+		locale = DetectSystemLocale()
+	}
+
+	l, err := LoadLocaleChain(locale)
 	if err != nil {
-		// Fallback to English
-		fmt.Printf("Warning: Failed to load locale '%s', falling back to en_US: %v\n", locale, err)
-		l, err = LoadLocale("en_US")
-		if err != nil {
-			return fmt.Errorf("failed to load fallback locale en_US: %w", err)
-		}
+		return fmt.Errorf("failed to load fallback locale en_US: %w", err)
 	}
 
 	globalLocale = l
@@ -73,26 +98,136 @@ func DetectSystemLocale() string {
 	return "en_US"
 }
 
-// LoadLocale loads a locale file from the lang/ directory
+// normalizeLocale accepts the spellings users type: "ru-RU", "ru_RU.UTF-8", "ru"
+func normalizeLocale(locale string) string {
+	locale, _, _ = strings.Cut(strings.TrimSpace(locale), ".")
+	return strings.ReplaceAll(locale, "-", "_")
+}
+
+// LoadLocaleChain loads the requested locale followed by its fallbacks: the bare
+// language (ru.yaml, or another region of it) and en_US, which is always built in.
+func LoadLocaleChain(locale string) (*Locale, error) {
+	var chain []string
+	for _, candidate := range []string{locale, languageLocale(locale), "en_US"} {
+		if candidate != "" && !containsString(chain, candidate) {
+			chain = append(chain, candidate)
+		}
+	}
+
+	var head, tail *Locale
+	for _, name := range chain {
+		l, err := LoadLocale(name)
+		if err != nil {
+			if name == "en_US" {
+				return nil, err
+			}
+			continue
+		}
+		if head == nil {
+			head = l
+		} else {
+			tail.fallback = l
+		}
+		tail = l
+	}
+
+	// Falling back to another region of the same language is expected, anything else isn't
+	if localeLanguage(head.locale) != localeLanguage(locale) {
+		fmt.Printf("Warning: No translation for locale '%s', falling back to %s\n", locale, head.locale)
+	}
+	return head, nil
+}
+
+func localeLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "_")
+	return language
+}
+
+// languageLocale picks the language-level file for a locale: ru.yaml for ru_UA,
+// otherwise the first other region of the same language (ru_RU)
+func languageLocale(locale string) string {
+	language := localeLanguage(locale)
+	available := availableLocales()
+	if containsString(available, language) {
+		return language
+	}
+	for _, name := range available {
+		if strings.HasPrefix(name, language+"_") && name != locale {
+			return name
+		}
+	}
+	return ""
+}
+
+// localeOverrideDir holds user or team translations (~/.specter/lang/<locale>.yaml)
+// that are merged over the built-in ones, or add a language of their own
+func localeOverrideDir() string {
+	return filepath.Join(getUserDataDir(), "lang")
+}
+
+// availableLocales lists the built-in locales and those in the override directory
+func availableLocales() []string {
+	var names []string
+	if entries, err := embeddedLocales.ReadDir("lang"); err == nil {
+		for _, entry := range entries {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+		}
+	}
+	overrides, _ := filepath.Glob(filepath.Join(localeOverrideDir(), "*.yaml"))
+	for _, path := range overrides {
+		if name := strings.TrimSuffix(filepath.Base(path), ".yaml"); !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadLocale loads a built-in locale and merges ~/.specter/lang/<locale>.yaml over it
+// key by key. Either one is enough, so the override directory can add a new language.
 func LoadLocale(locale string) (*Locale, error) {
-	// Get executable directory
-	exePath, err := os.Executable()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get executable path: %w", err)
+	l := &Locale{translations: map[string]message{}, locale: locale}
+	found := false
+
+	if data, err := embeddedLocales.ReadFile("lang/" + locale + ".yaml"); err == nil {
+		builtIn, err := parseLocale(locale, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse built-in locale %s: %w", locale, err)
+		}
+		l, found = builtIn, true
 	}
-	exeDir := filepath.Dir(exePath)
 
-	// Try loading from lang/ directory next to executable
-	localeFile := filepath.Join(exeDir, "lang", locale+".yaml")
+	overrideFile := filepath.Join(localeOverrideDir(), locale+".yaml")
+	data, err := os.ReadFile(overrideFile)
+	switch {
+	case err == nil:
+		override, err := parseLocale(locale, data)
+		if err != nil {
+			if !found {
+				return nil, fmt.Errorf("failed to parse locale file %s: %w", overrideFile, err)
+			}
+			// The built-in translations still work, so a broken override is only a warning
+			fmt.Printf("Warning: Ignoring locale override %s: %v\n", overrideFile, err)
+			break
+		}
+		for key, msg := range override.translations {
+			l.translations[key] = msg
+		}
+		found = true
+	case !os.IsNotExist(err):
+		fmt.Printf("Warning: Ignoring locale override %s: %v\n", overrideFile, err)
+	}
 
-	data, err := os.ReadFile(localeFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read locale file %s: %w", localeFile, err)
+	if !found {
+		return nil, fmt.Errorf("no locale file for %s (built in or in %s)", locale, localeOverrideDir())
 	}
+	return l, nil
+}
 
-	var translations map[string]string
+func parseLocale(locale string, data []byte) (*Locale, error) {
+	translations := map[string]message{}
 	if err := yaml.Unmarshal(data, &translations); err != nil {
-		return nil, fmt.Errorf("failed to parse locale file %s: %w", localeFile, err)
+		return nil, err
 	}
 
 	return &Locale{
@@ -101,26 +236,160 @@ func LoadLocale(locale string) (*Locale, error) {
 	}, nil
 }
 
-// T translates a key with optional parameters
-// Usage: T("greeting", "name", "John") => "Hello, John!"
-func T(key string, params ...interface{}) string {
-	if globalLocale == nil {
-		return key
+// lookup finds key in the locale or its fallbacks, with the locale that had it
+func (l *Locale) lookup(key string) (message, string, bool) {
+	for ; l != nil; l = l.fallback {
+		if msg, ok := l.translations[key]; ok {
+			return msg, l.locale, true
+		}
 	}
+	return message{}, "", false
+}
+
+// placeholder matches {name}, {name:%.2f} and {name:time} in a translation
+var placeholder = regexp.MustCompile(`\{([a-z_][a-z0-9_]*)(?::(%[^}]+|[a-z]+))?\}`)
 
-	translation, ok := globalLocale.translations[key]
+// T translates a key, filling named parameters given as name/value pairs.
+// Usage: T("greeting", "name", "John") with greeting: "Hello, {name}!" => "Hello, John!"
+// A "count" parameter selects the plural form: T("waves", "count", 3). Money,
+// time.Duration and time.Time values are formatted for the locale (see formatValue).
+func T(key string, params ...interface{}) string {
+	msg, locale, ok := globalLocale.lookup(key)
 	if !ok {
-		// Return key if translation not found
-		return key
+		// Return key if no locale in the chain has it, keeping the parameters visible
+		return untranslated(key, params)
+	}
+
+	values := make(map[string]interface{}, len(params)/2)
+	for i := 0; i+1 < len(params); i += 2 {
+		if name, ok := params[i].(string); ok {
+			values[name] = params[i+1]
+		}
+	}
+
+	text := msg.Text
+	if msg.Plural != nil {
+		text = msg.pluralForm(locale, values["count"])
+	}
+	if len(values) == 0 {
+		return text
+	}
+
+	return placeholder.ReplaceAllStringFunc(text, func(match string) string {
+		parts := placeholder.FindStringSubmatch(match)
+		value, ok := values[parts[1]]
+		if !ok {
+			return match
+		}
+		return formatValue(value, parts[2])
+	})
+}
+
+// untranslated renders a missing key as "key name=value ..."
+func untranslated(key string, params []interface{}) string {
+	var b strings.Builder
+	b.WriteString(key)
+	for i := 0; i+1 < len(params); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", params[i], params[i+1])
+	}
+	return b.String()
+}
+
+// TError translates a key like T and returns it as an error wrapping every error parameter,
+// so errors.Is and errors.As still see through it
+func TError(key string, params ...interface{}) error {
+	err := &translatedError{msg: T(key, params...)}
+	for i := 1; i < len(params); i += 2 {
+		if wrapped, ok := params[i].(error); ok {
+			err.wrapped = append(err.wrapped, wrapped)
+		}
+	}
+	return err
+}
+
+type translatedError struct {
+	msg     string
+	wrapped []error
+}
+
+func (e *translatedError) Error() string {
+	return e.msg
+}
+
+func (e *translatedError) Unwrap() []error {
+	return e.wrapped
+}
+
+// pluralForm picks the form for count, falling back to "other"
+func (m message) pluralForm(locale string, count interface{}) string {
+	category := "other"
+	if n, ok := pluralOperand(count); ok {
+		category = pluralCategory(locale, n)
 	}
+	if text, ok := m.Plural[category]; ok {
+		return text
+	}
+	return m.Plural["other"]
+}
 
-	// Simple parameter substitution
-	if len(params) > 0 {
-		// Build args for fmt.Sprintf style
-		return fmt.Sprintf(translation, params...)
+// pluralOperand converts an integer count; fractional and missing counts use "other"
+func pluralOperand(count interface{}) (int64, bool) {
+	switch n := count.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), true
+	case float64:
+		if n == float64(int64(n)) {
+			return int64(n), true
+		}
 	}
+	return 0, false
+}
 
-	return translation
+// pluralCategories lists the forms a plural message needs in a locale
+func pluralCategories(locale string) []string {
+	switch localeLanguage(locale) {
+	case "ru", "uk", "be":
+		return []string{"one", "few", "many", "other"}
+	case "ja", "ko", "zh":
+		return []string{"other"}
+	default:
+		return []string{"one", "other"}
+	}
+}
+
+// pluralCategory implements the CLDR cardinal rules for integers
+func pluralCategory(locale string, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+	switch localeLanguage(locale) {
+	case "ru", "uk", "be":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "ja", "ko", "zh":
+		return "other"
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
 }
 
 // GetLocale returns the current locale code (e.g., "en_US", "ru_RU")
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale-aware formatting of money, times and durations. The conventions (separators,
+// month names, unit names, patterns) are format_* keys in the language files, so they
+// follow the active locale and its fallbacks like any other translation. Without a
+// loaded locale the neutral Go formats are used.
+
+// formatValue renders a T() parameter. style is the part after the colon of a
+// placeholder: a fmt verb such as %.2f, or one of "time", "date" and "countdown".
+func formatValue(value interface{}, style string) string {
+	if strings.HasPrefix(style, "%") {
+		return fmt.Sprintf(style, value)
+	}
+
+	switch v := value.(type) {
+	case Money:
+		return FormatMoney(v)
+	case time.Duration:
+		if style == "countdown" {
+			return FormatCountdown(v)
+		}
+		return FormatDuration(v)
+	case time.Time:
+		switch style {
+		case "time":
+			return FormatTime(v)
+		case "date":
+			return FormatDate(v)
+		case "countdown":
+			return FormatCountdown(time.Until(v))
+		}
+		return FormatDateTime(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// FormatMoney formats an amount for the active locale: "$1,234.50" or "1 234,50 $"
+func FormatMoney(m Money) string {
+	if globalLocale == nil {
+		return m.String()
+	}
+
+	exp := m.exponent()
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	unit := int64(math.Pow10(exp))
+	value := groupDigits(amount / unit)
+	if exp > 0 {
+		value += T("format_decimal_separator") + fmt.Sprintf("%0*d", exp, amount%unit)
+	}
+
+	if symbol, ok := currencySymbols[m.Currency]; ok {
+		return T("format_money", "sign", sign, "symbol", symbol, "amount", value)
+	}
+	if m.Currency == "" {
+		return sign + value
+	}
+	return T("format_money_code", "sign", sign, "amount", value, "code", m.Currency)
+}
+
+// groupDigits writes n with the locale's thousands separator
+func groupDigits(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	separator := T("format_group_separator")
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// formatDecimal writes v with up to places decimals, dropping trailing zeros
+func formatDecimal(v float64, places int) string {
+	s := strconv.FormatFloat(v, 'f', places, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return strings.Replace(s, ".", T("format_decimal_separator"), 1)
+}
+
+// FormatDuration formats a duration for the active locale. Under a second it's shown
+// in milliseconds, under a minute in seconds with up to two decimals, and otherwise
+// as whole days, hours, minutes and seconds: "1h 2m 3s" or "1 ч 2 мин 3 с".
+func FormatDuration(d time.Duration) string {
+	if globalLocale == nil {
+		return d.String()
+	}
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	switch {
+	case d == 0:
+		return T("format_duration_seconds", "value", 0)
+	case d < time.Second:
+		return sign + T("format_duration_milliseconds", "value", int64(d.Round(time.Millisecond)/time.Millisecond))
+	case d < time.Minute:
+		return sign + T("format_duration_seconds", "value", formatDecimal(d.Seconds(), 2))
+	}
+
+	d = d.Round(time.Second)
+	units := []struct {
+		key  string
+		size time.Duration
+	}{
+		{"format_duration_days", 24 * time.Hour},
+		{"format_duration_hours", time.Hour},
+		{"format_duration_minutes", time.Minute},
+		{"format_duration_seconds", time.Second},
+	}
+
+	var parts []string
+	for _, unit := range units {
+		n := int64(d / unit.size)
+		d -= time.Duration(n) * unit.size
+		if n == 0 {
+			continue
+		}
+		parts = append(parts, T(unit.key, "value", n))
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// FormatCountdown describes a duration relative to now: "in 5m", "5m ago" or "now".
+// It's rounded to whole seconds.
+func FormatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	if globalLocale == nil {
+		return d.String()
+	}
+
+	switch {
+	case d == 0:
+		return T("format_countdown_now")
+	case d > 0:
+		return T("format_countdown_future", "duration", d)
+	}
+	return T("format_countdown_past", "duration", -d)
+}
+
+// FormatTime formats the local time of day, "15:04:05 MST" by default
+func FormatTime(t time.Time) string {
+	if globalLocale == nil {
+		return t.Local().Format("15:04:05 MST")
+	}
+	return t.Local().Format(T("format_time"))
+}
+
+// FormatDate formats the local date with the locale's month names: "Jan 2, 2006"
+// or "2 января 2006"
+func FormatDate(t time.Time) string {
+	t = t.Local()
+	if globalLocale == nil {
+		return t.Format("2006-01-02")
+	}
+
+	month := t.Month().String()[:3]
+	if months := strings.Split(T("format_months"), ","); len(months) == 12 {
+		month = strings.TrimSpace(months[t.Month()-1])
+	}
+	return T("format_date", "day", t.Day(), "month", month, "year", t.Year())
+}
+
+// FormatDateTime formats the local date and time of day
+func FormatDateTime(t time.Time) string {
+	if globalLocale == nil {
+		return t.Local().Format("2006-01-02 15:04:05 MST")
+	}
+	return T("format_datetime", "date", FormatDate(t), "time", FormatTime(t))
+}
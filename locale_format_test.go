@@ -0,0 +1,146 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// withBuiltinLocale installs a built-in locale chain, in UTC, for the rest of the test
+func withBuiltinLocale(t *testing.T, name string) {
+	t.Setenv("HOME", t.TempDir())
+	l, err := LoadLocaleChain(name)
+	if err != nil {
+		t.Fatalf("LoadLocaleChain(%q) failed: %v", name, err)
+	}
+	withLocale(t, l)
+
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		locale string
+		money  Money
+		want   string
+	}{
+		{"en_US", newMoney(4500, "USD"), "$45.00"},
+		{"en_US", newMoney(123456789, "USD"), "$1,234,567.89"},
+		{"en_US", newMoney(-1250, "USD"), "-$12.50"},
+		{"en_US", newMoney(1999, "CAD"), "19.99 CAD"},
+		{"en_US", newMoney(1500, "JPY"), "1,500 JPY"},
+		{"ru_RU", newMoney(4500, "USD"), "45,00 $"},
+		{"ru_RU", newMoney(123456789, "EUR"), "1\u00a0234\u00a0567,89 €"},
+		{"ru_RU", newMoney(-1250, "USD"), "-12,50 $"},
+		{"ru_RU", newMoney(1999, "CAD"), "19,99 CAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.want, func(t *testing.T) {
+			withBuiltinLocale(t, tt.locale)
+			if got := FormatMoney(tt.money); got != tt.want {
+				t.Errorf("FormatMoney(%v) = %q, want %q", tt.money, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		en, ru string
+	}{
+		{0, "0s", "0 с"},
+		{350 * time.Millisecond, "350ms", "350 мс"},
+		{1250 * time.Millisecond, "1.25s", "1,25 с"},
+		{42 * time.Second, "42s", "42 с"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1h 2m 3s", "1 ч 2 мин 3 с"},
+		{2 * time.Hour, "2h", "2 ч"},
+		{26*time.Hour + 30*time.Minute, "1d 2h 30m", "1 д 2 ч 30 мин"},
+		{-90 * time.Second, "-1m 30s", "-1 мин 30 с"},
+	}
+	for _, locale := range []string{"en_US", "ru_RU"} {
+		t.Run(locale, func(t *testing.T) {
+			withBuiltinLocale(t, locale)
+			for _, tt := range tests {
+				want := tt.en
+				if locale == "ru_RU" {
+					want = tt.ru
+				}
+				if got := FormatDuration(tt.d); got != want {
+					t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	withBuiltinLocale(t, "ru_RU")
+
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{5*time.Minute + 400*time.Millisecond, "через 5 мин"},
+		{-2 * time.Hour, "2 ч назад"},
+		{300 * time.Millisecond, "сейчас"},
+	}
+	for _, tt := range tests {
+		if got := FormatCountdown(tt.d); got != tt.want {
+			t.Errorf("FormatCountdown(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	moment := time.Date(2026, time.March, 7, 18, 5, 9, 0, time.UTC)
+
+	withBuiltinLocale(t, "en_US")
+	if got := FormatDateTime(moment); got != "Mar 7, 2026 18:05:09 UTC" {
+		t.Errorf("en_US FormatDateTime = %q", got)
+	}
+
+	withBuiltinLocale(t, "ru_RU")
+	if got := FormatDateTime(moment); got != "7 марта 2026, 18:05:09 UTC" {
+		t.Errorf("ru_RU FormatDateTime = %q", got)
+	}
+	if got := FormatTime(moment); got != "18:05:09 UTC" {
+		t.Errorf("ru_RU FormatTime = %q", got)
+	}
+}
+
+// Test that T formats Money, durations and times by type and placeholder style
+func TestTranslationFormatsValues(t *testing.T) {
+	russian := &Locale{locale: "ru_RU", translations: map[string]message{
+		"format_decimal_separator": {Text: ","},
+		"format_money":             {Text: "{sign}{amount} {symbol}"},
+		"format_time":              {Text: "15:04"},
+		"format_duration_minutes":  {Text: "{value} мин"},
+		"format_countdown_future":  {Text: "через {duration}"},
+		"summary":                  {Text: "{price}, {wait}, {at:time}, {left:countdown}, {ratio:%.1f}"},
+	}}
+	withLocale(t, russian)
+
+	got := T("summary",
+		"price", newMoney(1999, "USD"),
+		"wait", 3*time.Minute,
+		"at", time.Date(2026, time.March, 7, 18, 5, 0, 0, time.Local),
+		"left", 10*time.Minute,
+		"ratio", 0.27)
+	if want := "19,99 $, 3 мин, 18:05, через 10 мин, 0.3"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// Test that without a locale the neutral Go formats are used
+func TestFormatWithoutLocale(t *testing.T) {
+	withLocale(t, nil)
+
+	if got := FormatMoney(newMoney(4500, "USD")); got != "$45.00" {
+		t.Errorf("FormatMoney = %q", got)
+	}
+	if got := FormatDuration(90 * time.Second); got != "1m30s" {
+		t.Errorf("FormatDuration = %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -112,15 +113,15 @@ error_chrome_already_running: "browser already running"
 	t.Run("Load valid locale file", func(t *testing.T) {
 		// We can't easily mock os.Executable in tests, so we test the structure
 		locale := &Locale{
-			translations: map[string]string{
-				"test_key":        "Test Value",
-				"test_with_param": "Hello, %s!",
+			translations: map[string]message{
+				"test_key":        {Text: "Test Value"},
+				"test_with_param": {Text: "Hello, {name}!"},
 			},
 			locale: "test_locale",
 		}
 
-		if locale.translations["test_key"] != "Test Value" {
-			t.Errorf("Expected 'Test Value', got '%s'", locale.translations["test_key"])
+		if locale.translations["test_key"].Text != "Test Value" {
+			t.Errorf("Expected 'Test Value', got '%s'", locale.translations["test_key"].Text)
 		}
 
 		if locale.locale != "test_locale" {
@@ -142,13 +143,14 @@ error_chrome_already_running: "browser already running"
 func TestTranslationFunction(t *testing.T) {
 	// Set up a test locale
 	testLocale := &Locale{
-		translations: map[string]string{
-			"simple_key":           "Simple Translation",
-			"key_with_param":       "Hello, %s!",
-			"key_with_two_params":  "User %s has %d messages",
-			"browser_using_system_chrome": "✓ Using system Chrome browser",
-			"error_chrome_already_running": "browser already running - please close Chrome completely",
-			"error_macos_permission_header": "\n⚠️  macOS Security Warning: Cannot create directory",
+		translations: map[string]message{
+			"simple_key":           {Text: "Simple Translation"},
+			"key_with_param":       {Text: "Hello, {name}!"},
+			"key_with_two_params":  {Text: "User {user} has {count} messages"},
+			"key_with_format":      {Text: "Waiting {seconds:%.2f} seconds"},
+			"browser_using_system_chrome": {Text: "✓ Using system Chrome browser"},
+			"error_chrome_already_running": {Text: "browser already running - please close Chrome completely"},
+			"error_macos_permission_header": {Text: "\n⚠️  macOS Security Warning: Cannot create directory"},
 		},
 		locale: "test",
 	}
@@ -175,15 +177,27 @@ func TestTranslationFunction(t *testing.T) {
 		{
 			name:           "Translation with one parameter",
 			key:            "key_with_param",
-			params:         []interface{}{"World"},
+			params:         []interface{}{"name", "World"},
 			expectedOutput: "Hello, World!",
 		},
 		{
 			name:           "Translation with two parameters",
 			key:            "key_with_two_params",
-			params:         []interface{}{"Alice", 5},
+			params:         []interface{}{"count", 5, "user", "Alice"},
 			expectedOutput: "User Alice has 5 messages",
 		},
+		{
+			name:           "Translation with a format verb",
+			key:            "key_with_format",
+			params:         []interface{}{"seconds", 1.5},
+			expectedOutput: "Waiting 1.50 seconds",
+		},
+		{
+			name:           "Unknown parameter is left in place",
+			key:            "key_with_param",
+			params:         []interface{}{"other", "World"},
+			expectedOutput: "Hello, {name}!",
+		},
 		{
 			name:           "Browser message translation",
 			key:            "browser_using_system_chrome",
@@ -230,7 +244,7 @@ func TestGetLocale(t *testing.T) {
 
 	// Test with global locale set
 	globalLocale = &Locale{
-		translations: map[string]string{},
+		translations: map[string]message{},
 		locale:       "ru_RU",
 	}
 
@@ -360,3 +374,187 @@ func TestLocaleFallback(t *testing.T) {
 		})
 	}
 }
+
+// withLocale installs l as the global locale for the rest of the test
+func withLocale(t *testing.T, l *Locale) {
+	original := globalLocale
+	globalLocale = l
+	t.Cleanup(func() { globalLocale = original })
+}
+
+// Test that a key missing from the requested locale is taken from the next one in the chain
+func TestKeyLevelFallback(t *testing.T) {
+	english := &Locale{locale: "en_US", translations: map[string]message{
+		"greeting": {Text: "Hello, {name}!"},
+		"farewell": {Text: "Goodbye"},
+	}}
+	language := &Locale{locale: "ru", fallback: english, translations: map[string]message{
+		"farewell": {Text: "До свидания"},
+	}}
+	withLocale(t, &Locale{locale: "ru_UA", fallback: language, translations: map[string]message{}})
+
+	if got := T("farewell"); got != "До свидания" {
+		t.Errorf("Expected the language-level translation, got %q", got)
+	}
+	if got := T("greeting", "name", "John"); got != "Hello, John!" {
+		t.Errorf("Expected the en_US translation, got %q", got)
+	}
+	if got := T("missing_everywhere"); got != "missing_everywhere" {
+		t.Errorf("Expected the key itself, got %q", got)
+	}
+}
+
+// Test CLDR plural categories for English and Russian
+func TestPluralForms(t *testing.T) {
+	english := &Locale{locale: "en_US", translations: map[string]message{
+		"waves": {Plural: map[string]string{"one": "{count} wave", "other": "{count} waves"}},
+	}}
+	russian := &Locale{locale: "ru_RU", fallback: english, translations: map[string]message{
+		"attempts": {Plural: map[string]string{
+			"one":   "{count} попытка",
+			"few":   "{count} попытки",
+			"many":  "{count} попыток",
+			"other": "{count} попытки",
+		}},
+	}}
+	withLocale(t, russian)
+
+	tests := []struct {
+		key   string
+		count interface{}
+		want  string
+	}{
+		{"attempts", 1, "1 попытка"},
+		{"attempts", 3, "3 попытки"},
+		{"attempts", 5, "5 попыток"},
+		{"attempts", 11, "11 попыток"},
+		{"attempts", 21, "21 попытка"},
+		{"attempts", 24, "24 попытки"},
+		{"attempts", 112, "112 попыток"},
+		{"attempts", 1.5, "1.5 попытки"},
+		// Falling back to en_US also falls back to the English rules
+		{"waves", 1, "1 wave"},
+		{"waves", 2, "2 waves"},
+		{"waves", 0, "0 waves"},
+	}
+	for _, tt := range tests {
+		if got := T(tt.key, "count", tt.count); got != tt.want {
+			t.Errorf("T(%q, count=%v) = %q, want %q", tt.key, tt.count, got, tt.want)
+		}
+	}
+}
+
+// Test that translated errors still wrap the errors passed as parameters
+func TestTErrorWrapsParameters(t *testing.T) {
+	withLocale(t, &Locale{locale: "en_US", translations: map[string]message{
+		"error_read": {Text: "failed to read {path}: {error}"},
+	}})
+
+	err := TError("error_read", "path", "config.yaml", "error", os.ErrNotExist)
+	if err.Error() != "failed to read config.yaml: "+os.ErrNotExist.Error() {
+		t.Errorf("Unexpected message %q", err)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected the error parameter to be wrapped")
+	}
+}
+
+// Test that the built-in locales load without any files on disk and that
+// ~/.specter/lang is merged over them key by key
+func TestLoadLocaleWithOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	l, err := LoadLocaleChain("ru_RU")
+	if err != nil {
+		t.Fatalf("LoadLocaleChain failed: %v", err)
+	}
+	if l.locale != "ru_RU" || l.fallback == nil || l.fallback.locale != "en_US" {
+		t.Fatalf("Expected the ru_RU → en_US chain, got %+v", l)
+	}
+	withLocale(t, l)
+	if got := T("shutting_down"); got == "shutting_down" {
+		t.Error("Built-in translations should load without a lang/ directory")
+	}
+
+	dir := filepath.Join(home, ".specter", "lang")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"ru_RU.yaml": "shutting_down: \"Выключаемся\"\n",
+		"de_DE.yaml": "shutting_down: \"Wird beendet\"\n",
+		"en_US.yaml": "shutting_down: [broken\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ru, err := LoadLocale("ru_RU")
+	if err != nil {
+		t.Fatalf("LoadLocale failed: %v", err)
+	}
+	if ru.translations["shutting_down"].Text != "Выключаемся" {
+		t.Errorf("Override not applied: %q", ru.translations["shutting_down"].Text)
+	}
+	if ru.translations["app_starting"].Text == "" {
+		t.Error("Keys missing from the override should keep their built-in translation")
+	}
+
+	// A language that only exists as an override falls back to the built-in English
+	de, err := LoadLocaleChain("de_DE")
+	if err != nil {
+		t.Fatalf("LoadLocaleChain failed: %v", err)
+	}
+	withLocale(t, de)
+	if got := T("shutting_down"); got != "Wird beendet" {
+		t.Errorf("Expected the override language, got %q", got)
+	}
+	if got := T("app_starting"); got == "app_starting" {
+		t.Error("Expected keys missing from de_DE to come from en_US")
+	}
+
+	// A broken override doesn't take the built-in locale down with it
+	if en, err := LoadLocale("en_US"); err != nil || en.translations["shutting_down"].Text == "" {
+		t.Errorf("Broken override should be ignored, got %v", err)
+	}
+
+	if _, err := LoadLocale("xx_XX"); err == nil {
+		t.Error("Expected an error for a locale that exists nowhere")
+	}
+}
+
+// Test that the shipped lang files parse and carry the same plural keys
+func TestLangFilesParse(t *testing.T) {
+	locales := map[string]*Locale{}
+	for _, name := range []string{"en_US", "ru_RU"} {
+		data, err := embeddedLocales.ReadFile("lang/" + name + ".yaml")
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		l, err := parseLocale(name, data)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", name, err)
+		}
+		locales[name] = l
+	}
+
+	for key, msg := range locales["en_US"].translations {
+		if msg.Plural == nil {
+			continue
+		}
+		if locales["ru_RU"].translations[key].Plural == nil {
+			t.Errorf("%s is plural in en_US but not in ru_RU", key)
+		}
+		if _, ok := msg.Plural["other"]; !ok {
+			t.Errorf("%s has no \"other\" form", key)
+		}
+	}
+
+	withLocale(t, locales["ru_RU"])
+	if got := T("multiwave_configured_waves", "count", 2); got != "📊 Настроено 2 волны продаж на сегодня" {
+		t.Errorf("Unexpected plural form %q", got)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// The log file is a full trace of every run: everything the reporters are given, debug
+// messages included, as one JSON object per line with the component and the wave,
+// operation or attempt it concerns. It doesn't depend on the console output mode or on
+// debug_mode, so a run can be looked into after the fact.
+
+// logFileName is the current log file; rotated ones are specter.1.log, specter.2.log, ...
+const logFileName = "specter.log"
+
+// traceLogger receives the trace; it discards everything until InitLogging runs
+var traceLogger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+// logLevels maps the log_level option to slog levels
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// logDir is where log files are kept (~/.specter/logs)
+func logDir() string {
+	return filepath.Join(getUserDataDir(), "logs")
+}
+
+// validLogLevel reports whether level is one of logLevels (empty means debug)
+func validLogLevel(level string) bool {
+	_, ok := logLevels[level]
+	return ok || level == ""
+}
+
+// InitLogging starts writing the trace to the rotating log file. The returned closer
+// closes the file at the end of the run.
+func InitLogging(config *Config) (io.Closer, error) {
+	file, err := openRotatingFile(logDir(), logFileName, int64(config.LogMaxSizeMB)*1024*1024, config.LogMaxFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	level, ok := logLevels[config.LogLevel]
+	if !ok {
+		level = slog.LevelDebug
+	}
+
+	traceLogger = slog.New(slog.NewJSONHandler(file, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactLogAttr,
+	})).With("pid", os.Getpid())
+	return file, nil
+}
+
+// redactLogAttr keeps tokens and cookie values out of the log file, like the event log
+func redactLogAttr(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindString {
+		attr.Value = slog.StringValue(redactText(attr.Value.String()))
+	}
+	return attr
+}
+
+// rotatingFile is an append-only log file that's moved aside once it reaches maxSize.
+// The keep most recent rotated files are kept.
+type rotatingFile struct {
+	mu      sync.Mutex
+	dir     string
+	name    string
+	maxSize int64
+	keep    int
+
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(dir, name string, maxSize int64, keep int) (*rotatingFile, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{dir: dir, name: name, maxSize: maxSize, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(filepath.Join(r.dir, r.name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// rotatedName is the name of the n-th rotated file: specter.log -> specter.n.log
+func (r *rotatingFile) rotatedName(n int) string {
+	ext := filepath.Ext(r.name)
+	return filepath.Join(r.dir, fmt.Sprintf("%s.%d%s", strings.TrimSuffix(r.name, ext), n, ext))
+}
+
+// rotate shifts the rotated files up by one, dropping the oldest, and starts a new file
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+
+	os.Remove(r.rotatedName(r.keep))
+	for n := r.keep - 1; n >= 1; n-- {
+		os.Rename(r.rotatedName(n), r.rotatedName(n+1))
+	}
+	if r.keep > 0 {
+		os.Rename(filepath.Join(r.dir, r.name), r.rotatedName(1))
+	} else {
+		os.Remove(filepath.Join(r.dir, r.name))
+	}
+
+	return r.open()
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// consoleDebugAttempts is how many attempts of a retry loop show their debug details on
+// the console; the log file gets every attempt
+const consoleDebugAttempts = 3
+
+// debugAttempt reports debug details of one attempt of a retry loop
+func debugAttempt(reporter Reporter, attempt int, text string) {
+	reporter = reporter.With("attempt", attempt)
+	if attempt <= consoleDebugAttempts {
+		reporter.Debug(text)
+	} else {
+		reporter.Trace(text)
+	}
+}
+
+// tracingReporter writes everything it's given to the log file, then hands it on to the
+// console renderer. Debug messages reach the console only in debug mode.
+type tracingReporter struct {
+	Reporter
+	log     *slog.Logger
+	console bool
+}
+
+func (t *tracingReporter) With(args ...interface{}) Reporter {
+	return &tracingReporter{Reporter: t.Reporter, log: t.log.With(args...), console: t.console}
+}
+
+// Controls passes on the dashboard's keys; without a dashboard the channel is nil
+func (t *tracingReporter) Controls() <-chan Control {
+	if source, ok := t.Reporter.(ControlSource); ok {
+		return source.Controls()
+	}
+	return nil
+}
+
+func (t *tracingReporter) Close() error {
+	closeReporter(t.Reporter)
+	return nil
+}
+
+func (t *tracingReporter) WaveScheduled(event WaveScheduled) {
+	t.log.Info("wave scheduled", "wave", event.Wave, "total", event.Total, "start", event.Start, "activation", event.Activation)
+	t.Reporter.WaveScheduled(event)
+}
+
+func (t *tracingReporter) WaveStateChanged(event WaveStateChanged) {
+	t.log.Info("wave state", "wave", event.Wave, "state", event.State)
+	t.Reporter.WaveStateChanged(event)
+}
+
+func (t *tracingReporter) TimeSynced(event TimeSynced) {
+	t.log.Info("time synced", "offset", event.Offset, "error_bound", event.ErrorBound)
+	t.Reporter.TimeSynced(event)
+}
+
+func (t *tracingReporter) PollProgress(event PollProgress) {
+	t.log.Debug("poll", "attempt", event.Attempt, "status", event.Status, "until", event.Until)
+	t.Reporter.PollProgress(event)
+}
+
+func (t *tracingReporter) SessionHealth(event SessionHealth) {
+	t.log.Info("session health", "healthy", event.Healthy, "problem", event.Problem)
+	t.Reporter.SessionHealth(event)
+}
+
+func (t *tracingReporter) CartWarning(event CartWarning) {
+	t.log.Warn("cart warning", "reason", event.Reason, "target_sku", event.TargetSKU, "items", len(event.Items), "total", event.Total.String(), "expected", event.Expected.String())
+	t.Reporter.CartWarning(event)
+}
+
+func (t *tracingReporter) CheckoutStep(event CheckoutStep) {
+	t.log.Info("checkout step", "step", event.Step, "balance", event.Balance.String())
+	t.Reporter.CheckoutStep(event)
+}
+
+func (t *tracingReporter) ValidateAttempt(event ValidateAttempt) {
+	t.log.Debug("validate attempt failed", "attempt", event.Attempt, "class", event.Class)
+	t.Reporter.ValidateAttempt(event)
+}
+
+func (t *tracingReporter) Success(event Success) {
+	t.log.Info("success", "elapsed", event.Elapsed, "dry_run", event.DryRun)
+	t.Reporter.Success(event)
+}
+
+func (t *tracingReporter) Info(text string) {
+	t.message(slog.LevelInfo, text)
+	t.Reporter.Info(text)
+}
+
+func (t *tracingReporter) Warn(text string) {
+	t.message(slog.LevelWarn, text)
+	t.Reporter.Warn(text)
+}
+
+func (t *tracingReporter) Error(text string) {
+	t.message(slog.LevelError, text)
+	t.Reporter.Error(text)
+}
+
+func (t *tracingReporter) Debug(text string) {
+	t.message(slog.LevelDebug, text)
+	if t.console {
+		t.Reporter.Debug(text)
+	}
+}
+
+func (t *tracingReporter) Trace(text string) {
+	t.message(slog.LevelDebug, text)
+}
+
+// message logs a line of text; blank lines only matter on the console
+func (t *tracingReporter) message(level slog.Level, text string) {
+	if text = strings.TrimSpace(text); text != "" {
+		t.log.Log(context.Background(), level, text)
+	}
+}
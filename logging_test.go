@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	file, err := openRotatingFile(dir, "specter.log", 10, 2)
+	if err != nil {
+		t.Fatalf("openRotatingFile failed: %v", err)
+	}
+	defer file.Close()
+
+	// 10 bytes per file: three and four don't fit after what's already there
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		"specter.log":   "four\nfive\n",
+		"specter.1.log": "three\n",
+		"specter.2.log": "one\ntwo\n",
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != len(want) {
+		t.Errorf("Expected %d files, got %d", len(want), len(entries))
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s: expected %q, got %q (%v)", name, content, data, err)
+		}
+	}
+}
+
+// Test that the log file gets debug details the console doesn't show, with attributes
+func TestTracingReporter(t *testing.T) {
+	withLocale(t, nil)
+
+	var trace bytes.Buffer
+	logger := traceLogger
+	traceLogger = slog.New(slog.NewJSONHandler(&trace, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	t.Cleanup(func() { traceLogger = logger })
+
+	var console bytes.Buffer
+	r := &tracingReporter{Reporter: &HumanReporter{Out: &console}, log: traceLogger.With("component", "checkout")}
+
+	r.Info("")
+	r.Info("adding to cart")
+	r.Debug("request body")
+	debugAttempt(r.With("wave", 2), 5, "attempt response")
+	r.ValidateAttempt(ValidateAttempt{Attempt: 7, Class: ValidateOutOfStock})
+
+	if got := console.String(); got != "\nadding to cart\n" {
+		t.Errorf("Unexpected console output: %q", got)
+	}
+
+	events := decodeLines(t, trace.String())
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 log entries (blank line skipped), got %d:\n%s", len(events), trace.String())
+	}
+	if events[0]["msg"] != "adding to cart" || events[0]["level"] != "INFO" || events[0]["component"] != "checkout" {
+		t.Errorf("Unexpected entry: %v", events[0])
+	}
+	if events[1]["msg"] != "request body" || events[1]["level"] != "DEBUG" {
+		t.Errorf("Unexpected entry: %v", events[1])
+	}
+	if events[2]["msg"] != "attempt response" || events[2]["wave"] != 2.0 || events[2]["attempt"] != 5.0 {
+		t.Errorf("Unexpected entry: %v", events[2])
+	}
+	if events[3]["class"] != "out_of_stock" || events[3]["attempt"] != 7.0 {
+		t.Errorf("Unexpected entry: %v", events[3])
+	}
+
+	// In debug mode the first attempts are shown on the console too
+	console.Reset()
+	r.console = true
+	debugAttempt(r, 3, "shown")
+	debugAttempt(r, 4, "log only")
+	if !strings.Contains(console.String(), "shown") || strings.Contains(console.String(), "log only") {
+		t.Errorf("Unexpected console output: %q", console.String())
+	}
+}
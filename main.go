@@ -12,120 +12,123 @@ import (
 )
 
 func main() {
-	configPath := flag.String("config", "config.yaml", "Path to configuration file")
-	url := flag.String("url", "", "Direct URL to the ship/item to purchase (overrides config)")
-	dryRun := flag.Bool("dry-run", false, "Test mode: stop before final purchase")
-	debug := flag.Bool("debug", false, "Enable detailed debug logging")
-	skipCart := flag.Bool("skip-cart", false, "Skip adding to cart (item already in cart)")
-	preWaveMinutes := flag.Int("pre-wave", 2, "Minutes before wave to start polling (default: 2)")
-	postWaveMinutes := flag.Int("post-wave", 5, "Minutes after wave to timeout (default: 5)")
-	flag.Parse()
-
-	// Initialize localization
-	if err := InitLocale(); err != nil {
-		log.Printf("Warning: Locale initialization failed, using default English: %v", err)
-	}
-
-	// Check for user data directory permission issues (after locale is loaded)
-	checkUserDataDirPermissions()
-
-	config, err := LoadConfig(*configPath)
+	name, err := runCLI(os.Args[1:])
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		log.Fatalf("%s: %v", name, err)
 	}
+}
 
-	if *url != "" {
-		config.ItemURL = *url
+// runFlags adds the flags of "specter run" (also the default without a command)
+func runFlags(fs *flag.FlagSet) func(config *Config) {
+	url := fs.String("url", "", "Direct URL to the ship/item to purchase (overrides config)")
+	dryRun := fs.Bool("dry-run", false, "Test mode: stop before final purchase")
+	skipCart := fs.Bool("skip-cart", false, "Skip adding to cart (item already in cart)")
+	tui := fs.Bool("tui", false, "Full-screen dashboard: wave table, live counters and keys to skip a wave, resync or abort")
+	applyWaveTiming := waveTimingFlags(fs)
+
+	return func(config *Config) {
+		if *url != "" {
+			config.ItemURL = *url
+		}
+		if *dryRun {
+			config.DryRun = true
+		}
+		if *skipCart {
+			config.SkipAddToCart = true
+		}
+		if *tui {
+			config.Output = OutputTUI
+		}
+		applyWaveTiming(config)
 	}
+}
 
-	if *dryRun {
-		config.DryRun = true
+// runCheckoutCommand handles "specter run": wait for the configured waves and check out
+func runCheckoutCommand(env *commandEnv, args []string) error {
+	config, reporter := env.Config, env.Reporter
+	if len(args) > 0 {
+		return TError("command_run_usage")
 	}
-	if *debug {
-		config.DebugMode = true
-	}
-	if *skipCart {
-		config.SkipAddToCart = true
-	}
-
-	// Configure wave timing parameters
-	config.PreWaveActivationMinutes = *preWaveMinutes
-	config.PostWaveTimeoutMinutes = *postWaveMinutes
 
 	// Validate that sale windows are configured
 	if len(config.SaleWindows) == 0 {
-		log.Fatal("No sale windows configured. Please configure sale_windows in config.yaml (format: YYYY-MM-DD HH:MM in UTC)")
+		return TError("error_run_no_sale_windows")
 	}
 
 	if config.ItemURL == "" && !config.SkipAddToCart {
-		log.Fatal("No item URL specified. Use -url flag or set it in config.yaml")
+		return TError("error_run_no_item_url")
 	}
 
-	fmt.Println(T("app_header"))
-	fmt.Println()
+	reporter.Info(T("app_header"))
+	reporter.Info("")
 	if config.ItemURL != "" {
-		fmt.Printf(T("target_url")+"\n", config.ItemURL)
+		reporter.Info(T("target_url", "url", config.ItemURL))
 	}
-	fmt.Printf(T("browser_profile")+"\n", config.BrowserProfilePath)
+	reporter.Info(T("browser_profile", "path", config.BrowserProfilePath))
 
 	if config.DryRun {
-		fmt.Println(T("dry_run_mode"))
+		reporter.Info(T("dry_run_mode"))
 	}
 	if config.DebugMode {
-		fmt.Println(T("debug_mode"))
+		reporter.Info(T("debug_mode"))
 	}
 	if config.SkipAddToCart {
-		fmt.Println(T("skip_cart_mode"))
+		reporter.Info(T("skip_cart_mode"))
+	}
+	if config.Unattended {
+		reporter.Info(T("unattended_mode"))
+	}
+	if config.RecordCassette != "" {
+		reporter.Info(T("cassette_record_mode", "path", config.RecordCassette))
+	}
+	if config.ReplayCassette != "" {
+		reporter.Info(T("cassette_replay_mode", "path", config.ReplayCassette))
 	}
 
-	fmt.Println(T("multiwave_mode_enabled"))
-	fmt.Printf(T("multiwave_num_waves")+"\n", len(config.SaleWindows))
-	fmt.Printf(T("multiwave_prewave_minutes")+"\n", config.PreWaveActivationMinutes)
-	fmt.Printf(T("multiwave_postwave_minutes")+"\n", config.PostWaveTimeoutMinutes)
-	fmt.Println()
-	fmt.Println(T("multiwave_wave_list"))
+	reporter.Info(T("multiwave_mode_enabled"))
+	reporter.Info(T("multiwave_num_waves", "count", len(config.SaleWindows)))
+	reporter.Info(T("multiwave_prewave_minutes", "count", config.PreWaveActivationMinutes))
+	reporter.Info(T("multiwave_postwave_minutes", "count", config.PostWaveTimeoutMinutes))
+	reporter.Info("")
+	reporter.Info(T("multiwave_wave_list"))
 	for i, waveTime := range config.SaleWindows {
-		t, _ := time.Parse(time.RFC3339, waveTime)
-		fmt.Printf("  Wave %d: %s (%s)\n", i+1, waveTime, t.Local().Format("15:04:05 MST"))
+		t, _ := ParseSaleTime(waveTime)
+		reporter.Info(T("multiwave_wave_time", "number", i+1, "time", t, "countdown", time.Until(t)))
 	}
 
-	fmt.Println(T("fast_api_mode"))
-	fmt.Println()
-
-	fmt.Println(T("step1_browser_setup"))
-	automation := NewAutomation(config)
-	defer automation.Close()
+	reporter.Info(T("fast_api_mode"))
+	reporter.Info("")
 
-	if err := automation.setupBrowser(); err != nil {
-		log.Fatalf("Failed to setup browser: %v", err)
+	reporter.Info(T("step1_browser_setup"))
+	fastCheckout, automation, err := env.Browser()
+	if err != nil {
+		return err
 	}
 
-	if err := automation.waitForLogin(); err != nil {
-		log.Fatalf("Failed to wait for login: %v", err)
-	}
+	reporter.Info(T("step2_init_fast_checkout"))
 
-	fmt.Println(T("step2_init_fast_checkout"))
-	fastCheckout, err := NewFastCheckout(config)
-	if err != nil {
-		log.Fatalf("Failed to initialize fast checkout: %v", err)
+	// Open the store connection now; each wave re-warms it before polling starts
+	if err := fastCheckout.WarmUp(); err != nil {
+		reporter.Warn(T("connection_warmup_failed", "error", err))
 	}
 
-	fmt.Println(T("step3_running_checkout"))
+	reporter.Info(T("step3_running_checkout"))
 
 	// Run multi-wave automated checkout
 	orchestrator := NewMultiWaveOrchestrator(config, automation, fastCheckout)
 	if err := orchestrator.Run(); err != nil {
-		log.Fatalf("Multi-wave checkout failed: %v", err)
+		return TError("error_run_multiwave_failed", "error", err)
 	}
 
-	fmt.Println()
-	fmt.Println(T("checkout_completed"))
-	fmt.Println()
+	reporter.Info("")
+	reporter.Info(T("checkout_completed"))
+	reporter.Info("")
 
 	if config.KeepBrowserOpen {
-		fmt.Println(T("keeping_browser_open"))
+		reporter.Info(T("keeping_browser_open"))
 		time.Sleep(30 * time.Second)
 	}
+	return nil
 }
 
 // Store init error for later display (after locale is loaded)
@@ -144,7 +147,7 @@ func checkUserDataDirPermissions() {
 		// Check if this is a macOS permission issue
 		if runtime.GOOS == "darwin" && strings.Contains(initUserDataDirError.Error(), "operation not permitted") {
 			fmt.Println(T("error_macos_permission_header"))
-			fmt.Printf(T("error_macos_permission_location"), userDataDir)
+			fmt.Print(T("error_macos_permission_location", "path", userDataDir))
 			fmt.Println(T("error_macos_permission_fix_instructions"))
 			fmt.Println(T("error_macos_permission_step1"))
 			fmt.Println(T("error_macos_permission_step2"))
@@ -153,7 +156,7 @@ func checkUserDataDirPermissions() {
 			fmt.Println(T("error_macos_permission_alternative"))
 			fmt.Println()
 		}
-		log.Printf(T("error_macos_user_data_dir_warning"), initUserDataDirError)
+		log.Print(T("error_macos_user_data_dir_warning", "error", initUserDataDirError))
 	}
 }
 
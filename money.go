@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Money is an amount in the minor units (cents) of a currency. The store reports
+// amounts as integer cents, so keeping them as integers makes every sum and
+// comparison exact.
+type Money struct {
+	Amount   int64  `json:"amount"`   // Minor units, e.g. cents
+	Currency string `json:"currency"` // ISO 4217 code
+}
+
+// defaultCurrency is the currency of the pledge store unless configured otherwise
+const defaultCurrency = "USD"
+
+// currencyExponents lists currencies that don't have two decimal places
+var currencyExponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
+
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+}
+
+// newMoney returns an amount in minor units
+func newMoney(minor int64, currency string) Money {
+	return Money{Amount: minor, Currency: currency}
+}
+
+// moneyFromStore converts an amount from a store response, which arrives as a JSON
+// number of minor units
+func moneyFromStore(minor float64, currency string) Money {
+	return newMoney(int64(math.Round(minor)), currency)
+}
+
+// moneyFromMajor converts an amount given in major units (dollars), e.g. from the config
+func moneyFromMajor(major float64, currency string) Money {
+	m := Money{Currency: currency}
+	m.Amount = int64(math.Round(major * math.Pow10(m.exponent())))
+	return m
+}
+
+// currencyMismatchError is returned when amounts in two currencies are added or
+// compared. Amounts come from store responses and saved receipts, so a mismatch is
+// reported like any other bad input instead of crashing the run.
+type currencyMismatchError struct {
+	A, B string
+}
+
+func (e *currencyMismatchError) Error() string {
+	return T("error_money_currency_mismatch", "a", e.A, "b", e.B)
+}
+
+// currencyWith returns the currency of a sum or comparison of two amounts. A zero
+// Money{} counts as zero in any currency.
+func (m Money) currencyWith(other Money) (string, error) {
+	switch {
+	case m.Currency == other.Currency:
+		return m.Currency, nil
+	case m == Money{}:
+		return other.Currency, nil
+	case other == Money{}:
+		return m.Currency, nil
+	}
+	return "", &currencyMismatchError{A: m.Currency, B: other.Currency}
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.currencyWith(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return newMoney(m.Amount+other.Amount, currency), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.currencyWith(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return newMoney(m.Amount-other.Amount, currency), nil
+}
+
+// Mul multiplies by a quantity
+func (m Money) Mul(quantity int) Money {
+	return newMoney(m.Amount*int64(quantity), m.Currency)
+}
+
+// Cmp returns -1, 0 or 1 as m is less than, equal to or greater than other, or an
+// error when the two can't be compared
+func (m Money) Cmp(other Money) (int, error) {
+	if _, err := m.currencyWith(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Equal reports whether both the amount and the currency match
+func (m Money) Equal(other Money) bool {
+	if m.Currency != other.Currency && m != (Money{}) && other != (Money{}) {
+		return false
+	}
+	return m.Amount == other.Amount
+}
+
+// Min returns the smaller amount
+func (m Money) Min(other Money) (Money, error) {
+	cmp, err := m.Cmp(other)
+	if err != nil {
+		return Money{}, err
+	}
+	if cmp <= 0 {
+		return m, nil
+	}
+	return other, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// exponent is the number of decimal places of the currency
+func (m Money) exponent() int {
+	if exp, ok := currencyExponents[m.Currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Major returns the amount in major units (dollars). Only for store APIs that take
+// decimal amounts - never compare or add the result.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(m.exponent())
+}
+
+// String formats the amount with its currency, e.g. "$45.00" or "45.00 CAD"
+func (m Money) String() string {
+	exp := m.exponent()
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	value := fmt.Sprintf("%d", amount)
+	if exp > 0 {
+		unit := int64(math.Pow10(exp))
+		value = fmt.Sprintf("%d.%0*d", amount/unit, exp, amount%unit)
+	}
+
+	if symbol, ok := currencySymbols[m.Currency]; ok {
+		return sign + symbol + value
+	}
+	return strings.TrimSpace(sign + value + " " + m.Currency)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// usd is a test shorthand for an amount in US cents
+func usd(cents int64) Money {
+	return newMoney(cents, "USD")
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	total, err := usd(4500).Mul(2).Add(usd(315))
+	if err == nil {
+		total, err = total.Sub(usd(9000))
+	}
+	if err != nil || !total.Equal(usd(315)) {
+		t.Errorf("Expected $3.15, got %s (%v)", total, err)
+	}
+
+	// 0.1 + 0.2 style sums stay exact
+	sum := Money{}
+	for i := 0; i < 10; i++ {
+		sum, _ = sum.Add(usd(10))
+	}
+	if !sum.Equal(usd(100)) || sum.Currency != "USD" {
+		t.Errorf("Expected $1.00, got %s", sum)
+	}
+
+	if smaller, _ := usd(4500).Min(usd(1500)); smaller != usd(1500) {
+		t.Errorf("Expected $15.00 as the smaller amount, got %s", smaller)
+	}
+	if cmp, _ := usd(1).Cmp(usd(2)); cmp != -1 {
+		t.Error("Comparisons are wrong")
+	}
+	if usd(4500).Equal(newMoney(4500, "EUR")) {
+		t.Error("Amounts in different currencies must not be equal")
+	}
+	if !(Money{}).Equal(usd(0)) {
+		t.Error("Zero Money{} should equal zero in any currency")
+	}
+}
+
+func TestMoneyMixedCurrencies(t *testing.T) {
+	var mismatch *currencyMismatchError
+	if _, err := usd(100).Add(newMoney(100, "EUR")); !errors.As(err, &mismatch) {
+		t.Errorf("Expected a currency mismatch adding USD to EUR, got %v", err)
+	}
+	if _, err := usd(100).Cmp(newMoney(100, "EUR")); !errors.As(err, &mismatch) {
+		t.Errorf("Expected a currency mismatch comparing USD to EUR, got %v", err)
+	}
+	if sum, err := (Money{}).Add(newMoney(100, "EUR")); err != nil || !sum.Equal(newMoney(100, "EUR")) {
+		t.Errorf("Expected Money{} to add to EUR, got %s (%v)", sum, err)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{usd(4500), "$45.00"},
+		{usd(5), "$0.05"},
+		{usd(-1250), "-$12.50"},
+		{newMoney(1999, "EUR"), "€19.99"},
+		{newMoney(1999, "CAD"), "19.99 CAD"},
+		{newMoney(500, "JPY"), "500 JPY"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%+v: expected %q, got %q", tt.money, tt.want, got)
+		}
+	}
+
+	if usd(4599).Major() != 45.99 {
+		t.Errorf("Unexpected major amount %v", usd(4599).Major())
+	}
+}
+
+func TestMoneyFromMajor(t *testing.T) {
+	if got := moneyFromMajor(45.1, "USD"); !got.Equal(usd(4510)) {
+		t.Errorf("Expected $45.10, got %s", got)
+	}
+	if got := moneyFromMajor(1200, "JPY"); got.Amount != 1200 {
+		t.Errorf("Expected 1200 minor units of JPY, got %d", got.Amount)
+	}
+}
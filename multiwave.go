@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"time"
@@ -13,16 +15,40 @@ type MultiWaveOrchestrator struct {
 	timeSync     *TimeSync
 	automation   *Automation
 	fastCheckout *FastCheckout
+	session      *sessionMonitor
+	reporter     Reporter
+	controls     <-chan Control // Keys from the dashboard; nil without one
 	rand         *rand.Rand
 }
 
+var (
+	errWaveSkipped = errors.New("wave skipped")
+	errRunAborted  = errors.New("run aborted")
+)
+
 // NewMultiWaveOrchestrator creates a new multi-wave orchestrator
 func NewMultiWaveOrchestrator(config *Config, automation *Automation, fastCheckout *FastCheckout) *MultiWaveOrchestrator {
+	timeSync := NewTimeSync(config.DebugMode)
+	if fastCheckout != nil {
+		timeSync.UseClient(fastCheckout.newClient(5*time.Second, true))
+	}
+
+	reporter := NewReporter(config, "orchestrator")
+	timeSync.UseReporter(NewReporter(config, "timesync"))
+
+	var controls <-chan Control
+	if source, ok := reporter.(ControlSource); ok {
+		controls = source.Controls()
+	}
+
 	return &MultiWaveOrchestrator{
 		config:       config,
-		timeSync:     NewTimeSync(config.DebugMode),
+		timeSync:     timeSync,
 		automation:   automation,
 		fastCheckout: fastCheckout,
+		session:      newSessionMonitor(config, fastCheckout, automation),
+		reporter:     reporter,
+		controls:     controls,
 		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
@@ -30,20 +56,11 @@ func NewMultiWaveOrchestrator(config *Config, automation *Automation, fastChecko
 // Run executes the multi-wave sale workflow
 func (mwo *MultiWaveOrchestrator) Run() error {
 	// Step 1: Synchronize time with reliable time servers
-	fmt.Println(T("multiwave_syncing_time"))
+	mwo.reporter.Info(T("multiwave_syncing_time"))
 	if err := mwo.timeSync.Sync(); err != nil {
 		return fmt.Errorf("failed to sync time: %w", err)
 	}
 
-	offset := mwo.timeSync.GetOffset()
-	if offset > 0 {
-		fmt.Printf(T("multiwave_time_synced_ahead")+"\n", offset)
-	} else if offset < 0 {
-		fmt.Printf(T("multiwave_time_synced_behind")+"\n", -offset)
-	} else {
-		fmt.Println(T("multiwave_time_synced_perfect"))
-	}
-
 	// Step 2: Parse and validate all sale windows
 	saleWindows, err := mwo.parseSaleWindows()
 	if err != nil {
@@ -54,18 +71,24 @@ func (mwo *MultiWaveOrchestrator) Run() error {
 		return fmt.Errorf("no sale windows configured")
 	}
 
-	fmt.Printf(T("multiwave_configured_waves")+"\n", len(saleWindows))
-	fmt.Println()
+	mwo.reporter.Info(T("multiwave_configured_waves", "count", len(saleWindows)))
+	mwo.reporter.Info("")
 
 	// Display all waves
+	now := mwo.timeSync.Now()
+	preWaveDuration := time.Duration(mwo.config.PreWaveActivationMinutes) * time.Minute
 	for i, waveTime := range saleWindows {
-		localTime := waveTime.Local()
-		fmt.Printf(T("multiwave_wave_time")+"\n", i+1, waveTime.Format(time.RFC3339), localTime.Format("15:04:05 MST"))
+		mwo.reporter.WaveScheduled(WaveScheduled{
+			Wave:       i + 1,
+			Total:      len(saleWindows),
+			Start:      waveTime,
+			Activation: waveTime.Add(-preWaveDuration),
+			Until:      waveTime.Sub(now),
+		})
 	}
-	fmt.Println()
+	mwo.reporter.Info("")
 
 	// Step 3: Determine which wave to start from based on current time
-	now := mwo.timeSync.Now()
 	postWaveDuration := time.Duration(mwo.config.PostWaveTimeoutMinutes) * time.Minute
 
 	startWaveIndex := -1
@@ -80,68 +103,100 @@ func (mwo *MultiWaveOrchestrator) Run() error {
 	}
 
 	// Check if all waves have passed
+	for i := 0; i < len(saleWindows) && (startWaveIndex == -1 || i < startWaveIndex); i++ {
+		mwo.reporter.WaveStateChanged(WaveStateChanged{Wave: i + 1, State: WaveEnded})
+	}
+
 	if startWaveIndex == -1 {
-		fmt.Println()
-		fmt.Println(T("multiwave_all_waves_passed"))
-		fmt.Printf(T("multiwave_last_wave_was")+"\n", len(saleWindows), saleWindows[len(saleWindows)-1].Local().Format("15:04:05 MST"))
-		fmt.Println(T("multiwave_exiting_no_waves"))
+		mwo.reporter.Info("")
+		mwo.reporter.Warn(T("multiwave_all_waves_passed"))
+		mwo.reporter.Info(T("multiwave_last_wave_was", "number", len(saleWindows), "local_time", saleWindows[len(saleWindows)-1]))
+		mwo.reporter.Info(T("multiwave_exiting_no_waves"))
 		return fmt.Errorf("all sale waves have ended")
 	}
 
 	// Inform user if we're skipping past waves
 	if startWaveIndex > 0 {
-		fmt.Println()
-		fmt.Printf(T("multiwave_skipping_past_waves")+"\n", startWaveIndex)
+		mwo.reporter.Info("")
+		mwo.reporter.Info(T("multiwave_skipping_past_waves", "count", startWaveIndex))
 		for i := 0; i < startWaveIndex; i++ {
-			fmt.Printf("   • Wave %d (%s) - Ended\n", i+1, saleWindows[i].Local().Format("15:04:05 MST"))
+			mwo.reporter.Info(T("multiwave_wave_ended", "number", i+1, "time", saleWindows[i]))
 		}
-		fmt.Println()
+		mwo.reporter.Info("")
 	}
 
 	// Step 4: Process waves starting from the first relevant wave
+	reporter, checkoutReporter := mwo.reporter, mwo.fastCheckout.reporter
+	defer func() { mwo.reporter, mwo.fastCheckout.reporter = reporter, checkoutReporter }()
+
 	for i := startWaveIndex; i < len(saleWindows); i++ {
 		waveNum := i + 1
 		waveTime := saleWindows[i]
 
-		fmt.Printf(T("multiwave_wave_header")+"\n", waveNum, len(saleWindows))
-		fmt.Println()
+		// Tag the wave's log file entries with its number
+		mwo.reporter = reporter.With("wave", waveNum)
+		mwo.fastCheckout.reporter = checkoutReporter.With("wave", waveNum)
+
+		mwo.reporter.Info(T("multiwave_wave_header", "number", waveNum, "total", len(saleWindows)))
+		mwo.reporter.Info("")
 
 		success, err := mwo.processWave(waveNum, waveTime)
-		if err != nil {
+		if err == nil && !success {
+			// Keys pressed during checkout take effect once the attempt is over
+			if controlErr := mwo.checkControls(); controlErr == errRunAborted {
+				err = controlErr
+			}
+		}
+
+		switch {
+		case err == errWaveSkipped:
+			mwo.reporter.WaveStateChanged(WaveStateChanged{Wave: waveNum, State: WaveSkipped})
+			mwo.reporter.Info("")
+			mwo.reporter.Warn(T("multiwave_wave_skipped", "number", waveNum))
+			mwo.reporter.Info("")
+			continue
+		case err == errRunAborted:
+			mwo.reporter.Info("")
+			mwo.reporter.Warn(T("multiwave_run_aborted"))
+			return err
+		case err != nil:
+			mwo.reporter.WaveStateChanged(WaveStateChanged{Wave: waveNum, State: WaveFailed})
 			return fmt.Errorf("wave %d failed: %w", waveNum, err)
 		}
 
 		if success {
-			fmt.Println()
-			fmt.Println(T("multiwave_purchase_success"))
-			fmt.Println(T("multiwave_exiting_gracefully"))
+			mwo.reporter.WaveStateChanged(WaveStateChanged{Wave: waveNum, State: WaveSucceeded})
+			mwo.reporter.Info("")
+			mwo.reporter.Info(T("multiwave_purchase_success"))
+			mwo.reporter.Info(T("multiwave_exiting_gracefully"))
 			return nil
 		}
 
 		// Wave failed - check if there's a next wave
+		mwo.reporter.WaveStateChanged(WaveStateChanged{Wave: waveNum, State: WaveFailed})
 		if waveNum < len(saleWindows) {
 			nextWaveTime := saleWindows[i+1]
 			now := mwo.timeSync.Now()
 			waitDuration := nextWaveTime.Sub(now)
 
-			fmt.Println()
-			fmt.Printf(T("multiwave_wave_failed")+"\n", waveNum)
-			fmt.Printf(T("multiwave_moving_to_next")+"\n", waveNum+1)
-			fmt.Printf(T("multiwave_next_wave_in")+"\n", waitDuration.Round(time.Second))
-			fmt.Printf(T("multiwave_staying_dormant")+"\n")
-			fmt.Println()
+			mwo.reporter.Info("")
+			mwo.reporter.Error(T("multiwave_wave_failed", "number", waveNum))
+			mwo.reporter.Info(T("multiwave_moving_to_next", "number", waveNum+1))
+			mwo.reporter.Info(T("multiwave_next_wave_in", "wait", waitDuration.Round(time.Second)))
+			mwo.reporter.Info(T("multiwave_staying_dormant"))
+			mwo.reporter.Info("")
 		} else {
 			// This was the last wave
-			fmt.Println()
-			fmt.Printf(T("multiwave_wave_failed")+"\n", waveNum)
-			fmt.Println(T("multiwave_was_last_wave"))
-			fmt.Println()
+			mwo.reporter.Info("")
+			mwo.reporter.Error(T("multiwave_wave_failed", "number", waveNum))
+			mwo.reporter.Info(T("multiwave_was_last_wave"))
+			mwo.reporter.Info("")
 		}
 	}
 
 	// All waves completed without success
-	fmt.Println()
-	fmt.Println(T("multiwave_all_waves_failed"))
+	mwo.reporter.Info("")
+	mwo.reporter.Error(T("multiwave_all_waves_failed"))
 	return fmt.Errorf("checkout failed for all %d waves", len(saleWindows))
 }
 
@@ -171,34 +226,43 @@ func (mwo *MultiWaveOrchestrator) processWave(waveNum int, waveTime time.Time) (
 	now := mwo.timeSync.Now()
 	if now.Before(activationTime) {
 		waitDuration := activationTime.Sub(now)
-		fmt.Printf(T("multiwave_waiting_for_activation")+"\n", waitDuration.Round(time.Second))
-		fmt.Printf(T("multiwave_activation_time")+"\n", activationTime.Local().Format("15:04:05 MST"))
-		fmt.Println()
+		mwo.reporter.Info(T("multiwave_waiting_for_activation", "wait", waitDuration.Round(time.Second)))
+		mwo.reporter.Info(T("multiwave_activation_time", "time", activationTime))
+		mwo.reporter.Info("")
 
 		// Sleep until activation, checking periodically for time sync
-		mwo.sleepUntilWithUpdates(activationTime)
+		if err := mwo.sleepUntilWithUpdates(activationTime); err != nil {
+			return false, err
+		}
+	}
+
+	// Re-open the store connection: anything pooled before a long dormant period has
+	// been dropped by the server. Polling below then keeps this connection hot until T0.
+	if err := mwo.fastCheckout.WarmUp(); err != nil {
+		mwo.reporter.Warn(T("connection_warmup_failed", "error", err))
 	}
 
 	// Start pre-wave polling
-	fmt.Println(T("multiwave_prewave_polling_start"))
-	fmt.Printf(T("multiwave_polling_url")+"\n", mwo.config.ItemURL)
-	fmt.Println()
+	mwo.reporter.WaveStateChanged(WaveStateChanged{Wave: waveNum, State: WavePolling})
+	mwo.reporter.Info(T("multiwave_prewave_polling_start"))
+	mwo.reporter.Info(T("multiwave_polling_url", "url", mwo.config.ItemURL))
+	mwo.reporter.Info("")
 
 	pageAvailableTime, err := mwo.pollForProductPage(waveTime)
 	if err != nil {
 		return false, err
 	}
 
-	fmt.Println()
-	fmt.Println(T("multiwave_product_page_available"))
+	mwo.reporter.Info("")
+	mwo.reporter.Info(T("multiwave_product_page_available"))
 	if pageAvailableTime.Before(waveTime) {
 		earlyBy := waveTime.Sub(pageAvailableTime)
-		fmt.Printf(T("multiwave_page_available_early")+"\n", earlyBy.Round(time.Second))
+		mwo.reporter.Info(T("multiwave_page_available_early", "early_by", earlyBy.Round(time.Second)))
 	}
-	fmt.Println()
+	mwo.reporter.Info("")
 
 	// Navigate to product page (it's now available)
-	fmt.Println(T("multiwave_navigating_to_product"))
+	mwo.reporter.Info(T("multiwave_navigating_to_product"))
 	if err := mwo.automation.page.Navigate(mwo.config.ItemURL); err != nil {
 		return false, fmt.Errorf("failed to navigate to product page: %w", err)
 	}
@@ -207,7 +271,7 @@ func (mwo *MultiWaveOrchestrator) processWave(waveNum int, waveTime time.Time) (
 	}
 
 	// Extract and cache SKU
-	fmt.Println(T("multiwave_extracting_sku"))
+	mwo.reporter.Info(T("multiwave_extracting_sku"))
 	if err := mwo.automation.extractAndCacheSKU(); err != nil {
 		return false, fmt.Errorf("failed to extract SKU: %w", err)
 	}
@@ -216,10 +280,11 @@ func (mwo *MultiWaveOrchestrator) processWave(waveNum int, waveTime time.Time) (
 	postWaveDuration := time.Duration(mwo.config.PostWaveTimeoutMinutes) * time.Minute
 	timeoutTime := waveTime.Add(postWaveDuration)
 
-	fmt.Println()
-	fmt.Println(T("multiwave_attempting_checkout"))
-	fmt.Printf(T("multiwave_timeout_at")+"\n", timeoutTime.Local().Format("15:04:05 MST"))
-	fmt.Println()
+	mwo.reporter.WaveStateChanged(WaveStateChanged{Wave: waveNum, State: WaveCheckout})
+	mwo.reporter.Info("")
+	mwo.reporter.Info(T("multiwave_attempting_checkout"))
+	mwo.reporter.Info(T("multiwave_timeout_at", "time", timeoutTime))
+	mwo.reporter.Info("")
 
 	// Attempt checkout with timeout
 	success := mwo.attemptCheckoutWithTimeout(timeoutTime)
@@ -228,18 +293,25 @@ func (mwo *MultiWaveOrchestrator) processWave(waveNum int, waveTime time.Time) (
 }
 
 // pollForProductPage polls the product URL until it returns 200 (not 404)
+// Polls share the store transport, so they double as keep-alive for the GraphQL connection
 func (mwo *MultiWaveOrchestrator) pollForProductPage(waveTime time.Time) (time.Time, error) {
+	// Don't follow redirects. Without a FastCheckout there is no shared transport to
+	// keep warm, so the poll uses a connection of its own.
 	client := &http.Client{
-		Timeout: 5 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse // Don't follow redirects
-		},
+		Timeout:       5 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	if mwo.fastCheckout != nil {
+		client = mwo.fastCheckout.newClient(5*time.Second, false)
 	}
 
 	attemptNum := 0
-	lastProgressUpdate := time.Now()
 
 	for {
+		if err := mwo.checkControls(); err != nil {
+			return time.Time{}, err
+		}
+
 		attemptNum++
 		now := mwo.timeSync.Now()
 
@@ -258,24 +330,17 @@ func (mwo *MultiWaveOrchestrator) pollForProductPage(waveTime time.Time) (time.T
 
 		resp, err := client.Do(req)
 		if err == nil {
-			defer resp.Body.Close()
+			// Drain and close right away (not deferred) so the connection returns to the
+			// shared pool instead of being dropped
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
 
 			if resp.StatusCode == 200 {
 				// Success! Page is available
 				return mwo.timeSync.Now(), nil
 			}
 
-			// Show progress every 10 seconds
-			if time.Since(lastProgressUpdate) >= 10*time.Second {
-				timeUntilWave := waveTime.Sub(now)
-				if timeUntilWave > 0 {
-					fmt.Printf(T("multiwave_polling_progress_before")+"\n", resp.StatusCode, timeUntilWave.Round(time.Second))
-				} else {
-					timeSinceWave := now.Sub(waveTime)
-					fmt.Printf(T("multiwave_polling_progress_after")+"\n", resp.StatusCode, timeSinceWave.Round(time.Second))
-				}
-				lastProgressUpdate = time.Now()
-			}
+			mwo.reporter.PollProgress(PollProgress{Attempt: attemptNum, Status: resp.StatusCode, Until: waveTime.Sub(now)})
 		}
 
 		// Sleep before next attempt (variable millisecond delay for human-like timing)
@@ -297,12 +362,12 @@ func (mwo *MultiWaveOrchestrator) attemptCheckoutWithTimeout(timeoutTime time.Ti
 	}
 
 	// Checkout failed
-	fmt.Printf(T("multiwave_checkout_failed")+"\n", checkoutErr)
+	mwo.reporter.Error(T("multiwave_checkout_failed", "error", checkoutErr))
 
 	// Check if we should retry or if we've timed out
 	now := mwo.timeSync.Now()
 	if now.After(timeoutTime) {
-		fmt.Println(T("multiwave_wave_timeout"))
+		mwo.reporter.Info(T("multiwave_wave_timeout"))
 		return false
 	}
 
@@ -313,41 +378,94 @@ func (mwo *MultiWaveOrchestrator) attemptCheckoutWithTimeout(timeoutTime time.Ti
 }
 
 // sleepUntilWithUpdates sleeps until target time, with periodic progress updates
-func (mwo *MultiWaveOrchestrator) sleepUntilWithUpdates(targetTime time.Time) {
+// and session health checks. Dashboard keys can skip the wave or abort the run.
+func (mwo *MultiWaveOrchestrator) sleepUntilWithUpdates(targetTime time.Time) error {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
+	// The session has to survive until the wave's checkout window closes
+	waveEnd := targetTime.Add(time.Duration(mwo.config.PreWaveActivationMinutes+mwo.config.PostWaveTimeoutMinutes) * time.Minute)
+
 	for {
 		now := mwo.timeSync.Now()
 		remaining := targetTime.Sub(now)
 
 		if remaining <= 0 {
-			return
+			return nil
 		}
 
 		// If less than 30 seconds remaining, just sleep the remainder
 		if remaining < 30*time.Second {
-			time.Sleep(remaining)
-			return
+			timer := time.NewTimer(remaining)
+			select {
+			case <-timer.C:
+				return nil
+			case control := <-mwo.controls:
+				timer.Stop()
+				if err := mwo.handleControl(control); err != nil {
+					return err
+				}
+			}
+			continue
 		}
 
 		// Wait for next tick or timeout
 		select {
+		case control := <-mwo.controls:
+			if err := mwo.handleControl(control); err != nil {
+				return err
+			}
+
 		case <-ticker.C:
 			// Resync time if needed (every hour)
 			if mwo.timeSync.ShouldResync() {
-				fmt.Println(T("multiwave_resyncing_time"))
-				if err := mwo.timeSync.Sync(); err != nil {
-					fmt.Printf(T("multiwave_resync_failed")+"\n", err)
-				}
+				mwo.reporter.Info(T("multiwave_resyncing_time"))
+				mwo.resync()
+			}
+
+			// Probe the session well ahead of activation
+			if now = mwo.timeSync.Now(); mwo.session.due(now) {
+				mwo.session.check(now, targetTime, waveEnd)
 			}
 
 			// Show progress
 			now = mwo.timeSync.Now()
 			remaining = targetTime.Sub(now)
 			if remaining > 0 {
-				fmt.Printf(T("multiwave_waiting_update")+"\n", remaining.Round(time.Second))
+				mwo.reporter.Info(T("multiwave_waiting_update", "remaining", remaining.Round(time.Second)))
 			}
 		}
 	}
 }
+
+// resync synchronizes the clock again, keeping the last offset on failure
+func (mwo *MultiWaveOrchestrator) resync() {
+	if err := mwo.timeSync.Sync(); err != nil {
+		mwo.reporter.Warn(T("multiwave_resync_failed", "error", err))
+	}
+}
+
+// checkControls handles a pending dashboard key without waiting for one
+func (mwo *MultiWaveOrchestrator) checkControls() error {
+	select {
+	case control := <-mwo.controls:
+		return mwo.handleControl(control)
+	default:
+		return nil
+	}
+}
+
+// handleControl resyncs in place; skip and abort are returned as errWaveSkipped and
+// errRunAborted for Run to act on
+func (mwo *MultiWaveOrchestrator) handleControl(control Control) error {
+	switch control {
+	case ControlSkipWave:
+		return errWaveSkipped
+	case ControlAbort:
+		return errRunAborted
+	case ControlResync:
+		mwo.reporter.Info(T("multiwave_resyncing_time_requested"))
+		mwo.resync()
+	}
+	return nil
+}
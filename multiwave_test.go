@@ -1,6 +1,9 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -342,3 +345,25 @@ func TestMultipleWaveFormats(t *testing.T) {
 		}
 	}
 }
+
+// TestPollForProductPageWithoutFastCheckout polls until the page goes live, on a plain
+// client when the orchestrator has no FastCheckout
+func TestPollForProductPageWithoutFastCheckout(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{ItemURL: server.URL, PostWaveTimeoutMinutes: 1, PollingDelayMinMs: 1, PollingDelayMaxMs: 1}
+	mwo := NewMultiWaveOrchestrator(config, nil, nil)
+
+	if _, err := mwo.pollForProductPage(time.Now()); err != nil {
+		t.Fatalf("pollForProductPage failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&polls); got != 3 {
+		t.Errorf("Expected 3 polls, got %d", got)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// orderExpectation is what the run meant to buy
+type orderExpectation struct {
+	SKUID    string `json:"sku_id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"qty"`
+	Price    Money  `json:"price"`  // Item price before credit
+	Tax      Money  `json:"tax"`    // Tax charged on top of the price (zero when included)
+	Credit   Money  `json:"credit"` // Store credit applied
+}
+
+// total is the amount the order should charge after credit
+func (e orderExpectation) total() (Money, error) {
+	withTax, err := e.Price.Mul(e.Quantity).Add(e.Tax)
+	if err != nil {
+		return Money{}, err
+	}
+	return withTax.Sub(e.Credit)
+}
+
+// orderLine is one line item of a placed order
+type orderLine struct {
+	SKUID     string `json:"sku_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"qty"`
+	UnitPrice Money  `json:"unit_price"`
+}
+
+// orderDetails is the order as the store reports it after checkout
+type orderDetails struct {
+	Slug       string      `json:"slug"`
+	Status     string      `json:"status"`
+	Total      Money       `json:"total"`       // Amount charged after credit
+	CreditUsed Money       `json:"credit_used"` // Store credit spent on the order
+	Lines      []orderLine `json:"lines"`
+}
+
+// orderReceipt is written to ~/.specter/receipts after every completed purchase
+type orderReceipt struct {
+	OrderSlug string           `json:"order_slug"`
+	PlacedAt  time.Time        `json:"placed_at"`
+	Verified  bool             `json:"verified"`
+	Problems  []string         `json:"problems,omitempty"`
+	Expected  orderExpectation `json:"expected"`
+	Order     *orderDetails    `json:"order,omitempty"`
+	Steps     []timingStep     `json:"steps"`
+	TotalTime time.Duration    `json:"total_time_ns"`
+}
+
+// receiptsDir is where receipts are kept (~/.specter/receipts)
+func receiptsDir() string {
+	return filepath.Join(getUserDataDir(), "receipts")
+}
+
+// verifyOrder compares the placed order with what the run meant to buy and returns
+// every mismatch found (nil when the order is exactly as expected)
+func verifyOrder(order *orderDetails, expected orderExpectation) []string {
+	var problems []string
+
+	var target *orderLine
+	for i := range order.Lines {
+		if order.Lines[i].SKUID == expected.SKUID {
+			target = &order.Lines[i]
+		} else {
+			problems = append(problems, T("order_problem_unexpected_item", "name", order.Lines[i].Name, "quantity", order.Lines[i].Quantity))
+		}
+	}
+
+	if target == nil {
+		problems = append(problems, T("order_problem_missing_item", "name", expected.Name, "sku_id", expected.SKUID))
+	} else {
+		if target.Quantity != expected.Quantity {
+			problems = append(problems, T("order_problem_quantity", "quantity", target.Quantity, "expected", expected.Quantity))
+		}
+		if !target.UnitPrice.Equal(expected.Price) {
+			problems = append(problems, T("order_problem_price", "price", target.UnitPrice, "expected", expected.Price))
+		}
+	}
+
+	if !order.CreditUsed.Equal(expected.Credit) {
+		problems = append(problems, T("order_problem_credit", "credit", order.CreditUsed, "expected", expected.Credit))
+	}
+	if expectedTotal, err := expected.total(); err != nil {
+		problems = append(problems, err.Error())
+	} else if !order.Total.Equal(expectedTotal) {
+		problems = append(problems, T("order_problem_total", "total", order.Total, "expected", expectedTotal))
+	}
+
+	return problems
+}
+
+// GetOrder fetches a placed order by slug
+func (f *FastCheckout) GetOrder(slug string) (*orderDetails, error) {
+	query := operation("OrderQuery")
+
+	request := []GraphQLRequest{
+		{
+			OperationName: "OrderQuery",
+			Variables: map[string]interface{}{
+				"slug":       slug,
+				"storeFront": "pledge",
+			},
+			Query: query,
+		},
+	}
+
+	resp, err := f.graphqlRequestWithLoginRetry(request)
+	if err != nil {
+		return nil, TError("error_failed_query_order", "error", err)
+	}
+
+	details, err := decodeOrder(resp, f.currency())
+	if err != nil {
+		return nil, TError("error_failed_parse_order", "error", err)
+	}
+	if details == nil {
+		return nil, TError("error_order_not_found", "slug", slug)
+	}
+
+	return details, nil
+}
+
+// recordOrder remembers a completed checkout so it can be verified and receipted
+// once the run is over
+func (f *FastCheckout) recordOrder(expected orderExpectation) {
+	f.receipt = &orderReceipt{
+		OrderSlug: f.lastOrderSlug,
+		PlacedAt:  time.Now(),
+		Expected:  expected,
+	}
+}
+
+// verifyPlacedOrder fetches the order by slug and checks it against the expectation
+func (f *FastCheckout) verifyPlacedOrder() {
+	receipt := f.receipt
+
+	if receipt.OrderSlug == "" {
+		receipt.Problems = append(receipt.Problems, T("order_problem_no_slug"))
+		f.reporter.Warn(T("order_verify_no_slug"))
+		return
+	}
+
+	f.reporter.Info(T("order_verifying", "slug", receipt.OrderSlug))
+	order, err := f.GetOrder(receipt.OrderSlug)
+	if err != nil {
+		receipt.Problems = append(receipt.Problems, err.Error())
+		f.reporter.Warn(T("order_verify_failed", "error", err))
+		return
+	}
+
+	receipt.Order = order
+	receipt.Problems = verifyOrder(order, receipt.Expected)
+	receipt.Verified = len(receipt.Problems) == 0
+
+	if receipt.Verified {
+		f.reporter.Info(T("order_verified", "slug", order.Slug, "name", receipt.Expected.Name, "credit", order.CreditUsed))
+		return
+	}
+
+	f.reporter.Warn(T("order_verify_mismatch"))
+	for _, problem := range receipt.Problems {
+		f.reporter.Info(fmt.Sprintf("   • %s", problem))
+	}
+}
+
+// write saves the receipt as JSON and Markdown and returns the JSON path
+func (r *orderReceipt) write(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create receipts directory: %w", err)
+	}
+
+	name := "order-" + r.PlacedAt.Format("20060102-150405")
+	if r.OrderSlug != "" {
+		name += "-" + r.OrderSlug
+	}
+	base := filepath.Join(dir, name)
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal receipt: %w", err)
+	}
+	if err := os.WriteFile(base+".json", data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(base+".md", []byte(r.markdown()), 0644); err != nil {
+		return "", err
+	}
+
+	return base + ".json", nil
+}
+
+// markdown renders the receipt for people
+func (r *orderReceipt) markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", T("receipt_md_title", "slug", r.OrderSlug))
+	fmt.Fprintf(&b, "- %s: %s\n", T("receipt_md_placed"), r.PlacedAt.Local().Format("2006-01-02 15:04:05 MST"))
+	if r.Verified {
+		fmt.Fprintf(&b, "- %s: ✓\n", T("receipt_md_verified"))
+	} else {
+		fmt.Fprintf(&b, "- %s: ✗\n", T("receipt_md_verified"))
+	}
+	fmt.Fprintf(&b, "- %s: %s (SKU %s) × %d\n", T("receipt_md_item"), r.Expected.Name, r.Expected.SKUID, r.Expected.Quantity)
+	fmt.Fprintf(&b, "- %s: %s\n", T("receipt_md_price"), r.Expected.Price)
+	if r.Expected.Tax.IsPositive() {
+		fmt.Fprintf(&b, "- %s: %s\n", T("receipt_md_tax"), r.Expected.Tax)
+	}
+	fmt.Fprintf(&b, "- %s: %s\n", T("receipt_md_credit"), r.Expected.Credit)
+	if r.Order != nil {
+		fmt.Fprintf(&b, "- %s: %s (%s)\n", T("receipt_md_charged"), r.Order.Total, r.Order.Status)
+	}
+
+	if len(r.Problems) > 0 {
+		fmt.Fprintf(&b, "\n## %s\n\n", T("receipt_md_problems"))
+		for _, problem := range r.Problems {
+			fmt.Fprintf(&b, "- %s\n", problem)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## %s\n\n```\n%s\n```\n", T("receipt_md_timings"), formatTimingTable(r.Steps))
+	fmt.Fprintf(&b, "\n%s: %v\n", T("receipt_md_total_time"), r.TotalTime.Round(time.Millisecond))
+
+	return b.String()
+}
+
+// finishReceipt attaches the run's step timings and writes the receipt to disk
+func (f *FastCheckout) finishReceipt(runTime time.Duration) {
+	receipt := f.receipt
+	f.receipt = nil
+
+	receipt.Steps = summarizeTimings(f.latency.snapshot())
+	receipt.TotalTime = runTime
+
+	path, err := receipt.write(receiptsDir())
+	if err != nil {
+		f.reporter.Warn(T("receipt_write_failed", "error", err))
+		return
+	}
+	f.reporter.Info(T("receipt_written", "path", path))
+}
+
+// loadReceipts reads the receipts saved in dir, oldest first
+func loadReceipts(dir string) ([]*orderReceipt, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "order-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var receipts []*orderReceipt
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		receipt := &orderReceipt{}
+		if err := json.Unmarshal(data, receipt); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyOrder(t *testing.T) {
+	expected := orderExpectation{SKUID: "222", Name: "Target Ship", Quantity: 1, Price: usd(4500), Credit: usd(4500)}
+
+	order := &orderDetails{
+		Total:      usd(0),
+		CreditUsed: usd(4500),
+		Lines:      []orderLine{{SKUID: "222", Name: "Target Ship", Quantity: 1, UnitPrice: usd(4500)}},
+	}
+	if problems := verifyOrder(order, expected); len(problems) != 0 {
+		t.Errorf("Expected matching order to verify, got %v", problems)
+	}
+
+	// Wrong quantity, an extra item and less credit than planned
+	order = &orderDetails{
+		Total:      usd(5500),
+		CreditUsed: usd(3500),
+		Lines: []orderLine{
+			{SKUID: "222", Name: "Target Ship", Quantity: 2, UnitPrice: usd(4500)},
+			{SKUID: "111", Name: "Other Ship", Quantity: 1, UnitPrice: usd(0)},
+		},
+	}
+	if problems := verifyOrder(order, expected); len(problems) != 4 {
+		t.Errorf("Expected 4 problems (extra item, quantity, credit, total), got %v", problems)
+	}
+
+	order = &orderDetails{Total: usd(4500), CreditUsed: usd(4500)}
+	if problems := verifyOrder(order, expected); len(problems) != 2 {
+		t.Errorf("Expected missing item and total problems, got %v", problems)
+	}
+
+	// Sales tax added on top of the price is part of the expected charge
+	expected = orderExpectation{SKUID: "222", Name: "Target Ship", Quantity: 1, Price: usd(4500), Tax: usd(315), Credit: usd(4500)}
+	order = &orderDetails{
+		Total:      usd(315),
+		CreditUsed: usd(4500),
+		Lines:      []orderLine{{SKUID: "222", Name: "Target Ship", Quantity: 1, UnitPrice: usd(4500)}},
+	}
+	if problems := verifyOrder(order, expected); len(problems) != 0 {
+		t.Errorf("Expected taxed order to verify, got %v", problems)
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []GraphQLRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+		if requests[0].OperationName != "OrderQuery" || requests[0].Variables["slug"] != "ABC123" {
+			w.Write([]byte(`[{"data":{"store":{"order":null}}}]`))
+			return
+		}
+		w.Write([]byte(`[{"data":{"store":{"order":{"slug":"ABC123","status":"completed","totals":{"total":0,"credits":{"amount":4500}},"lineItems":[{"skuId":222,"sku":{"title":"Target Ship"},"unitPriceWithTax":{"amount":4500},"qty":1}]}}}}]`))
+	}))
+	defer server.Close()
+
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.graphqlURL = server.URL
+
+	order, err := fc.GetOrder("ABC123")
+	if err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+	if !order.CreditUsed.Equal(usd(4500)) || len(order.Lines) != 1 || order.Lines[0].SKUID != "222" || !order.Lines[0].UnitPrice.Equal(usd(4500)) {
+		t.Errorf("Unexpected order: %+v", order)
+	}
+
+	if _, err := fc.GetOrder("MISSING"); err == nil {
+		t.Error("Expected error for unknown order")
+	}
+}
+
+func TestOrderReceiptWrite(t *testing.T) {
+	dir := t.TempDir()
+	receipt := &orderReceipt{
+		OrderSlug: "ABC123",
+		PlacedAt:  time.Date(2025, 1, 15, 15, 58, 0, 0, time.UTC),
+		Problems:  []string{"quantity is 2, expected 1"},
+		Expected:  orderExpectation{SKUID: "222", Name: "Target Ship", Quantity: 1, Price: usd(4500), Credit: usd(4500)},
+		Steps:     []timingStep{{Operation: "ValidateCart", Calls: 1, Total: 120 * time.Millisecond}},
+		TotalTime: 2 * time.Second,
+	}
+
+	path, err := receipt.write(dir)
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if filepath.Base(path) != "order-20250115-155800-ABC123.json" {
+		t.Errorf("Unexpected receipt name: %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read receipt: %v", err)
+	}
+	var loaded orderReceipt
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Receipt is not valid JSON: %v", err)
+	}
+	if loaded.OrderSlug != "ABC123" || len(loaded.Steps) != 1 || loaded.Steps[0].Operation != "ValidateCart" {
+		t.Errorf("Unexpected receipt contents: %+v", loaded)
+	}
+
+	md, err := os.ReadFile(strings.TrimSuffix(path, ".json") + ".md")
+	if err != nil {
+		t.Fatalf("Failed to read Markdown receipt: %v", err)
+	}
+	if !strings.Contains(string(md), "Target Ship") || !strings.Contains(string(md), "quantity is 2, expected 1") {
+		t.Errorf("Markdown receipt is missing details:\n%s", md)
+	}
+
+	// "specter orders" lists the JSON receipts, not the Markdown copies
+	receipts, err := loadReceipts(dir)
+	if err != nil || len(receipts) != 1 {
+		t.Fatalf("Unexpected receipts: %+v (%v)", receipts, err)
+	}
+	if total, err := receipts[0].Expected.total(); err != nil || total != usd(0) {
+		t.Errorf("Unexpected receipts: %+v (%v)", receipts, err)
+	}
+}
+
+func TestVerifyPlacedOrderWithoutSlug(t *testing.T) {
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+
+	fc.recordOrder(orderExpectation{SKUID: "222", Quantity: 1})
+	fc.verifyPlacedOrder()
+
+	if fc.receipt.Verified || len(fc.receipt.Problems) != 1 {
+		t.Errorf("Order without slug should be recorded as unverified, got %+v", fc.receipt)
+	}
+}
+
+func TestRunFastCheckoutClearsOrderSlug(t *testing.T) {
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+
+	// Left over from an earlier wave; this run fails before validating the cart
+	fc.lastOrderSlug = "ORD-EARLIER"
+	if err := fc.RunFastCheckout(nil); err == nil {
+		t.Fatal("Expected the run to fail without a browser")
+	}
+	if fc.lastOrderSlug != "" {
+		t.Errorf("Expected the slug to be cleared, got %q", fc.lastOrderSlug)
+	}
+}
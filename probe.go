@@ -0,0 +1,393 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// probeOperation is one read-only store query run by "specter probe"
+type probeOperation struct {
+	Name      string
+	Variables map[string]interface{}
+	Decode    func(resp string) error // The decoder checkout relies on for this query
+}
+
+// responseShape maps every JSON path of a response to its kind
+// ("object", "array", "string", "number", "bool" or "null"); array elements share "[]"
+type responseShape map[string]string
+
+// probeBaseline is the response shapes of a run that worked, stored in ~/.specter
+type probeBaseline struct {
+	RecordedAt time.Time                `json:"recorded_at"`
+	Operations map[string]responseShape `json:"operations"`
+	FlowSteps  []string                 `json:"flow_steps"`
+}
+
+// shapeDrift is one difference between a baseline shape and the live response
+type shapeDrift struct {
+	Path string
+	Was  string // Kind in the baseline, "" for a new field
+	Now  string // Kind in the response, "" for a missing field
+}
+
+func (d shapeDrift) String() string {
+	switch {
+	case d.Now == "":
+		return T("probe_drift_missing", "path", d.Path, "was", d.Was)
+	case d.Was == "":
+		return T("probe_drift_added", "path", d.Path, "now", d.Now)
+	default:
+		return T("probe_drift_changed", "path", d.Path, "was", d.Was, "now", d.Now)
+	}
+}
+
+// probeBaselinePath is where the probe baseline is kept (~/.specter/probe-baseline.json)
+func probeBaselinePath() string {
+	return filepath.Join(getUserDataDir(), "probe-baseline.json")
+}
+
+// probeOperations lists the queries to probe; the listing queries need a product slug
+func probeOperations(slug, currency string) []probeOperation {
+	storeFront := map[string]interface{}{"storeFront": "pledge"}
+
+	ops := []probeOperation{
+		{Name: "CombinedCartQuery", Variables: storeFront, Decode: func(resp string) error {
+			_, err := decodeCartInfo(resp, currency)
+			return err
+		}},
+		{Name: "CartSummaryViewQuery", Variables: storeFront, Decode: func(resp string) error {
+			_, _, err := decodeCartTotals(resp, currency)
+			return err
+		}},
+		{Name: "StepperQuery", Variables: storeFront, Decode: func(resp string) error {
+			_, err := decodeCartItems(resp, currency)
+			return err
+		}},
+		{Name: "AddressBookQuery", Variables: storeFront, Decode: func(resp string) error {
+			_, err := decodeAddressBook(resp)
+			return err
+		}},
+		{Name: "CartFlowQuery", Variables: storeFront, Decode: func(resp string) error {
+			_, err := decodeCartFlow("CartFlowQuery", resp)
+			return err
+		}},
+	}
+
+	if slug != "" {
+		ops = append(ops,
+			probeOperation{
+				Name:      "GetSkuQuery",
+				Variables: map[string]interface{}{"slug": slug, "storeFront": "pledge"},
+				Decode: func(resp string) error {
+					_, err := decodeListingSkus(resp)
+					return err
+				},
+			},
+			probeOperation{
+				Name: "GetSkus",
+				Variables: map[string]interface{}{
+					"query": map[string]interface{}{
+						"skus": map[string]interface{}{"slugs": []string{slug}},
+					},
+				},
+				Decode: func(resp string) error {
+					_, err := decodeSkuSearch(resp)
+					return err
+				},
+			},
+		)
+	}
+
+	return ops
+}
+
+// shapeOf walks a decoded GraphQL batch response and records the shape of its first result
+func shapeOf(resp string) (responseShape, error) {
+	var batch []interface{}
+	if err := json.Unmarshal([]byte(resp), &batch); err != nil {
+		return nil, err
+	}
+	if len(batch) == 0 {
+		return nil, TError("error_probe_empty_response")
+	}
+
+	shape := responseShape{}
+	if result, ok := batch[0].(map[string]interface{}); ok {
+		shape.add("data", result["data"])
+	}
+	return shape, nil
+}
+
+// add records the kind of value at path and everything below it
+func (s responseShape) add(path string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s[path] = "object"
+		for key, child := range v {
+			s.add(path+"."+key, child)
+		}
+	case []interface{}:
+		s[path] = "array"
+		for _, child := range v {
+			s.add(path+"[]", child)
+		}
+	case string:
+		s.setKind(path, "string")
+	case float64:
+		s.setKind(path, "number")
+	case bool:
+		s.setKind(path, "bool")
+	default:
+		s.setKind(path, "null")
+	}
+}
+
+// setKind keeps the non-null kind when array elements disagree about a nullable field
+func (s responseShape) setKind(path, kind string) {
+	if existing, ok := s[path]; ok && kind == "null" && existing != "null" {
+		return
+	}
+	s[path] = kind
+}
+
+// merge keeps what an earlier shape knows about fields that are null or inside empty
+// arrays this time; fields that are really gone are not brought back
+func (s responseShape) merge(earlier responseShape) {
+	known := responseShape{}
+	for path, kind := range earlier {
+		existing, ok := s[path]
+		if (ok && existing == "null") || (!ok && s.unknownBelow(path)) {
+			known[path] = kind
+		}
+	}
+	for path, kind := range known {
+		s[path] = kind
+	}
+}
+
+// unknownBelow reports whether the response can't say anything about path because an
+// ancestor is null or an empty array
+func (s responseShape) unknownBelow(path string) bool {
+	for parent := parentPath(path); parent != ""; parent = parentPath(parent) {
+		kind, ok := s[parent]
+		if !ok {
+			continue
+		}
+		if kind == "null" {
+			return true
+		}
+		if kind == "array" {
+			if _, hasElements := s[parent+"[]"]; !hasElements {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parentPath returns the enclosing path ("a.b[]" → "a.b", "a.b" → "a")
+func parentPath(path string) string {
+	if strings.HasSuffix(path, "[]") {
+		return strings.TrimSuffix(path, "[]")
+	}
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// compareShapes lists how the live shape differs from the baseline. Only the topmost
+// missing or new path is reported, and null values or empty arrays are not drift.
+func compareShapes(baseline, current responseShape) []shapeDrift {
+	var drift []shapeDrift
+
+	for path, was := range baseline {
+		now, ok := current[path]
+		if !ok {
+			parent := parentPath(path)
+			if _, parentFound := current[parent]; (parentFound || parent == "") && !current.unknownBelow(path) {
+				drift = append(drift, shapeDrift{Path: path, Was: was})
+			}
+			continue
+		}
+		if now != was && now != "null" && was != "null" {
+			drift = append(drift, shapeDrift{Path: path, Was: was, Now: now})
+		}
+	}
+
+	for path, now := range current {
+		if _, ok := baseline[path]; ok {
+			continue
+		}
+		if _, ok := baseline[parentPath(path)]; ok && !baseline.unknownBelow(path) {
+			drift = append(drift, shapeDrift{Path: path, Now: now})
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Path < drift[j].Path })
+	return drift
+}
+
+// flowSteps extracts the step names from a CartFlowQuery response
+func flowSteps(resp string) []string {
+	flow, err := decodeCartFlow("CartFlowQuery", resp)
+	if err != nil {
+		return nil
+	}
+	steps := make([]string, 0, len(flow.Steps))
+	for _, step := range flow.Steps {
+		steps = append(steps, step.Step)
+	}
+	return steps
+}
+
+// loadProbeBaseline reads the stored baseline; nil when none was recorded yet
+func loadProbeBaseline(path string) (*probeBaseline, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline probeBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, TError("error_probe_baseline_parse", "path", path, "error", err)
+	}
+	return &baseline, nil
+}
+
+func (b *probeBaseline) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// RunProbe runs every read-only store query, compares the responses with the baseline
+// and returns the number of problems found. With update set (or without a baseline)
+// the live shapes become the new baseline.
+func (f *FastCheckout) RunProbe(slug string, baselinePath string, update bool) (int, error) {
+	baseline, err := loadProbeBaseline(baselinePath)
+	if err != nil {
+		return 0, err
+	}
+
+	live := &probeBaseline{RecordedAt: time.Now().UTC(), Operations: map[string]responseShape{}}
+	problems := 0
+
+	for _, op := range probeOperations(slug, f.currency()) {
+		resp, err := f.graphqlRequestWithLoginRetry([]GraphQLRequest{{
+			OperationName: op.Name,
+			Variables:     op.Variables,
+			Query:         operation(op.Name),
+		}})
+		if err != nil {
+			f.reporter.Warn(T("probe_operation_failed", "name", op.Name, "error", err))
+			problems++
+			continue
+		}
+
+		shape, err := shapeOf(resp)
+		if err != nil {
+			f.reporter.Warn(T("probe_operation_failed", "name", op.Name, "error", err))
+			problems++
+			continue
+		}
+		live.Operations[op.Name] = shape
+		if op.Name == "CartFlowQuery" {
+			live.FlowSteps = flowSteps(resp)
+		}
+
+		// New fields are listed too, but only what checkout can trip over counts as a problem
+		var issues []string
+		opProblems := 0
+		if err := op.Decode(resp); err != nil {
+			issues = append(issues, err.Error())
+			opProblems++
+		}
+		if baseline != nil {
+			if known, ok := baseline.Operations[op.Name]; ok {
+				for _, drift := range compareShapes(known, shape) {
+					issues = append(issues, drift.String())
+					if drift.Was != "" {
+						opProblems++
+					}
+				}
+				shape.merge(known)
+			}
+		}
+
+		switch {
+		case len(issues) == 0:
+			f.reporter.Info(T("probe_operation_ok", "name", op.Name))
+			continue
+		case opProblems == 0:
+			f.reporter.Info(T("probe_operation_new_fields", "name", op.Name))
+		default:
+			f.reporter.Warn(T("probe_operation_drift", "name", op.Name))
+		}
+		for _, issue := range issues {
+			f.reporter.Info(fmt.Sprintf("   • %s", issue))
+		}
+		problems += opProblems
+	}
+
+	if slug == "" {
+		f.reporter.Info(T("probe_listing_skipped"))
+	}
+
+	if baseline != nil && live.FlowSteps != nil {
+		for _, step := range live.FlowSteps {
+			if !containsString(baseline.FlowSteps, step) {
+				f.reporter.Warn(T("probe_flow_step_unexpected", "step", step, "known", strings.Join(baseline.FlowSteps, ", ")))
+				problems++
+			}
+		}
+		for _, step := range baseline.FlowSteps {
+			if !containsString(live.FlowSteps, step) {
+				f.reporter.Warn(T("probe_flow_step_missing", "step", step))
+				problems++
+			}
+		}
+	}
+
+	if baseline == nil || update {
+		// Operations that failed this time keep their previous shape
+		if baseline != nil {
+			for name, shape := range baseline.Operations {
+				if _, ok := live.Operations[name]; !ok {
+					live.Operations[name] = shape
+				}
+			}
+			if live.FlowSteps == nil {
+				live.FlowSteps = baseline.FlowSteps
+			}
+		}
+		if err := live.save(baselinePath); err != nil {
+			return problems, TError("error_probe_baseline_save", "error", err)
+		}
+		f.reporter.Info(T("probe_baseline_saved", "path", baselinePath))
+	}
+
+	return problems, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
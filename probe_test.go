@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mustShape(t *testing.T, resp string) responseShape {
+	t.Helper()
+	shape, err := shapeOf(resp)
+	if err != nil {
+		t.Fatalf("shapeOf failed: %v", err)
+	}
+	return shape
+}
+
+func TestCompareShapes(t *testing.T) {
+	baseline := mustShape(t, `[{"data":{"store":{"cart":{"totals":{"total":100,"credits":{"amount":0,"maxApplicable":100}},"lineItems":[{"id":"a","qty":1}]}}}}]`)
+
+	tests := []struct {
+		name  string
+		resp  string
+		drift []string // Paths expected to drift
+	}{
+		{
+			name: "same shape",
+			resp: `[{"data":{"store":{"cart":{"totals":{"total":5,"credits":{"amount":5,"maxApplicable":0}},"lineItems":[{"id":"b","qty":3}]}}}}]`,
+		},
+		{
+			name: "null and empty values",
+			resp: `[{"data":{"store":{"cart":{"totals":{"total":5,"credits":null},"lineItems":[]}}}}]`,
+		},
+		{
+			name:  "renamed field",
+			resp:  `[{"data":{"store":{"cart":{"totals":{"total":5,"credits":{"amount":0,"maxCredit":0}},"lineItems":[]}}}}]`,
+			drift: []string{"data.store.cart.totals.credits.maxApplicable", "data.store.cart.totals.credits.maxCredit"},
+		},
+		{
+			name:  "changed type and missing object",
+			resp:  `[{"data":{"store":{"cart":{"lineItems":[{"id":7,"qty":1}]}}}}]`,
+			drift: []string{"data.store.cart.lineItems[].id", "data.store.cart.totals"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			for _, d := range compareShapes(baseline, mustShape(t, tt.resp)) {
+				paths = append(paths, d.Path)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.drift, ",") {
+				t.Errorf("Expected drift %v, got %v", tt.drift, paths)
+			}
+		})
+	}
+}
+
+func TestShapeMergeKeepsNullableFields(t *testing.T) {
+	earlier := mustShape(t, `[{"data":{"store":{"cart":{"totals":{"credits":{"amount":100}},"lineItems":[{"id":"a"}],"gone":1}}}}]`)
+	current := mustShape(t, `[{"data":{"store":{"cart":{"totals":{"credits":null},"lineItems":[]}}}}]`)
+
+	current.merge(earlier)
+	if current["data.store.cart.totals.credits.amount"] != "number" || current["data.store.cart.lineItems[].id"] != "string" {
+		t.Errorf("Fields hidden by null values should be kept: %v", current)
+	}
+	if _, ok := current["data.store.cart.gone"]; ok {
+		t.Error("A field that is really gone should not be brought back")
+	}
+}
+
+// fakeProbeServer answers the probe queries with canned responses by operation name
+func fakeProbeServer(t *testing.T, responses map[string]string) *FastCheckout {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []GraphQLRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+		w.Write([]byte(responses[requests[0].OperationName]))
+	}))
+	t.Cleanup(server.Close)
+
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.graphqlURL = server.URL
+	return fc
+}
+
+func TestRunProbe(t *testing.T) {
+	cart := `[{"data":{"store":{"cart":{"totals":{"total":4500,"credits":{"amount":0,"maxApplicable":4500}},"lineItems":[{"id":"l1","skuId":222,"sku":{"title":"Target Ship"},"unitPriceWithTax":{"amount":4500},"qty":1}]}},"customer":{"ledger":{"amount":{"value":100000}}}}}]`
+	responses := map[string]string{
+		"CombinedCartQuery":    cart,
+		"CartSummaryViewQuery": cart,
+		"StepperQuery":         cart,
+		"AddressBookQuery":     `[{"data":{"store":{"addressBook":[{"id":"1","defaultBilling":true}]}}}]`,
+		"CartFlowQuery":        `[{"data":{"store":{"cart":{"flow":{"steps":[{"step":"cart","active":true},{"step":"billing","active":false}]}}}}}]`,
+		"GetSkuQuery":          `[{"data":{"store":{"listing":{"skus":[{"id":222,"title":"Target Ship"}]}}}}]`,
+		"GetSkus":              `[{"data":{"store":{"search":{"resources":[{"id":"222","slug":"target"}]}}}}]`,
+	}
+	fc := fakeProbeServer(t, responses)
+	path := filepath.Join(t.TempDir(), "probe-baseline.json")
+
+	// The first run records the baseline
+	problems, err := fc.RunProbe("target", path, false)
+	if err != nil || problems != 0 {
+		t.Fatalf("First probe should record a clean baseline, got %d problems, %v", problems, err)
+	}
+	baseline, err := loadProbeBaseline(path)
+	if err != nil || baseline == nil || len(baseline.Operations) != 7 || strings.Join(baseline.FlowSteps, ",") != "cart,billing" {
+		t.Fatalf("Unexpected baseline: %+v, %v", baseline, err)
+	}
+	if data, _ := json.Marshal(baseline); strings.Contains(string(data), "Target Ship") {
+		t.Error("Baseline must hold shapes, not values")
+	}
+
+	// The store renames the ledger, changes a step and adds a field
+	responses["CombinedCartQuery"] = strings.Replace(cart, `"ledger"`, `"wallet"`, 1)
+	responses["CartFlowQuery"] = `[{"data":{"store":{"cart":{"flow":{"steps":[{"step":"cart","active":true},{"step":"payment","active":false}]}}}}}]`
+	responses["AddressBookQuery"] = `[{"data":{"store":{"addressBook":[{"id":"1","defaultBilling":true,"label":"Home"}]}}}]`
+
+	problems, err = fc.RunProbe("target", path, false)
+	if err != nil {
+		t.Fatalf("RunProbe failed: %v", err)
+	}
+	// Decoder error, ledger missing, wallet new (not a problem), payment unexpected, billing gone
+	if problems != 4 {
+		t.Errorf("Expected 4 problems, got %d", problems)
+	}
+
+	// Accepting the new shapes clears the drift
+	if _, err := fc.RunProbe("target", path, true); err != nil {
+		t.Fatalf("RunProbe update failed: %v", err)
+	}
+	responses["CombinedCartQuery"] = cart
+	if problems, _ := fc.RunProbe("target", path, false); problems != 1 {
+		t.Errorf("Expected only the restored ledger to differ after update, got %d problems", problems)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// PromptKind identifies a question, so policies and scripts can answer it without a terminal
+type PromptKind string
+
+const (
+	PromptLoginRequired     PromptKind = "login_required"     // Initial browser login before the first wave
+	PromptSessionExpired    PromptKind = "session_expired"    // Store reported "not logged in" mid-checkout
+	PromptCartMismatch      PromptKind = "cart_mismatch"      // Cart holds other items, extra quantity or a wrong total
+	PromptSessionPassphrase PromptKind = "session_passphrase" // Passphrase for the encrypted session cache
+	PromptCartRestore       PromptKind = "cart_restore"       // Put saved cart items back after the run
+)
+
+// PromptAnswer is one of the choices offered by a prompt
+type PromptAnswer string
+
+const (
+	AnswerProceed PromptAnswer = "proceed"
+	AnswerAbort   PromptAnswer = "abort"
+	AnswerClean   PromptAnswer = "clean"
+	AnswerRestore PromptAnswer = "restore"
+	AnswerSkip    PromptAnswer = "skip"
+)
+
+// Prompt is a question for the user. The caller prints any context beforehand;
+// Message is the final line shown right before waiting for input.
+type Prompt struct {
+	Kind    PromptKind
+	Message string
+	Options []PromptAnswer // Options[0] is the default (ENTER); AnswerAbort is ESC
+	Secret  bool           // ReadLine input is not echoed (passphrases)
+}
+
+// Prompter answers the questions that used to block on stdin
+type Prompter interface {
+	// Choose returns one of prompt.Options
+	Choose(prompt Prompt) (PromptAnswer, error)
+	// ReadLine returns a line of free text (e.g. a passphrase)
+	ReadLine(prompt Prompt) (string, error)
+}
+
+// NewPrompter builds the prompter for a run: configured policies answer first, everything
+// else goes to the terminal - or fails fast in unattended mode
+func NewPrompter(config *Config) Prompter {
+	var fallback Prompter
+	if !config.Unattended {
+		fallback = &TerminalPrompter{}
+	}
+
+	return &PolicyPrompter{
+		Policies: promptPolicies(config),
+		Fallback: fallback,
+		Reporter: NewReporter(config, "prompt"),
+	}
+}
+
+// promptPolicies maps the on_* config options to prompt answers
+func promptPolicies(config *Config) map[PromptKind]PromptAnswer {
+	policies := make(map[PromptKind]PromptAnswer)
+	if config.OnLoginRequired != "" {
+		policies[PromptLoginRequired] = PromptAnswer(config.OnLoginRequired)
+	}
+	if config.OnSessionExpired != "" {
+		policies[PromptSessionExpired] = PromptAnswer(config.OnSessionExpired)
+	}
+	if config.OnCartMismatch != "" {
+		policies[PromptCartMismatch] = PromptAnswer(config.OnCartMismatch)
+	}
+	if config.OnCartRestore != "" {
+		policies[PromptCartRestore] = PromptAnswer(config.OnCartRestore)
+	}
+	return policies
+}
+
+// hasOption reports whether answer is one of the prompt's options
+func (p Prompt) hasOption(answer PromptAnswer) bool {
+	for _, option := range p.Options {
+		if option == answer {
+			return true
+		}
+	}
+	return false
+}
+
+// terminalInput is shared by all terminal prompts so bytes buffered by one prompt are
+// never lost to the next
+var terminalInput = bufio.NewReader(os.Stdin)
+
+// TerminalPrompter asks the user on stdin: ENTER picks the default option, ESC aborts,
+// and any other option is picked by its first letter
+type TerminalPrompter struct{}
+
+func (t *TerminalPrompter) Choose(prompt Prompt) (PromptAnswer, error) {
+	beginTerminalPrompt()
+	defer endTerminalPrompt()
+
+	fmt.Print(prompt.Message)
+
+	for {
+		input, err := terminalInput.ReadByte()
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if input == '\n' || input == '\r' {
+			fmt.Println()
+			return prompt.Options[0], nil
+		}
+
+		var answer PromptAnswer
+		if input == 27 { // ESC key
+			answer = AnswerAbort
+		} else {
+			for _, option := range prompt.Options {
+				if strings.ToLower(string(input)) == string(option[0]) {
+					answer = option
+					break
+				}
+			}
+		}
+
+		if answer != "" && prompt.hasOption(answer) {
+			// The terminal is line-buffered: drop the ENTER that followed the key
+			terminalInput.ReadString('\n')
+			fmt.Println()
+			return answer, nil
+		}
+	}
+}
+
+func (t *TerminalPrompter) ReadLine(prompt Prompt) (string, error) {
+	beginTerminalPrompt()
+	defer endTerminalPrompt()
+
+	fmt.Print(prompt.Message)
+
+	// Secrets typed at a terminal are read without echo; piped input has none anyway.
+	// Under the dashboard stdin belongs to its key reader, which feeds terminalInput.
+	fd := int(os.Stdin.Fd())
+	if prompt.Secret && dashboard == nil && terminalInput.Buffered() == 0 && term.IsTerminal(fd) {
+		secret, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		return string(secret), nil
+	}
+
+	line, err := terminalInput.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// PolicyPrompter answers prompts from configured policies. Prompts without a policy go
+// to Fallback; with no fallback (unattended mode) they fail immediately instead of blocking.
+type PolicyPrompter struct {
+	Policies map[PromptKind]PromptAnswer
+	Fallback Prompter
+	Reporter Reporter // Announces answers taken from a policy
+}
+
+func (p *PolicyPrompter) Choose(prompt Prompt) (PromptAnswer, error) {
+	if answer, ok := p.Policies[prompt.Kind]; ok {
+		if !prompt.hasOption(answer) {
+			return "", TError("prompt_policy_invalid", "answer", answer, "kind", prompt.Kind, "options", prompt.Options)
+		}
+		p.Reporter.Info(T("prompt_policy_answer", "kind", prompt.Kind, "answer", answer))
+		return answer, nil
+	}
+
+	if p.Fallback == nil {
+		return "", TError("prompt_unattended_no_policy", "kind", prompt.Kind)
+	}
+	return p.Fallback.Choose(prompt)
+}
+
+func (p *PolicyPrompter) ReadLine(prompt Prompt) (string, error) {
+	// Free text (passphrases) never comes from config
+	if p.Fallback == nil {
+		return "", TError("prompt_unattended_no_input", "kind", prompt.Kind)
+	}
+	return p.Fallback.ReadLine(prompt)
+}
+
+// ScriptedPrompter replays canned answers in order, for tests. Every prompt it is
+// asked is recorded in Asked.
+type ScriptedPrompter struct {
+	Answers []PromptAnswer
+	Lines   []string
+	Asked   []PromptKind
+}
+
+func (s *ScriptedPrompter) Choose(prompt Prompt) (PromptAnswer, error) {
+	s.Asked = append(s.Asked, prompt.Kind)
+	if len(s.Answers) == 0 {
+		return "", fmt.Errorf("scripted prompter: no answer left for %s", prompt.Kind)
+	}
+
+	answer := s.Answers[0]
+	s.Answers = s.Answers[1:]
+	if !prompt.hasOption(answer) {
+		return "", fmt.Errorf("scripted prompter: %q is not an option for %s", answer, prompt.Kind)
+	}
+	return answer, nil
+}
+
+func (s *ScriptedPrompter) ReadLine(prompt Prompt) (string, error) {
+	s.Asked = append(s.Asked, prompt.Kind)
+	if len(s.Lines) == 0 {
+		return "", fmt.Errorf("scripted prompter: no line left for %s", prompt.Kind)
+	}
+
+	line := s.Lines[0]
+	s.Lines = s.Lines[1:]
+	return line, nil
+}
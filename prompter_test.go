@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicyPrompterAnswersFromConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.OnCartMismatch = "abort"
+
+	prompter := NewPrompter(config)
+	answer, err := prompter.Choose(Prompt{
+		Kind:    PromptCartMismatch,
+		Options: []PromptAnswer{AnswerProceed, AnswerAbort},
+	})
+	if err != nil {
+		t.Fatalf("Choose failed: %v", err)
+	}
+	if answer != AnswerAbort {
+		t.Errorf("Expected policy answer abort, got %q", answer)
+	}
+}
+
+func TestPolicyPrompterRejectsInvalidAnswer(t *testing.T) {
+	prompter := &PolicyPrompter{
+		Policies: map[PromptKind]PromptAnswer{PromptLoginRequired: "maybe"},
+	}
+
+	_, err := prompter.Choose(Prompt{
+		Kind:    PromptLoginRequired,
+		Options: []PromptAnswer{AnswerProceed, AnswerAbort},
+	})
+	if err == nil || !strings.Contains(err.Error(), "maybe") {
+		t.Errorf("Expected invalid policy error, got %v", err)
+	}
+}
+
+func TestUnattendedFailsFastWithoutPolicy(t *testing.T) {
+	config := DefaultConfig()
+	config.Unattended = true
+	prompter := NewPrompter(config)
+
+	if _, err := prompter.Choose(Prompt{Kind: PromptSessionExpired, Options: []PromptAnswer{AnswerProceed, AnswerAbort}}); err == nil {
+		t.Error("Expected unattended prompt without policy to fail")
+	}
+	if _, err := prompter.ReadLine(Prompt{Kind: PromptSessionPassphrase}); err == nil {
+		t.Error("Expected unattended passphrase prompt to fail")
+	}
+}
+
+func TestPolicyPrompterFallsBack(t *testing.T) {
+	script := &ScriptedPrompter{Answers: []PromptAnswer{AnswerProceed}, Lines: []string{"secret"}}
+	prompter := &PolicyPrompter{
+		Policies: map[PromptKind]PromptAnswer{PromptCartMismatch: AnswerAbort},
+		Fallback: script,
+	}
+
+	answer, err := prompter.Choose(Prompt{Kind: PromptLoginRequired, Options: []PromptAnswer{AnswerProceed, AnswerAbort}})
+	if err != nil || answer != AnswerProceed {
+		t.Errorf("Expected fallback answer proceed, got %q, %v", answer, err)
+	}
+
+	line, err := prompter.ReadLine(Prompt{Kind: PromptSessionPassphrase})
+	if err != nil || line != "secret" {
+		t.Errorf("Expected fallback line, got %q, %v", line, err)
+	}
+
+	if len(script.Asked) != 2 {
+		t.Errorf("Expected fallback to be asked twice, got %v", script.Asked)
+	}
+}
+
+func TestValidateCartContentsUsesPrompter(t *testing.T) {
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+
+	items := []CartItem{
+		{Name: "Target Ship", Price: usd(2000), SKUID: "target", Quantity: 1},
+		{Name: "Other Ship", Price: usd(4500), SKUID: "other", Quantity: 1},
+	}
+
+	script := &ScriptedPrompter{Answers: []PromptAnswer{AnswerProceed, AnswerAbort}}
+	fc.prompter = script
+
+	shouldAdd, err := fc.ValidateCartContents("target", &CartInfo{Total: usd(6500), Items: items})
+	if err != nil || shouldAdd {
+		t.Errorf("Expected proceed with existing cart, got %v, %v", shouldAdd, err)
+	}
+
+	if _, err := fc.ValidateCartContents("target", &CartInfo{Total: usd(6500), Items: items}); err == nil {
+		t.Error("Expected abort to return an error")
+	}
+
+	if len(script.Asked) != 2 || script.Asked[0] != PromptCartMismatch {
+		t.Errorf("Expected two cart mismatch prompts, got %v", script.Asked)
+	}
+
+	// A clean cart never prompts
+	if _, err := fc.ValidateCartContents("target", &CartInfo{Total: usd(2000), Items: items[:1]}); err != nil {
+		t.Errorf("Expected matching cart to pass without prompting: %v", err)
+	}
+	if len(script.Asked) != 2 {
+		t.Errorf("Matching cart should not prompt, got %v", script.Asked)
+	}
+}
+
+func TestPromptForLoginAbort(t *testing.T) {
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.prompter = &ScriptedPrompter{Answers: []PromptAnswer{AnswerAbort}}
+
+	if err := fc.promptForLogin(nil); err == nil {
+		t.Error("Expected aborted login prompt to return an error")
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redactedValue replaces anything the redactor masks
+const redactedValue = "[REDACTED]"
+
+// redactSensitiveKeys are the JSON keys whose values are masked wherever they turn up:
+// session credentials, reCAPTCHA tokens, billing address fields and account identifiers.
+// Keys are matched whole, compared in lowercase without "_" or "-" (see normalizeKey).
+var redactSensitiveKeys = map[string]bool{
+	// Session and reCAPTCHA
+	"token": true, "csrf": true, "csrftoken": true, "xsrf": true, "xsrftoken": true,
+	"recaptchatoken": true, "cookie": true, "cookies": true, "password": true,
+	"secret": true, "authorization": true,
+
+	// Billing address, and the address ids sent to assign one
+	"firstname": true, "lastname": true, "fullname": true, "company": true,
+	"address": true, "address1": true, "address2": true, "addressline": true,
+	"addressline1": true, "addressline2": true, "street": true, "city": true,
+	"postalcode": true, "postcode": true, "zip": true, "zipcode": true, "region": true,
+	"country": true, "phone": true, "phonenumber": true, "email": true,
+	"billing": true, "shipping": true, "addressid": true, "billingaddressid": true,
+	"shippingaddressid": true,
+
+	// Account identifiers
+	"nickname": true, "handle": true, "moniker": true, "displayname": true,
+	"accountid": true, "customerid": true,
+}
+
+// redactSensitiveObjects hold an address or the account: every value inside them is
+// masked, ids included, unless its key is on the allow-list
+var redactSensitiveObjects = map[string]bool{
+	"addressbook": true, "billingaddress": true, "shippingaddress": true,
+	"customer": true, "account": true,
+}
+
+// redactAllowKeys are safe to print even under a sensitive key or inside a masked
+// object (the allow-list wins). A replay needs them: the address book's default flag
+// and the credit balance under customer.ledger.
+var redactAllowKeys = map[string]bool{
+	"operationname":   true,
+	"typename":        true, // __typename
+	"storefront":      true,
+	"step":            true,
+	"active":          true,
+	"defaultbilling":  true,
+	"defaultshipping": true,
+	"ledger":          true,
+}
+
+// redactSensitiveHeaders are HTTP headers that carry the session
+var redactSensitiveHeaders = []string{"Cookie", "Set-Cookie", "X-Csrf-Token", "Authorization"}
+
+// redactTextPatterns mask secrets in free text that isn't JSON (errors, HTML, headers)
+var redactTextPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b(Rsi-Token|Rsi-XSRF|XSRF-TOKEN|_rsi_device|Rsi-Account-Auth)=[^;\s"]+`), "${1}=" + redactedValue},
+	{regexp.MustCompile(`(?i)\b(x-csrf-token|token|recaptcha)(["']?\s*[:=]\s*["']?)[A-Za-z0-9._\-]{8,}`), "${1}${2}" + redactedValue},
+	{regexp.MustCompile(`[A-Za-z0-9_\-]{80,}`), redactedValue}, // reCAPTCHA tokens and other long opaque blobs
+}
+
+// secretValues holds exact values seen at runtime (cookies, CSRF, reCAPTCHA tokens)
+// that are masked wherever they turn up. secretsByLength is the same set sorted longest
+// first, so a secret that contains another is masked whole; it is rebuilt on register,
+// which is rare, rather than on every redaction.
+var (
+	secretValuesMutex sync.RWMutex
+	secretValues      = make(map[string]bool)
+	secretsByLength   []string
+)
+
+// registerSecret remembers values that must never be printed. Very short values are
+// ignored - masking them would garble unrelated text.
+func registerSecret(values ...string) {
+	secretValuesMutex.Lock()
+	defer secretValuesMutex.Unlock()
+
+	added := false
+	for _, value := range values {
+		if len(value) >= 8 && !secretValues[value] {
+			secretValues[value] = true
+			added = true
+		}
+	}
+	if !added {
+		return
+	}
+
+	secrets := make([]string, 0, len(secretValues))
+	for value := range secretValues {
+		secrets = append(secrets, value)
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	secretsByLength = secrets
+}
+
+// registerCookieSecrets registers the value of every session cookie
+func registerCookieSecrets(cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		registerSecret(cookie.Value)
+	}
+}
+
+// normalizeKey folds the spellings of a key (csrfToken, csrf_token, CSRF-Token) together
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// isSensitiveKey reports whether a JSON key holds something the redactor masks
+func isSensitiveKey(key string) bool {
+	return maskedUnder(key, false)
+}
+
+// maskedUnder reports whether the value under key is masked, given whether the object
+// holding it already is
+func maskedUnder(key string, inherited bool) bool {
+	normalized := normalizeKey(key)
+	switch {
+	case redactAllowKeys[normalized]:
+		return false
+	case redactSensitiveKeys[normalized], redactSensitiveObjects[normalized]:
+		return true
+	}
+	return inherited
+}
+
+// redactText masks secrets in any text bound for a log, the console or a file.
+// JSON documents are redacted key by key so their structure survives; other text
+// is masked by pattern.
+func redactText(text string) string {
+	if text == "" {
+		return text
+	}
+
+	if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		if redacted, ok := redactJSON([]byte(trimmed)); ok {
+			return string(redacted)
+		}
+	}
+
+	return redactPlain(text)
+}
+
+// redactPlain masks registered secrets and secret-looking patterns in free text
+func redactPlain(text string) string {
+	secretValuesMutex.RLock()
+	secrets := secretsByLength // Replaced, never modified, by registerSecret
+	secretValuesMutex.RUnlock()
+
+	for _, value := range secrets {
+		text = strings.ReplaceAll(text, value, redactedValue)
+	}
+
+	for _, rule := range redactTextPatterns {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
+
+// redactJSON masks sensitive values anywhere in a JSON document. ok is false when
+// the data isn't JSON.
+func redactJSON(data []byte) (redacted []byte, ok bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return data, false
+	}
+
+	redacted, err := json.Marshal(redactValue(document))
+	if err != nil {
+		return data, false
+	}
+	return redacted, true
+}
+
+// redactValue masks sensitive keys in decoded JSON and free-text secrets in strings
+func redactValue(value interface{}) interface{} {
+	return redactNode(value, false)
+}
+
+// redactNode walks one JSON value. Under a sensitive key or inside an address or the
+// customer every scalar is masked, ids and nested objects such as country{name}
+// included; an allow-listed key lifts the masking for its own subtree.
+func redactNode(value interface{}, masked bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			v[key] = redactNode(inner, maskedUnder(key, masked))
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactNode(v[i], masked)
+		}
+	case string:
+		if masked {
+			return redactedValue
+		}
+		return redactPlain(v)
+	case json.Number, bool:
+		if masked {
+			return redactedValue
+		}
+	}
+	return value
+}
+
+// redactFields masks an event log entry's fields before they are written
+func redactFields(fields map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		switch {
+		case isSensitiveKey(key):
+			redacted[key] = redactedValue
+		case value == nil:
+			redacted[key] = nil
+		default:
+			if text, ok := value.(string); ok {
+				redacted[key] = redactText(text)
+			} else if err, ok := value.(error); ok {
+				redacted[key] = redactText(err.Error())
+			} else {
+				redacted[key] = value
+			}
+		}
+	}
+	return redacted
+}
+
+// redactHeaders copies headers with the session credentials masked
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactSensitiveHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// maskSecret shows that a secret is present without revealing it
+func maskSecret(secret string) string {
+	return fmt.Sprintf("%s (len=%d)", redactedValue, len(secret))
+}
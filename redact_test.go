@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedactTextJSON(t *testing.T) {
+	resp := `[{"data":{"store":{"cart":{"billingAddress":{"id":"addr-991","firstname":"Jane","lastname":"Doe","city":"Springfield"},"totals":{"total":0}}},"customer":{"nickname":"pilot42","ledger":{"amount":{"value":450000}}}}}]`
+
+	redacted := redactText(resp)
+	for _, secret := range []string{"Jane", "Doe", "Springfield", "pilot42", "addr-991"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("Redacted response still contains %q: %s", secret, redacted)
+		}
+	}
+	// Amounts and the allow-listed credit balance are kept so replayed responses still work
+	if !strings.Contains(redacted, "450000") || !strings.Contains(redacted, `"total":0`) {
+		t.Errorf("Expected amounts to survive redaction: %s", redacted)
+	}
+
+	request := `[{"operationName":"CartValidateCartMutation","variables":{"storeFront":"pledge","mark":"123","token":"03AFcWeA5abcdef"}}]`
+	redacted = redactText(request)
+	if strings.Contains(redacted, "03AFcWeA5abcdef") {
+		t.Errorf("reCAPTCHA token not redacted: %s", redacted)
+	}
+	if !strings.Contains(redacted, "CartValidateCartMutation") || !strings.Contains(redacted, `"mark":"123"`) {
+		t.Errorf("Allow-listed and safe fields should be kept: %s", redacted)
+	}
+}
+
+func TestRedactTextAddressAndCustomer(t *testing.T) {
+	resp := `[{"data":{"store":{"addressBook":[{"id":"addr-5531","defaultBilling":true,"addressLine":"742 Evergreen Terrace","country":{"id":"US","name":"United States"},"region":{"id":"38","name":"Oregon"}}]},"customer":{"id":"cust-123456","accountId":998877,"ledger":{"amount":{"value":450000}}}}}]`
+
+	redacted := redactText(resp)
+	for _, secret := range []string{"addr-5531", "742 Evergreen Terrace", "United States", "Oregon", "cust-123456", "998877"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("Redacted response still contains %q: %s", secret, redacted)
+		}
+	}
+
+	// A replay still finds the default address and the credit balance
+	addresses, err := decodeAddressBook(redacted)
+	if err != nil || len(addresses) != 1 || !addresses[0].DefaultBilling {
+		t.Errorf("Redacted address book no longer decodes: %+v (%v)", addresses, err)
+	}
+	if !strings.Contains(redacted, `"value":450000`) {
+		t.Errorf("Expected the allow-listed ledger to survive redaction: %s", redacted)
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	for _, key := range []string{"csrfToken", "csrf_token", "CSRF-Token", "postalCode", "first_name", "nickname", "addressLine", "accountId", "billingAddressId", "customer"} {
+		if !isSensitiveKey(key) {
+			t.Errorf("Expected %q to be sensitive", key)
+		}
+	}
+	// Whole keys only: these merely contain a sensitive word, or are allow-listed
+	for _, key := range []string{"id", "cityCode", "handleBar", "tokenExpiry", "operationName", "__typename", "defaultBilling"} {
+		if isSensitiveKey(key) {
+			t.Errorf("Expected %q to be kept", key)
+		}
+	}
+}
+
+func TestRedactTextPlain(t *testing.T) {
+	registerSecret("csrf-value-123456")
+
+	text := "Cookie: Rsi-Token=abc123def456; other=1 x-csrf-token: csrf-value-123456 token=0123456789abcdef"
+	redacted := redactText(text)
+	for _, secret := range []string{"abc123def456", "csrf-value-123456", "0123456789abcdef"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("Redacted text still contains %q: %s", secret, redacted)
+		}
+	}
+	if !strings.Contains(redacted, "other=1") {
+		t.Errorf("Unrelated text should be kept: %s", redacted)
+	}
+
+	blob := strings.Repeat("A1b2", 30)
+	if strings.Contains(redactText("preview "+blob), blob) {
+		t.Error("Long opaque token should be redacted")
+	}
+}
+
+func TestRedactFields(t *testing.T) {
+	fields := redactFields(map[string]interface{}{
+		"csrf_token": "abc",
+		"operation":  "CartValidateCartMutation",
+		"attempts":   3,
+		"error":      errors.New("HTTP error 401: Rsi-Token=secret-cookie"),
+	})
+
+	if fields["csrf_token"] != redactedValue {
+		t.Errorf("Sensitive field not masked: %v", fields["csrf_token"])
+	}
+	if fields["operation"] != "CartValidateCartMutation" || fields["attempts"] != 3 {
+		t.Errorf("Safe fields changed: %v", fields)
+	}
+	if strings.Contains(fields["error"].(string), "secret-cookie") {
+		t.Errorf("Error text not redacted: %v", fields["error"])
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Cookie", "Rsi-Token=secret")
+	header.Set("Content-Type", "application/json")
+
+	redacted := redactHeaders(header)
+	if redacted.Get("Cookie") != redactedValue || redacted.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected headers: %v", redacted)
+	}
+	if header.Get("Cookie") != "Rsi-Token=secret" {
+		t.Error("redactHeaders must not modify the original headers")
+	}
+}
+
+func TestEventLogIsRedacted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No ~/.specter yet, as on a clean machine
+
+	registerSecret("event-secret-token")
+	appendEventLog("test_event", map[string]interface{}{"detail": "token event-secret-token leaked"})
+
+	data, err := os.ReadFile(eventLogPath())
+	if err != nil {
+		t.Fatalf("Event log not written: %v", err)
+	}
+	if strings.Contains(string(data), "event-secret-token") {
+		t.Errorf("Event log leaks a registered secret: %s", data)
+	}
+}
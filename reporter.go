@@ -0,0 +1,535 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Output modes (config "output", flag --output)
+const (
+	OutputHuman = "human" // Emoji and box characters for a person watching the terminal
+	OutputJSON  = "json"  // One JSON object per line for scripts and log collectors
+	OutputQuiet = "quiet" // Errors and outcomes only
+	OutputTUI   = "tui"   // Full-screen dashboard (flag --tui)
+)
+
+var outputModes = []string{OutputHuman, OutputJSON, OutputQuiet, OutputTUI}
+
+// Reporter receives everything a run has to tell the user. The semantic events carry
+// data rather than text, so each renderer can lay them out its own way; everything
+// else is a line of text at a level.
+type Reporter interface {
+	WaveScheduled(event WaveScheduled)
+	WaveStateChanged(event WaveStateChanged)
+	TimeSynced(event TimeSynced)
+	PollProgress(event PollProgress)
+	SessionHealth(event SessionHealth)
+	CartWarning(event CartWarning)
+	CheckoutStep(event CheckoutStep)
+	ValidateAttempt(event ValidateAttempt)
+	Success(event Success)
+
+	Info(text string) // Progress; "" is a blank line for human output
+	Warn(text string)
+	Error(text string)
+	Debug(text string) // Shown only in debug mode, but always written to the log file
+	Trace(text string) // Written to the log file only
+
+	// With returns a reporter whose log file entries carry extra attributes, such
+	// as the wave, operation or attempt they concern
+	With(args ...interface{}) Reporter
+}
+
+// WaveScheduled announces a sale wave when the orchestrator lists the schedule
+type WaveScheduled struct {
+	Wave       int
+	Total      int
+	Start      time.Time
+	Activation time.Time     // When polling for the wave begins
+	Until      time.Duration // Time left until Start, measured on the synchronized clock
+}
+
+// WaveState is where a wave is in the orchestrator's workflow
+type WaveState string
+
+const (
+	WaveWaiting   WaveState = "waiting"   // Dormant until activation
+	WavePolling   WaveState = "polling"   // Polling the product page
+	WaveCheckout  WaveState = "checkout"  // Checking out
+	WaveSucceeded WaveState = "succeeded" // Purchased
+	WaveFailed    WaveState = "failed"    // Checkout failed or timed out
+	WaveSkipped   WaveState = "skipped"   // Skipped from the dashboard
+	WaveEnded     WaveState = "ended"     // Already over when the run started
+)
+
+// WaveStateChanged is reported as a wave moves through the workflow
+type WaveStateChanged struct {
+	Wave  int
+	State WaveState
+}
+
+// TimeSynced is reported after each clock synchronization
+type TimeSynced struct {
+	Offset     time.Duration // Network time minus system time
+	ErrorBound time.Duration // Half the round trip of the sync request
+}
+
+// PollProgress is reported for every poll of the product page before a wave. Text
+// renderers show it at most every pollProgressInterval.
+type PollProgress struct {
+	Attempt int
+	Status  int           // HTTP status of the latest poll
+	Until   time.Duration // Time left until the wave starts, negative once it has started
+}
+
+// pollProgressInterval is how often text renderers show polling progress
+const pollProgressInterval = 10 * time.Second
+
+// SessionHealth is the result of a session health check while dormant
+type SessionHealth struct {
+	Healthy bool
+	Problem string // Why the session is unhealthy
+}
+
+// CartWarningReason says why the cart needs the user's attention
+type CartWarningReason string
+
+const (
+	CartWarningMultipleItems CartWarningReason = "multiple_items" // Other items besides the target
+	CartWarningQuantity      CartWarningReason = "quantity"       // More than one unit of the target
+	CartWarningTotalMismatch CartWarningReason = "total_mismatch" // The target alone, at an unexpected total
+	CartWarningWrongItem     CartWarningReason = "wrong_item"     // A single item that isn't the target
+)
+
+// CartWarning describes unexpected cart contents found before checkout
+type CartWarning struct {
+	Reason    CartWarningReason
+	TargetSKU string
+	Items     []CartItem
+	Total     Money
+	TaxOnTop  Money // Tax added on top of the line prices, if any
+	Expected  Money // What the target alone should cost (CartWarningTotalMismatch only)
+}
+
+// CheckoutStepName identifies a stage of the fast checkout
+type CheckoutStepName string
+
+const (
+	StepCheckCart       CheckoutStepName = "check_cart"
+	StepItemAdded       CheckoutStepName = "item_added"
+	StepBilling         CheckoutStepName = "billing"
+	StepPayment         CheckoutStepName = "payment"
+	StepCompleteOrder   CheckoutStepName = "complete_order"
+	StepCompletePayment CheckoutStepName = "complete_payment"
+	StepOrderCompleted  CheckoutStepName = "order_completed"
+	StepDryRunStop      CheckoutStepName = "dry_run_stop"
+)
+
+// CheckoutStep is reported as the checkout enters a stage
+type CheckoutStep struct {
+	Step    CheckoutStepName
+	Balance Money // Left to pay by card (StepPayment only)
+}
+
+// ValidateErrorClass groups failed cart validations by cause
+type ValidateErrorClass string
+
+const (
+	ValidatePayment4226 ValidateErrorClass = "payment_4226"
+	ValidatePayment4227 ValidateErrorClass = "payment_4227"
+	ValidateOutOfStock  ValidateErrorClass = "out_of_stock"
+	ValidateRateLimit   ValidateErrorClass = "rate_limit"
+	ValidateCaptcha     ValidateErrorClass = "captcha"
+	ValidateOther       ValidateErrorClass = "other"
+)
+
+// ValidateAttempt is reported for every failed attempt to validate the cart
+type ValidateAttempt struct {
+	Attempt int
+	Class   ValidateErrorClass
+}
+
+// Success is reported when a checkout went through (or, in a dry run, got as far as
+// it is allowed to)
+type Success struct {
+	Elapsed time.Duration
+	DryRun  bool
+}
+
+// NewReporter builds the reporter for one component of a run (e.g. "checkout"): the
+// renderer selected by config.Output, with everything also written to the log file.
+func NewReporter(config *Config, component string) Reporter {
+	return &tracingReporter{
+		Reporter: newRenderer(config),
+		log:      traceLogger.With("component", component),
+		console:  config.DebugMode,
+	}
+}
+
+// newRenderer builds the console renderer for config.Output; unknown modes fall back
+// to human output. All components of a run share the one dashboard.
+func newRenderer(config *Config) Reporter {
+	switch config.Output {
+	case OutputTUI:
+		if dashboard := sharedDashboard(); dashboard != nil {
+			return dashboard
+		}
+	case OutputJSON:
+		return &JSONReporter{Out: os.Stdout}
+	case OutputQuiet:
+		return &QuietReporter{Out: os.Stdout}
+	}
+	return &HumanReporter{Out: os.Stdout}
+}
+
+// validOutputMode reports whether mode is one of outputModes (empty means human)
+func validOutputMode(mode string) bool {
+	if mode == "" {
+		return true
+	}
+	for _, known := range outputModes {
+		if mode == known {
+			return true
+		}
+	}
+	return false
+}
+
+// outputMutex serializes writes so lines from concurrent components never interleave
+var outputMutex sync.Mutex
+
+func writeLine(out io.Writer, text string) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	fmt.Fprintln(out, text)
+}
+
+// closeReporter gives the terminal back if the reporter took it over
+func closeReporter(reporter Reporter) {
+	if closer, ok := reporter.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// HumanReporter prints localized text with emoji, the way Specter always has
+type HumanReporter struct {
+	Out io.Writer
+
+	lastPoll time.Time
+}
+
+func (h *HumanReporter) WaveScheduled(event WaveScheduled) {
+	h.Info(T("multiwave_wave_time", "number", event.Wave, "time", event.Start, "countdown", event.Until))
+}
+
+// WaveStateChanged prints nothing: the orchestrator explains each transition in text
+func (h *HumanReporter) WaveStateChanged(WaveStateChanged) {}
+
+func (h *HumanReporter) TimeSynced(event TimeSynced) {
+	switch {
+	case event.Offset > 0:
+		h.Info(T("multiwave_time_synced_ahead", "offset", event.Offset))
+	case event.Offset < 0:
+		h.Info(T("multiwave_time_synced_behind", "offset", -event.Offset))
+	default:
+		h.Info(T("multiwave_time_synced_perfect"))
+	}
+}
+
+func (h *HumanReporter) PollProgress(event PollProgress) {
+	if time.Since(h.lastPoll) < pollProgressInterval {
+		return
+	}
+	h.lastPoll = time.Now()
+
+	if event.Until > 0 {
+		h.Info(T("multiwave_polling_progress_before", "status", event.Status, "until_wave", event.Until.Round(time.Second)))
+	} else {
+		h.Info(T("multiwave_polling_progress_after", "status", event.Status, "since_wave", -event.Until.Round(time.Second)))
+	}
+}
+
+func (h *HumanReporter) CartWarning(event CartWarning) {
+	items := event.Items
+
+	h.Info("")
+	h.Info(T("cart_warning_header"))
+	h.Info("")
+
+	// Count units across all lines
+	totalItems := 0
+	for _, item := range items {
+		totalItems += item.Quantity
+	}
+	if len(items) == 1 {
+		h.Info(T("cart_warning_single_quantity", "quantity", items[0].Quantity, "name", items[0].Name))
+	} else {
+		h.Info(T("cart_warning_multiple_items", "count", totalItems, "lines", len(items)))
+	}
+	h.Info("")
+
+	for i, item := range items {
+		marker := "  "
+		if item.SKUID == event.TargetSKU {
+			marker = "→ "
+		}
+
+		if item.Quantity > 1 {
+			h.Info(T("cart_warning_item_quantity", "marker", marker, "number", i+1, "name", item.Name, "quantity", item.Quantity))
+		} else {
+			h.Info(T("cart_warning_item", "marker", marker, "number", i+1, "name", item.Name))
+		}
+		h.Info(T("cart_item_price_line", "price", item.Price, "quantity", item.Quantity, "total", item.Price.Mul(item.Quantity)))
+
+		if item.SKUID == event.TargetSKU {
+			h.Info(T("cart_item_target_marker"))
+			if item.Quantity > 1 {
+				h.Info(T("cart_item_quantity_warning", "quantity", item.Quantity))
+			}
+		}
+		h.Info("")
+	}
+
+	h.Info(T("cart_total_label", "total", event.Total))
+	if event.TaxOnTop.IsPositive() {
+		h.Info(T("cart_tax_on_top", "tax", event.TaxOnTop))
+	}
+	if event.Reason == CartWarningTotalMismatch {
+		h.Info(T("cart_expected_total", "expected", event.Expected, "name", items[0].Name))
+	}
+	h.Info("")
+
+	switch event.Reason {
+	case CartWarningMultipleItems:
+		h.Warn(T("cart_multiple_items_warning"))
+	case CartWarningQuantity:
+		h.Warn(T("cart_quantity_warning", "quantity", items[0].Quantity))
+		h.Info(T("cart_quantity_purchase_details", "quantity", items[0].Quantity, "name", items[0].Name, "total", items[0].Price.Mul(items[0].Quantity)))
+		h.Info("")
+		h.Info(T("cart_quantity_limit_note"))
+	case CartWarningTotalMismatch:
+		h.Warn(T("cart_total_mismatch", "total", event.Total, "expected", event.Expected))
+		h.Info(T("cart_total_mismatch_reason"))
+	default:
+		h.Warn(T("wrong_item_warning"))
+	}
+
+	// A cart prompt follows
+	h.Info("")
+	h.Info(T("cart_options_header"))
+	h.Info(T("cart_option_continue"))
+	h.Info(T("cart_option_clean"))
+	h.Info(T("cart_option_cancel"))
+	h.Info("")
+}
+
+func (h *HumanReporter) CheckoutStep(event CheckoutStep) {
+	switch event.Step {
+	case StepCheckCart:
+		h.Info(T("cart_checking_state"))
+	case StepItemAdded:
+		h.Info(T("cart_item_added_success"))
+	case StepBilling:
+		h.Info(T("checkout_moving_billing"))
+	case StepPayment:
+		h.Info(T("checkout_moving_payment", "balance", event.Balance))
+	case StepCompleteOrder:
+		h.Info(T("checkout_completing_order"))
+	case StepCompletePayment:
+		h.Info(T("checkout_completing_payment"))
+	case StepOrderCompleted:
+		h.Info(T("checkout_order_completed"))
+	case StepDryRunStop:
+		h.Info(T("checkout_dry_run_stop"))
+	}
+}
+
+// SessionHealth, ValidateAttempt: the checks print their own findings
+func (h *HumanReporter) SessionHealth(SessionHealth)     {}
+func (h *HumanReporter) ValidateAttempt(ValidateAttempt) {}
+
+func (h *HumanReporter) Success(event Success) {
+	h.Info(T("checkout_total_time", "elapsed", event.Elapsed))
+	h.Info(T("checkout_target_vs_actual", "elapsed", event.Elapsed))
+	if event.Elapsed < time.Second {
+		h.Info(T("checkout_achieved_subsecond"))
+	}
+}
+
+func (h *HumanReporter) Info(text string)  { writeLine(h.Out, text) }
+func (h *HumanReporter) Warn(text string)  { writeLine(h.Out, text) }
+func (h *HumanReporter) Error(text string) { writeLine(h.Out, text) }
+func (h *HumanReporter) Debug(text string) { writeLine(h.Out, text) }
+func (h *HumanReporter) Trace(string)      {}
+
+func (h *HumanReporter) With(...interface{}) Reporter { return h }
+
+// JSONReporter writes one JSON object per line (NDJSON). Every object has "time" and
+// "event"; durations are in milliseconds and money is {"amount": cents, "currency": code}.
+type JSONReporter struct {
+	Out io.Writer
+
+	lastPoll time.Time
+}
+
+func (j *JSONReporter) emit(event string, fields map[string]interface{}) {
+	entry := map[string]interface{}{
+		"time":  time.Now().UTC().Format(time.RFC3339Nano),
+		"event": event,
+	}
+	for key, value := range fields {
+		entry[key] = value
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	writeLine(j.Out, string(line))
+}
+
+func (j *JSONReporter) WaveScheduled(event WaveScheduled) {
+	j.emit("wave_scheduled", map[string]interface{}{
+		"wave":       event.Wave,
+		"total":      event.Total,
+		"start":      event.Start.UTC().Format(time.RFC3339),
+		"activation": event.Activation.UTC().Format(time.RFC3339),
+		"until_ms":   event.Until.Milliseconds(),
+	})
+}
+
+func (j *JSONReporter) WaveStateChanged(event WaveStateChanged) {
+	j.emit("wave_state", map[string]interface{}{
+		"wave":  event.Wave,
+		"state": event.State,
+	})
+}
+
+func (j *JSONReporter) TimeSynced(event TimeSynced) {
+	j.emit("time_synced", map[string]interface{}{
+		"offset_ms":      event.Offset.Milliseconds(),
+		"error_bound_ms": event.ErrorBound.Milliseconds(),
+	})
+}
+
+func (j *JSONReporter) PollProgress(event PollProgress) {
+	if time.Since(j.lastPoll) < pollProgressInterval {
+		return
+	}
+	j.lastPoll = time.Now()
+
+	j.emit("poll_progress", map[string]interface{}{
+		"attempt":  event.Attempt,
+		"status":   event.Status,
+		"until_ms": event.Until.Milliseconds(),
+	})
+}
+
+func (j *JSONReporter) SessionHealth(event SessionHealth) {
+	fields := map[string]interface{}{"healthy": event.Healthy}
+	if !event.Healthy {
+		fields["problem"] = event.Problem
+	}
+	j.emit("session_health", fields)
+}
+
+func (j *JSONReporter) ValidateAttempt(event ValidateAttempt) {
+	j.emit("validate_attempt", map[string]interface{}{
+		"attempt": event.Attempt,
+		"class":   event.Class,
+	})
+}
+
+func (j *JSONReporter) CartWarning(event CartWarning) {
+	items := make([]map[string]interface{}, 0, len(event.Items))
+	for _, item := range event.Items {
+		items = append(items, map[string]interface{}{
+			"name":     item.Name,
+			"sku_id":   item.SKUID,
+			"quantity": item.Quantity,
+			"price":    item.Price,
+			"target":   item.SKUID == event.TargetSKU,
+		})
+	}
+
+	fields := map[string]interface{}{
+		"reason": event.Reason,
+		"items":  items,
+		"total":  event.Total,
+	}
+	if event.TaxOnTop.IsPositive() {
+		fields["tax_on_top"] = event.TaxOnTop
+	}
+	if event.Reason == CartWarningTotalMismatch {
+		fields["expected"] = event.Expected
+	}
+	j.emit("cart_warning", fields)
+}
+
+func (j *JSONReporter) CheckoutStep(event CheckoutStep) {
+	fields := map[string]interface{}{"step": event.Step}
+	if event.Step == StepPayment {
+		fields["balance"] = event.Balance
+	}
+	j.emit("checkout_step", fields)
+}
+
+func (j *JSONReporter) Success(event Success) {
+	j.emit("success", map[string]interface{}{
+		"elapsed_ms": event.Elapsed.Milliseconds(),
+		"dry_run":    event.DryRun,
+	})
+}
+
+func (j *JSONReporter) message(level, text string) {
+	if text == "" {
+		return
+	}
+	j.emit("message", map[string]interface{}{"level": level, "text": text})
+}
+
+func (j *JSONReporter) Info(text string)  { j.message("info", text) }
+func (j *JSONReporter) Warn(text string)  { j.message("warn", text) }
+func (j *JSONReporter) Error(text string) { j.message("error", text) }
+func (j *JSONReporter) Debug(text string) { j.message("debug", text) }
+func (j *JSONReporter) Trace(string)      {}
+
+func (j *JSONReporter) With(...interface{}) Reporter { return j }
+
+// QuietReporter prints only errors and the outcome of a checkout
+type QuietReporter struct {
+	Out io.Writer
+}
+
+func (q *QuietReporter) WaveScheduled(WaveScheduled)       {}
+func (q *QuietReporter) WaveStateChanged(WaveStateChanged) {}
+func (q *QuietReporter) TimeSynced(TimeSynced)             {}
+func (q *QuietReporter) PollProgress(PollProgress)         {}
+func (q *QuietReporter) SessionHealth(SessionHealth)       {}
+func (q *QuietReporter) CheckoutStep(CheckoutStep)         {}
+func (q *QuietReporter) ValidateAttempt(ValidateAttempt)   {}
+
+// CartWarning is shown in full: a cart prompt that needs an answer comes next
+func (q *QuietReporter) CartWarning(event CartWarning) {
+	(&HumanReporter{Out: q.Out}).CartWarning(event)
+}
+
+func (q *QuietReporter) Success(event Success) {
+	if event.DryRun {
+		writeLine(q.Out, T("report_dry_run_success", "elapsed", event.Elapsed))
+	} else {
+		writeLine(q.Out, T("report_success", "elapsed", event.Elapsed))
+	}
+}
+
+func (q *QuietReporter) Info(string)       {}
+func (q *QuietReporter) Warn(string)       {}
+func (q *QuietReporter) Error(text string) { writeLine(q.Out, text) }
+func (q *QuietReporter) Debug(string)      {}
+func (q *QuietReporter) Trace(string)      {}
+
+func (q *QuietReporter) With(...interface{}) Reporter { return q }
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeLines parses NDJSON output, failing the test on any line that isn't an object
+func decodeLines(t *testing.T, output string) []map[string]interface{} {
+	var events []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Line is not JSON: %q (%v)", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestJSONReporter(t *testing.T) {
+	var out bytes.Buffer
+	r := &JSONReporter{Out: &out}
+
+	start := time.Date(2026, time.March, 7, 18, 0, 0, 0, time.UTC)
+	r.WaveScheduled(WaveScheduled{Wave: 1, Total: 2, Start: start, Until: 90 * time.Second})
+	r.Info("")
+	r.Warn("⚠️  careful")
+	r.CheckoutStep(CheckoutStep{Step: StepPayment, Balance: newMoney(1250, "USD")})
+	r.Success(Success{Elapsed: 850 * time.Millisecond})
+
+	events := decodeLines(t, out.String())
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events (blank lines dropped), got %d:\n%s", len(events), out.String())
+	}
+
+	if events[0]["event"] != "wave_scheduled" || events[0]["start"] != "2026-03-07T18:00:00Z" || events[0]["until_ms"] != 90000.0 {
+		t.Errorf("Unexpected wave event: %v", events[0])
+	}
+	if events[1]["event"] != "message" || events[1]["level"] != "warn" || events[1]["text"] != "⚠️  careful" {
+		t.Errorf("Unexpected message event: %v", events[1])
+	}
+	balance, _ := events[2]["balance"].(map[string]interface{})
+	if events[2]["step"] != "payment" || balance["amount"] != 1250.0 || balance["currency"] != "USD" {
+		t.Errorf("Unexpected step event: %v", events[2])
+	}
+	if events[3]["event"] != "success" || events[3]["elapsed_ms"] != 850.0 || events[3]["time"] == nil {
+		t.Errorf("Unexpected success event: %v", events[3])
+	}
+}
+
+// Test that dashboard events are emitted too, with text renderers throttling poll progress
+func TestJSONReporterDashboardEvents(t *testing.T) {
+	var out bytes.Buffer
+	r := &JSONReporter{Out: &out}
+
+	r.TimeSynced(TimeSynced{Offset: -1500 * time.Millisecond, ErrorBound: 35 * time.Millisecond})
+	r.WaveStateChanged(WaveStateChanged{Wave: 2, State: WavePolling})
+	r.PollProgress(PollProgress{Attempt: 1, Status: 404, Until: time.Minute})
+	r.PollProgress(PollProgress{Attempt: 2, Status: 404, Until: time.Minute})
+	r.ValidateAttempt(ValidateAttempt{Attempt: 3, Class: classifyValidateError(errors.New("error 4227: payment"))})
+	r.SessionHealth(SessionHealth{Healthy: false, Problem: "logged out"})
+
+	events := decodeLines(t, out.String())
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 events (second poll throttled), got %d:\n%s", len(events), out.String())
+	}
+	if events[0]["event"] != "time_synced" || events[0]["offset_ms"] != -1500.0 || events[0]["error_bound_ms"] != 35.0 {
+		t.Errorf("Unexpected sync event: %v", events[0])
+	}
+	if events[1]["event"] != "wave_state" || events[1]["state"] != "polling" {
+		t.Errorf("Unexpected state event: %v", events[1])
+	}
+	if events[2]["attempt"] != 1.0 {
+		t.Errorf("Unexpected poll event: %v", events[2])
+	}
+	if events[3]["event"] != "validate_attempt" || events[3]["class"] != "payment_4227" {
+		t.Errorf("Unexpected validate event: %v", events[3])
+	}
+	if events[4]["healthy"] != false || events[4]["problem"] != "logged out" {
+		t.Errorf("Unexpected session event: %v", events[4])
+	}
+}
+
+// Test that quiet output keeps only errors and outcomes
+func TestQuietReporter(t *testing.T) {
+	var out bytes.Buffer
+	r := &QuietReporter{Out: &out}
+
+	r.WaveScheduled(WaveScheduled{Wave: 1, Total: 1, Start: time.Now()})
+	r.PollProgress(PollProgress{Status: 404, Until: time.Minute})
+	r.CheckoutStep(CheckoutStep{Step: StepBilling})
+	r.Info("progress")
+	r.Warn("warning")
+	r.Debug("debug")
+	r.Error("❌ failed")
+	r.Success(Success{Elapsed: time.Second})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || lines[0] != "❌ failed" || !strings.HasPrefix(lines[1], "report_success") {
+		t.Errorf("Unexpected quiet output:\n%s", out.String())
+	}
+}
+
+// Test that the cart check reports why the cart needs attention
+func TestValidateCartContentsReportsWarning(t *testing.T) {
+	tests := []struct {
+		items  []CartItem
+		total  Money
+		reason CartWarningReason
+	}{
+		{[]CartItem{{SKUID: "222", Quantity: 1, Price: newMoney(4500, "USD")}, {SKUID: "111", Quantity: 1, Price: newMoney(1000, "USD")}}, newMoney(5500, "USD"), CartWarningMultipleItems},
+		{[]CartItem{{SKUID: "222", Quantity: 2, Price: newMoney(4500, "USD")}}, newMoney(9000, "USD"), CartWarningQuantity},
+		{[]CartItem{{SKUID: "222", Quantity: 1, Price: newMoney(4500, "USD")}}, newMoney(4000, "USD"), CartWarningTotalMismatch},
+		{[]CartItem{{SKUID: "111", Quantity: 1, Price: newMoney(1000, "USD")}}, newMoney(1000, "USD"), CartWarningWrongItem},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.reason), func(t *testing.T) {
+			fc, err := NewFastCheckout(DefaultConfig())
+			if err != nil {
+				t.Fatalf("NewFastCheckout failed: %v", err)
+			}
+			var out bytes.Buffer
+			fc.reporter = &JSONReporter{Out: &out}
+			fc.prompter = &ScriptedPrompter{Answers: []PromptAnswer{AnswerProceed}}
+
+			if _, err := fc.ValidateCartContents("222", &CartInfo{Items: tt.items, Total: tt.total}); err != nil {
+				t.Fatalf("ValidateCartContents failed: %v", err)
+			}
+
+			var warning map[string]interface{}
+			for _, event := range decodeLines(t, out.String()) {
+				if event["event"] == "cart_warning" {
+					warning = event
+				}
+			}
+			if warning == nil || warning["reason"] != string(tt.reason) {
+				t.Errorf("Expected a %s cart warning, got:\n%s", tt.reason, out.String())
+			}
+		})
+	}
+}
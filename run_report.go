@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// runReport summarizes one "specter run" from the log file
+type runReport struct {
+	PID      int
+	Started  time.Time
+	Finished time.Time
+
+	Waves       map[int]WaveState // Last state of each wave
+	TotalWaves  int
+	Synced      bool
+	Offset      time.Duration // Last clock synchronization
+	ErrorBound  time.Duration
+	Polls       int
+	LastStatus  int
+	Validations map[string]int // Failed validate attempts by class
+	Steps       []string       // Checkout steps reached, in order
+	Succeeded   bool
+	DryRun      bool
+	Elapsed     time.Duration
+	Warnings    int
+	Errors      []string
+}
+
+// logEntry is one line of the log file. Durations are written as nanoseconds.
+type logEntry struct {
+	Time       time.Time `json:"time"`
+	Level      string    `json:"level"`
+	Msg        string    `json:"msg"`
+	PID        int       `json:"pid"`
+	Component  string    `json:"component"`
+	Wave       int       `json:"wave"`
+	Total      int       `json:"total"`
+	State      string    `json:"state"`
+	Offset     int64     `json:"offset"`
+	ErrorBound int64     `json:"error_bound"`
+	Status     int       `json:"status"`
+	Class      string    `json:"class"`
+	Step       string    `json:"step"`
+	Elapsed    int64     `json:"elapsed"`
+	DryRun     bool      `json:"dry_run"`
+}
+
+// summarizeRun finds the last checkout run in a log and summarizes it. Entries of other
+// processes that wrote to the log meanwhile are left out.
+func summarizeRun(r io.Reader) (*runReport, error) {
+	var entries []logEntry
+	lastRun := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Not written by the logger (e.g. a truncated line)
+		}
+		entries = append(entries, entry)
+		if entry.Component == "run" {
+			lastRun = entry.PID
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if lastRun == 0 {
+		return nil, nil
+	}
+
+	report := &runReport{PID: lastRun, Waves: make(map[int]WaveState), Validations: make(map[string]int)}
+
+	// The same pid may turn up again in an older run: a gap of more than a day between
+	// its entries means it was reused, and only the last run counts
+	var run []logEntry
+	for _, entry := range entries {
+		if entry.PID != lastRun {
+			continue
+		}
+		if len(run) > 0 && entry.Time.Sub(run[len(run)-1].Time) > 24*time.Hour {
+			run = run[:0]
+		}
+		run = append(run, entry)
+	}
+
+	for _, entry := range run {
+		if report.Started.IsZero() {
+			report.Started = entry.Time
+		}
+		report.Finished = entry.Time
+
+		switch entry.Msg {
+		case "wave scheduled":
+			report.TotalWaves = entry.Total
+			if _, ok := report.Waves[entry.Wave]; !ok {
+				report.Waves[entry.Wave] = WaveWaiting
+			}
+		case "wave state":
+			report.Waves[entry.Wave] = WaveState(entry.State)
+		case "time synced":
+			report.Synced = true
+			report.Offset, report.ErrorBound = time.Duration(entry.Offset), time.Duration(entry.ErrorBound)
+		case "poll":
+			report.Polls++
+			report.LastStatus = entry.Status
+		case "validate attempt failed":
+			report.Validations[entry.Class]++
+		case "checkout step":
+			report.Steps = append(report.Steps, entry.Step)
+		case "success":
+			report.Succeeded = true
+			report.DryRun = entry.DryRun
+			report.Elapsed = time.Duration(entry.Elapsed)
+		default:
+			switch entry.Level {
+			case "WARN":
+				report.Warnings++
+			case "ERROR":
+				report.Errors = append(report.Errors, entry.Msg)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// print writes the report for people
+func (r *runReport) print(reporter Reporter) {
+	reporter.Info(T("report_header", "pid", r.PID, "started", r.Started, "duration", r.Finished.Sub(r.Started).Round(time.Second)))
+
+	if r.Synced {
+		reporter.Info(T("report_clock", "offset", r.Offset, "bound", r.ErrorBound))
+	}
+
+	waves := make([]int, 0, len(r.Waves))
+	for wave := range r.Waves {
+		waves = append(waves, wave)
+	}
+	sort.Ints(waves)
+	for _, wave := range waves {
+		reporter.Info(T("report_wave", "number", wave, "total", r.TotalWaves, "state", string(r.Waves[wave])))
+	}
+
+	if r.Polls > 0 {
+		reporter.Info(T("report_polls", "count", r.Polls, "status", r.LastStatus))
+	}
+	if len(r.Validations) > 0 {
+		var classes []string
+		total := 0
+		for _, class := range sortedKeys(r.Validations) {
+			classes = append(classes, fmt.Sprintf("%s %d", class, r.Validations[class]))
+			total += r.Validations[class]
+		}
+		reporter.Info(T("report_validations", "count", total, "classes", strings.Join(classes, ", ")))
+	}
+	if len(r.Steps) > 0 {
+		reporter.Info(T("report_steps", "steps", strings.Join(r.Steps, " → ")))
+	}
+
+	switch {
+	case r.Succeeded && r.DryRun:
+		reporter.Info(T("report_outcome_dry_run", "elapsed", r.Elapsed))
+	case r.Succeeded:
+		reporter.Info(T("report_outcome_success", "elapsed", r.Elapsed))
+	default:
+		reporter.Info(T("report_outcome_no_purchase"))
+	}
+
+	if r.Warnings > 0 {
+		reporter.Info(T("report_warnings", "count", r.Warnings))
+	}
+	for _, text := range r.Errors {
+		reporter.Info(T("report_error", "error", text))
+	}
+}
+
+// runReportCommand handles "specter report [log file]": a summary of the last run
+func runReportCommand(env *commandEnv, args []string) error {
+	path := filepath.Join(logDir(), logFileName)
+	switch {
+	case len(args) == 1:
+		path = args[0]
+	case len(args) > 1:
+		return TError("command_report_usage")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := summarizeRun(file)
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		return TError("report_no_run", "path", path)
+	}
+
+	report.print(env.Reporter)
+	return nil
+}
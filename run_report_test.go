@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that the report reads back what the reporters write to the log file, and only
+// for the last run
+func TestSummarizeRun(t *testing.T) {
+	withLocale(t, nil)
+
+	var log bytes.Buffer
+	handler := slog.NewJSONHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug})
+	reporter := func(pid int, component string) Reporter {
+		return &tracingReporter{Reporter: &QuietReporter{Out: &bytes.Buffer{}}, log: slog.New(handler).With("pid", pid, "component", component)}
+	}
+
+	// An earlier run, and a command running alongside the last one
+	reporter(100, "run").Info("starting")
+	reporter(100, "orchestrator").Success(Success{Elapsed: time.Second})
+	other := reporter(300, "check")
+
+	reporter(200, "run").Info("starting")
+	orchestrator := reporter(200, "orchestrator")
+	orchestrator.WaveScheduled(WaveScheduled{Wave: 1, Total: 2})
+	orchestrator.WaveScheduled(WaveScheduled{Wave: 2, Total: 2})
+	orchestrator.TimeSynced(TimeSynced{Offset: 30 * time.Millisecond, ErrorBound: 5 * time.Millisecond})
+	orchestrator.PollProgress(PollProgress{Attempt: 1, Status: 404})
+	other.Error("unrelated")
+	orchestrator.PollProgress(PollProgress{Attempt: 2, Status: 200})
+	orchestrator.WaveStateChanged(WaveStateChanged{Wave: 1, State: WaveFailed})
+	checkout := reporter(200, "checkout").With("wave", 1)
+	checkout.CheckoutStep(CheckoutStep{Step: "add_to_cart"})
+	checkout.ValidateAttempt(ValidateAttempt{Attempt: 1, Class: ValidateOutOfStock})
+	checkout.ValidateAttempt(ValidateAttempt{Attempt: 2, Class: ValidateOutOfStock})
+	checkout.Warn("slow response")
+	checkout.Error("checkout failed")
+
+	report, err := summarizeRun(strings.NewReader(log.String() + "not json\n"))
+	if err != nil {
+		t.Fatalf("summarizeRun failed: %v", err)
+	}
+	if report == nil || report.PID != 200 {
+		t.Fatalf("Expected the run of pid 200, got %+v", report)
+	}
+	if report.TotalWaves != 2 || report.Waves[1] != WaveFailed || report.Waves[2] != WaveWaiting {
+		t.Errorf("Unexpected waves: %d %v", report.TotalWaves, report.Waves)
+	}
+	if !report.Synced || report.Offset != 30*time.Millisecond || report.ErrorBound != 5*time.Millisecond {
+		t.Errorf("Unexpected clock: %v ± %v", report.Offset, report.ErrorBound)
+	}
+	if report.Polls != 2 || report.LastStatus != 200 {
+		t.Errorf("Unexpected polls: %d, last %d", report.Polls, report.LastStatus)
+	}
+	if report.Validations["out_of_stock"] != 2 || len(report.Steps) != 1 || report.Steps[0] != "add_to_cart" {
+		t.Errorf("Unexpected checkout: %v %v", report.Validations, report.Steps)
+	}
+	if report.Succeeded || report.Warnings != 1 || len(report.Errors) != 1 || report.Errors[0] != "checkout failed" {
+		t.Errorf("Unexpected outcome: success %v, %d warnings, errors %v", report.Succeeded, report.Warnings, report.Errors)
+	}
+}
+
+func TestSummarizeRunWithoutRun(t *testing.T) {
+	report, err := summarizeRun(strings.NewReader(`{"msg":"checking","pid":7,"component":"check"}` + "\n"))
+	if err != nil || report != nil {
+		t.Errorf("Expected no report, got %+v (%v)", report, err)
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	sessionCacheVersion  = 2 // Version 1 used PBKDF2; such files are treated as no cache
+	sessionCacheSaltSize = 16
+	sessionCacheKeySize  = 32 // AES-256
+
+	// Argon2id cost (RFC 9106 second recommended option: 3 passes over 64 MiB)
+	sessionCacheArgonTime    = 3
+	sessionCacheArgonMemory  = 64 * 1024 // KiB
+	sessionCacheArgonThreads = 4
+
+	// sessionPassphraseEnv lets unattended restarts supply the passphrase without a prompt
+	sessionPassphraseEnv = "SPECTER_SESSION_PASSPHRASE"
+)
+
+// cachedCookie is the on-disk form of a session cookie, with its own expiry
+type cachedCookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Path     string    `json:"path"`
+	Domain   string    `json:"domain"`
+	Expires  time.Time `json:"expires"` // Zero for browser-session cookies
+	Secure   bool      `json:"secure"`
+	HttpOnly bool      `json:"http_only"`
+}
+
+// sessionCache is everything needed to talk to the store API without a browser login
+type sessionCache struct {
+	SavedAt   time.Time      `json:"saved_at"`
+	CSRFToken string         `json:"csrf_token"`
+	UserAgent string         `json:"user_agent"`
+	Cookies   []cachedCookie `json:"cookies"`
+}
+
+// sessionCacheFile is the encrypted envelope written to disk. Only the KDF parameters
+// and nonce are in the clear; cookies and the CSRF token live in Ciphertext.
+type sessionCacheFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Time       uint32 `json:"time"`
+	MemoryKiB  uint32 `json:"memory_kib"`
+	Threads    uint8  `json:"threads"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// sessionCacheKey is a passphrase-derived key plus the salt it was derived with.
+// Deriving is deliberately slow, so it is done once at startup and reused for every save.
+type sessionCacheKey struct {
+	key  []byte
+	salt []byte
+}
+
+// sessionCachePath is where the encrypted session lives (~/.specter/session.enc)
+func sessionCachePath() string {
+	return filepath.Join(getUserDataDir(), "session.enc")
+}
+
+// newSessionCacheKey derives a key for the given salt, generating a fresh salt when nil
+func newSessionCacheKey(passphrase string, salt []byte) (*sessionCacheKey, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("empty session cache passphrase")
+	}
+
+	if salt == nil {
+		salt = make([]byte, sessionCacheSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+
+	return &sessionCacheKey{
+		key:  argon2.IDKey([]byte(passphrase), salt, sessionCacheArgonTime, sessionCacheArgonMemory, sessionCacheArgonThreads, sessionCacheKeySize),
+		salt: salt,
+	}, nil
+}
+
+// readSessionCacheFile reads the envelope without decrypting it (used to recover the salt)
+func readSessionCacheFile(path string) (*sessionCacheFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file sessionCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse session cache: %w", err)
+	}
+	if file.Version != sessionCacheVersion {
+		return nil, fmt.Errorf("unsupported session cache version %d", file.Version)
+	}
+
+	return &file, nil
+}
+
+// saveSessionCache encrypts the session with AES-256-GCM and writes it atomically
+func saveSessionCache(path string, key *sessionCacheKey, cache *sessionCache) error {
+	plaintext, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	block, err := aes.NewCipher(key.key)
+	if err != nil {
+		return fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data, err := json.MarshalIndent(sessionCacheFile{
+		Version:    sessionCacheVersion,
+		KDF:        "argon2id",
+		Time:       sessionCacheArgonTime,
+		MemoryKiB:  sessionCacheArgonMemory,
+		Threads:    sessionCacheArgonThreads,
+		Salt:       key.salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create session cache directory: %w", err)
+	}
+
+	// Write to a temp file first so a crash mid-write never leaves a corrupt cache
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session cache: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// loadSessionCache decrypts the session cache. A wrong passphrase surfaces as an
+// authentication failure from GCM, never as garbage data.
+func loadSessionCache(path string, key *sessionCacheKey) (*sessionCache, error) {
+	file, err := readSessionCacheFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid session cache nonce")
+	}
+
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt session cache (wrong passphrase?): %w", err)
+	}
+
+	var cache sessionCache
+	if err := json.Unmarshal(plaintext, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted session: %w", err)
+	}
+
+	return &cache, nil
+}
+
+// newSessionCache captures cookies, CSRF token and user agent for saving
+func newSessionCache(cookies []*http.Cookie, csrfToken, userAgent string) *sessionCache {
+	cache := &sessionCache{
+		SavedAt:   time.Now(),
+		CSRFToken: csrfToken,
+		UserAgent: userAgent,
+	}
+
+	for _, cookie := range cookies {
+		cache.Cookies = append(cache.Cookies, cachedCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			Expires:  cookie.Expires,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+		})
+	}
+
+	return cache
+}
+
+// liveCookies returns the cookies that have not expired yet. Browser-session cookies
+// (no expiry) are only trusted while the cache itself is younger than maxAge.
+func (c *sessionCache) liveCookies(now time.Time, maxAge time.Duration) []*http.Cookie {
+	cacheExpired := maxAge > 0 && now.Sub(c.SavedAt) > maxAge
+
+	var cookies []*http.Cookie
+	for _, cookie := range c.Cookies {
+		if cookie.Expires.IsZero() {
+			if cacheExpired {
+				continue
+			}
+		} else if !cookie.Expires.After(now) {
+			continue
+		}
+
+		cookies = append(cookies, &http.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			Expires:  cookie.Expires,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+		})
+	}
+
+	return cookies
+}
+
+// earliestExpiry returns the soonest expiry among cookies that carry one (zero if none)
+func (c *sessionCache) earliestExpiry() time.Time {
+	var earliest time.Time
+	for _, cookie := range c.Cookies {
+		if cookie.Expires.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || cookie.Expires.Before(earliest) {
+			earliest = cookie.Expires
+		}
+	}
+	return earliest
+}
+
+// readSessionPassphrase takes the passphrase from SPECTER_SESSION_PASSPHRASE, or asks for it
+func readSessionPassphrase(prompter Prompter) (string, error) {
+	if passphrase := os.Getenv(sessionPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	return prompter.ReadLine(Prompt{
+		Kind:    PromptSessionPassphrase,
+		Message: T("session_cache_passphrase_prompt"),
+		Secret:  true,
+	})
+}
+
+// initSessionCache derives the cache key once, reusing the salt of an existing cache file
+// so the same passphrase keeps decrypting it
+func (f *FastCheckout) initSessionCache() error {
+	passphrase, err := readSessionPassphrase(f.prompter)
+	if err != nil {
+		return err
+	}
+
+	var salt []byte
+	if file, err := readSessionCacheFile(sessionCachePath()); err == nil {
+		salt = file.Salt
+	}
+
+	key, err := newSessionCacheKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	f.sessionKey = key
+	return nil
+}
+
+// RestoreCachedSession loads the encrypted session from disk and validates it against the
+// store with one cheap authenticated query. Returns true if the browser login can be skipped.
+func (f *FastCheckout) RestoreCachedSession() (bool, error) {
+	if f.sessionKey == nil {
+		if err := f.initSessionCache(); err != nil {
+			return false, err
+		}
+	}
+
+	path := sessionCachePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		f.reporter.Info(T("session_cache_none"))
+		return false, nil
+	}
+
+	cache, err := loadSessionCache(path, f.sessionKey)
+	if err != nil {
+		return false, err
+	}
+
+	maxAge := time.Duration(f.config.SessionCacheMaxAgeHours) * time.Hour
+	cookies := cache.liveCookies(time.Now(), maxAge)
+	if len(cookies) == 0 {
+		f.reporter.Warn(T("session_cache_expired"))
+		return false, nil
+	}
+
+	f.cookies = cookies
+	f.csrfToken = cache.CSRFToken
+	registerCookieSecrets(cookies)
+	registerSecret(cache.CSRFToken)
+	f.userAgent = cache.UserAgent
+
+	if err := f.ValidateSession(); err != nil {
+		f.reporter.Warn(T("session_cache_invalid", "error", err))
+		f.cookies = nil
+		f.csrfToken = ""
+		return false, nil
+	}
+
+	f.reporter.Info(T("session_cache_restored", "count", len(cookies), "saved_at", cache.SavedAt))
+	if expiry := cache.earliestExpiry(); !expiry.IsZero() {
+		f.reporter.Info(T("session_cache_expires", "expiry", expiry))
+	}
+
+	return true, nil
+}
+
+// saveSessionCache persists the current session if the cache is enabled
+func (f *FastCheckout) saveSessionCache() {
+	if f.sessionKey == nil || len(f.cookies) == 0 {
+		return
+	}
+
+	cache := newSessionCache(f.cookies, f.csrfToken, f.userAgent)
+	if err := saveSessionCache(sessionCachePath(), f.sessionKey, cache); err != nil {
+		f.reporter.Warn(T("session_cache_save_failed", "error", err))
+		return
+	}
+
+	f.reporter.Debug(T("debug_session_cache_saved", "count", len(cache.Cookies), "path", sessionCachePath()))
+}
+
+// ValidateSession runs a cheap authenticated query (the credit ledger) and reports
+// whether the current cookies still belong to a logged-in customer. It deliberately
+// bypasses the login retry wrapper - callers decide what to do with a dead session.
+func (f *FastCheckout) ValidateSession() error {
+	request := []GraphQLRequest{
+		{
+			OperationName: "SessionCheckQuery",
+			Variables:     map[string]interface{}{},
+			Query:         operation("SessionCheckQuery"),
+		},
+	}
+
+	_, err := f.graphqlRequest(request)
+	return err
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.enc")
+
+	key, err := newSessionCacheKey("correct horse", nil)
+	if err != nil {
+		t.Fatalf("newSessionCacheKey failed: %v", err)
+	}
+
+	cookies := []*http.Cookie{
+		{Name: "Rsi-Token", Value: "secret-token", Domain: ".robertsspaceindustries.com", Expires: time.Now().Add(time.Hour)},
+		{Name: "_rsi_device", Value: "device"},
+	}
+	if err := saveSessionCache(path, key, newSessionCache(cookies, "csrf-123", "UA/1.0")); err != nil {
+		t.Fatalf("saveSessionCache failed: %v", err)
+	}
+
+	// Cookie values must never be readable from the file itself
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	if strings.Contains(string(raw), "secret-token") || strings.Contains(string(raw), "csrf-123") {
+		t.Error("Session cache file contains plaintext secrets")
+	}
+
+	// Re-deriving from the stored salt must decrypt the same file
+	file, err := readSessionCacheFile(path)
+	if err != nil {
+		t.Fatalf("readSessionCacheFile failed: %v", err)
+	}
+	if file.KDF != "argon2id" || file.Time != sessionCacheArgonTime || file.MemoryKiB != sessionCacheArgonMemory {
+		t.Errorf("Unexpected KDF parameters: %s t=%d m=%d", file.KDF, file.Time, file.MemoryKiB)
+	}
+	rederived, err := newSessionCacheKey("correct horse", file.Salt)
+	if err != nil {
+		t.Fatalf("newSessionCacheKey failed: %v", err)
+	}
+
+	cache, err := loadSessionCache(path, rederived)
+	if err != nil {
+		t.Fatalf("loadSessionCache failed: %v", err)
+	}
+	if cache.CSRFToken != "csrf-123" || cache.UserAgent != "UA/1.0" || len(cache.Cookies) != 2 {
+		t.Errorf("Unexpected cache contents: %+v", cache)
+	}
+
+	wrong, _ := newSessionCacheKey("wrong passphrase", file.Salt)
+	if _, err := loadSessionCache(path, wrong); err == nil {
+		t.Error("Expected wrong passphrase to fail decryption")
+	}
+
+	// A cache from before Argon2id is not read; the next login replaces it
+	if err := os.WriteFile(path, []byte(`{"version":1,"kdf":"pbkdf2-sha256","iterations":210000}`), 0600); err != nil {
+		t.Fatalf("Failed to write old cache: %v", err)
+	}
+	if _, err := loadSessionCache(path, rederived); err == nil {
+		t.Error("Expected a version 1 cache to be rejected")
+	}
+}
+
+func TestSessionCacheLiveCookies(t *testing.T) {
+	now := time.Now()
+	cache := &sessionCache{
+		SavedAt: now.Add(-2 * time.Hour),
+		Cookies: []cachedCookie{
+			{Name: "expired", Expires: now.Add(-time.Minute)},
+			{Name: "valid", Expires: now.Add(3 * time.Hour)},
+			{Name: "sooner", Expires: now.Add(time.Hour)},
+			{Name: "session"},
+		},
+	}
+
+	live := cache.liveCookies(now, 12*time.Hour)
+	if len(live) != 3 {
+		t.Fatalf("Expected 3 live cookies, got %d", len(live))
+	}
+	for _, cookie := range live {
+		if cookie.Name == "expired" {
+			t.Error("Expired cookie should have been dropped")
+		}
+	}
+
+	// Browser-session cookies are dropped once the cache outlives maxAge
+	live = cache.liveCookies(now, time.Hour)
+	if len(live) != 2 {
+		t.Errorf("Expected 2 live cookies past max age, got %d", len(live))
+	}
+
+	if expiry := cache.earliestExpiry(); !expiry.Equal(now.Add(-time.Minute)) {
+		t.Errorf("Unexpected earliest expiry: %v", expiry)
+	}
+}
+
+func TestRestoreCachedSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(sessionPassphraseEnv, "correct horse")
+
+	loggedIn := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("Rsi-Token"); err != nil || cookie.Value != "secret-token" || !loggedIn {
+			w.Write([]byte(`[{"errors":[{"message":"Not logged in","code":"ErrNotLoggedIn"}]}]`))
+			return
+		}
+		w.Write([]byte(`[{"data":{"customer":{"ledger":{"amount":{"value":1000}}}}}]`))
+	}))
+	defer server.Close()
+
+	fc, err := NewFastCheckout(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.graphqlURL = server.URL
+
+	// No cache on disk yet
+	restored, err := fc.RestoreCachedSession()
+	if err != nil || restored {
+		t.Fatalf("Expected no restore without a cache file, got %v, %v", restored, err)
+	}
+
+	fc.cookies = []*http.Cookie{{Name: "Rsi-Token", Value: "secret-token", Expires: time.Now().Add(time.Hour)}}
+	fc.csrfToken = "csrf-123"
+	fc.saveSessionCache()
+
+	fresh, _ := NewFastCheckout(DefaultConfig())
+	fresh.graphqlURL = server.URL
+	restored, err = fresh.RestoreCachedSession()
+	if err != nil || !restored {
+		t.Fatalf("Expected cached session to be restored, got %v, %v", restored, err)
+	}
+	if fresh.csrfToken != "csrf-123" {
+		t.Errorf("Expected CSRF token to be restored, got %q", fresh.csrfToken)
+	}
+
+	// A session the store no longer accepts must fall back to browser login
+	loggedIn = false
+	rejected, _ := NewFastCheckout(DefaultConfig())
+	rejected.graphqlURL = server.URL
+	restored, err = rejected.RestoreCachedSession()
+	if err != nil || restored {
+		t.Errorf("Expected rejected session not to be restored, got %v, %v", restored, err)
+	}
+	if len(rejected.cookies) != 0 {
+		t.Error("Rejected session cookies should be cleared")
+	}
+}
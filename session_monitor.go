@@ -0,0 +1,183 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sessionCookieNames are the cookies that carry the store login and CSRF token. Rotation
+// or expiry of any other cookie (analytics, consent) doesn't affect checkout.
+var sessionCookieNames = []string{"Rsi-Token", "Rsi-XSRF"}
+
+// sessionMonitorRetryInterval is how often an unhealthy session is re-checked, so a
+// login in the browser window is picked up within a minute
+const sessionMonitorRetryInterval = time.Minute
+
+// sessionMonitor probes the store session at a low frequency while the orchestrator is
+// dormant, so a logout hours before a wave is repaired (or reported) long before T0
+// instead of blocking on stdin in the middle of the wave.
+type sessionMonitor struct {
+	fastCheckout *FastCheckout
+	automation   *Automation
+	reporter     Reporter
+	interval     time.Duration
+
+	lastCheck time.Time
+	healthy   bool
+}
+
+// newSessionMonitor creates a monitor; a zero interval disables it
+func newSessionMonitor(config *Config, fastCheckout *FastCheckout, automation *Automation) *sessionMonitor {
+	return &sessionMonitor{
+		fastCheckout: fastCheckout,
+		automation:   automation,
+		reporter:     NewReporter(config, "session"),
+		interval:     time.Duration(config.SessionCheckIntervalMinutes) * time.Minute,
+		healthy:      true,
+	}
+}
+
+// due reports whether the next probe should run. Unhealthy sessions are re-checked
+// every minute so a fresh browser login is picked up quickly.
+func (m *sessionMonitor) due(now time.Time) bool {
+	if m == nil || m.fastCheckout == nil || m.interval <= 0 {
+		return false
+	}
+
+	wait := m.interval
+	if !m.healthy {
+		wait = sessionMonitorRetryInterval
+	}
+	return m.lastCheck.IsZero() || now.Sub(m.lastCheck) >= wait
+}
+
+// check runs one health probe. deadline is the latest time the session must stay valid
+// (end of the upcoming wave); activation is only used to tell the user how long is left.
+func (m *sessionMonitor) check(now, activation, deadline time.Time) {
+	m.lastCheck = now
+
+	problem := m.detectProblem(deadline)
+	if problem == "" {
+		m.healthy = true
+		m.reporter.Debug(T("debug_session_monitor_ok"))
+		m.reporter.SessionHealth(SessionHealth{Healthy: true})
+		return
+	}
+
+	if m.recover(deadline) {
+		m.reporter.Info(T("session_monitor_recovered"))
+		m.healthy = true
+		m.reporter.SessionHealth(SessionHealth{Healthy: true})
+		return
+	}
+
+	m.healthy = false
+	m.reporter.SessionHealth(SessionHealth{Healthy: false, Problem: problem})
+	m.reporter.Info("")
+	m.reporter.Info(T("session_monitor_action_required", "problem", problem, "until", activation.Sub(now).Round(time.Minute)))
+	m.reporter.Info(T("session_monitor_login_instructions"))
+	m.reporter.Info("")
+}
+
+// detectProblem returns a short description of what is wrong with the current session,
+// or "" if it is healthy. Only a definite logout counts - a network failure is not a
+// reason to re-extract the session.
+func (m *sessionMonitor) detectProblem(deadline time.Time) string {
+	f := m.fastCheckout
+
+	if len(f.cookies) == 0 {
+		return T("session_monitor_problem_no_session")
+	}
+
+	// The browser refreshes its own cookies (and the CSRF token) as the site is used;
+	// compare first so the probe below runs with the newest session
+	if m.automation != nil && m.automation.page != nil {
+		if browser, err := f.browserCookies(m.automation); err == nil {
+			if changed := changedSessionCookies(f.cookies, browser); len(changed) > 0 {
+				m.reporter.Info(T("session_monitor_rotated", "cookies", strings.Join(changed, ", ")))
+				return T("session_monitor_problem_rotated")
+			}
+		}
+	}
+
+	if cookie := expiringSessionCookie(f.cookies, deadline); cookie != nil {
+		m.reporter.Warn(T("session_monitor_expiring", "name", cookie.Name, "expires", cookie.Expires))
+		return T("session_monitor_problem_expiring")
+	}
+
+	if err := f.ValidateSession(); err != nil {
+		if isNotLoggedInError(err) {
+			m.reporter.Warn(T("session_monitor_logged_out"))
+			return T("session_monitor_problem_logged_out")
+		}
+		m.reporter.Warn(T("session_monitor_probe_failed", "error", err))
+	}
+
+	return ""
+}
+
+// recover re-extracts the session from the browser and checks the result is usable
+func (m *sessionMonitor) recover(deadline time.Time) bool {
+	if m.automation == nil || m.automation.page == nil {
+		return false
+	}
+
+	f := m.fastCheckout
+	if err := f.LoadSessionFromBrowser(m.automation); err != nil {
+		m.reporter.Warn(T("session_monitor_reextract_failed", "error", err))
+		return false
+	}
+
+	if expiringSessionCookie(f.cookies, deadline) != nil {
+		return false
+	}
+
+	if err := f.ValidateSession(); err != nil {
+		if isNotLoggedInError(err) {
+			return false
+		}
+		// Store unreachable: the fresh cookies are the best we have, check again later
+		m.reporter.Warn(T("session_monitor_probe_failed", "error", err))
+	}
+
+	return true
+}
+
+// changedSessionCookies lists session cookies whose value in the browser differs from
+// the ones the API client is using (login refreshed, CSRF token rotated, or logged out)
+func changedSessionCookies(current, browser []*http.Cookie) []string {
+	var changed []string
+	for _, name := range sessionCookieNames {
+		ours := findCookie(current, name)
+		theirs := findCookie(browser, name)
+
+		if ours == nil && theirs == nil {
+			continue
+		}
+		if ours == nil || theirs == nil || ours.Value != theirs.Value {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// expiringSessionCookie returns the first session cookie that expires before deadline
+func expiringSessionCookie(cookies []*http.Cookie, deadline time.Time) *http.Cookie {
+	for _, name := range sessionCookieNames {
+		cookie := findCookie(cookies, name)
+		if cookie != nil && !cookie.Expires.IsZero() && cookie.Expires.Before(deadline) {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
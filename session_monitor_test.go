@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChangedSessionCookies(t *testing.T) {
+	current := []*http.Cookie{
+		{Name: "Rsi-Token", Value: "token-a"},
+		{Name: "Rsi-XSRF", Value: "xsrf-a"},
+		{Name: "_ga", Value: "analytics-a"},
+	}
+
+	same := []*http.Cookie{
+		{Name: "Rsi-Token", Value: "token-a"},
+		{Name: "Rsi-XSRF", Value: "xsrf-a"},
+		{Name: "_ga", Value: "analytics-b"}, // Non-session cookie changes are ignored
+	}
+	if changed := changedSessionCookies(current, same); len(changed) != 0 {
+		t.Errorf("Expected no changed session cookies, got %v", changed)
+	}
+
+	rotated := []*http.Cookie{
+		{Name: "Rsi-Token", Value: "token-a"},
+		{Name: "Rsi-XSRF", Value: "xsrf-b"},
+	}
+	if changed := changedSessionCookies(current, rotated); len(changed) != 1 || changed[0] != "Rsi-XSRF" {
+		t.Errorf("Expected CSRF rotation to be detected, got %v", changed)
+	}
+
+	loggedOut := []*http.Cookie{{Name: "Rsi-XSRF", Value: "xsrf-a"}}
+	if changed := changedSessionCookies(current, loggedOut); len(changed) != 1 || changed[0] != "Rsi-Token" {
+		t.Errorf("Expected missing login cookie to be detected, got %v", changed)
+	}
+}
+
+func TestExpiringSessionCookie(t *testing.T) {
+	now := time.Now()
+	deadline := now.Add(6 * time.Hour)
+
+	cookies := []*http.Cookie{
+		{Name: "_ga", Expires: now.Add(time.Minute)}, // Ignored: not a session cookie
+		{Name: "Rsi-Token", Expires: now.Add(24 * time.Hour)},
+		{Name: "Rsi-XSRF"}, // Browser-session cookie, no expiry
+	}
+	if cookie := expiringSessionCookie(cookies, deadline); cookie != nil {
+		t.Errorf("Expected no expiring session cookie, got %s", cookie.Name)
+	}
+
+	cookies[1].Expires = now.Add(2 * time.Hour)
+	if cookie := expiringSessionCookie(cookies, deadline); cookie == nil || cookie.Name != "Rsi-Token" {
+		t.Errorf("Expected Rsi-Token to expire before the deadline, got %v", cookie)
+	}
+}
+
+func TestSessionMonitorDue(t *testing.T) {
+	config := DefaultConfig()
+	fc, err := NewFastCheckout(config)
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+
+	monitor := newSessionMonitor(config, fc, nil)
+	now := time.Now()
+
+	if !monitor.due(now) {
+		t.Error("Expected first probe to be due immediately")
+	}
+
+	monitor.lastCheck = now
+	if monitor.due(now.Add(5 * time.Minute)) {
+		t.Error("Healthy session should not be probed before the interval")
+	}
+	if !monitor.due(now.Add(15 * time.Minute)) {
+		t.Error("Expected probe to be due after the interval")
+	}
+
+	monitor.healthy = false
+	if !monitor.due(now.Add(time.Minute)) {
+		t.Error("Unhealthy session should be re-checked every minute")
+	}
+
+	config.SessionCheckIntervalMinutes = 0
+	if newSessionMonitor(config, fc, nil).due(now) {
+		t.Error("Zero interval should disable the monitor")
+	}
+}
+
+func TestSessionMonitorDetectsLogout(t *testing.T) {
+	loggedIn := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !loggedIn {
+			w.Write([]byte(`[{"errors":[{"message":"TyUnknownCustomerException"}]}]`))
+			return
+		}
+		w.Write([]byte(`[{"data":{"customer":{"ledger":{"amount":{"value":1000}}}}}]`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	fc, err := NewFastCheckout(config)
+	if err != nil {
+		t.Fatalf("NewFastCheckout failed: %v", err)
+	}
+	fc.graphqlURL = server.URL
+	fc.cookies = []*http.Cookie{{Name: "Rsi-Token", Value: "token", Expires: time.Now().Add(48 * time.Hour)}}
+
+	monitor := newSessionMonitor(config, fc, nil)
+	now := time.Now()
+	activation := now.Add(6 * time.Hour)
+
+	monitor.check(now, activation, activation.Add(7*time.Minute))
+	if !monitor.healthy {
+		t.Fatal("Expected logged-in session to be healthy")
+	}
+
+	// Without a browser to re-extract from, a logout has to be escalated
+	loggedIn = false
+	monitor.check(now.Add(15*time.Minute), activation, activation.Add(7*time.Minute))
+	if monitor.healthy {
+		t.Error("Expected logout to mark the session unhealthy")
+	}
+
+	// An expiring login cookie is caught without any request to the store
+	loggedIn = true
+	fc.cookies[0].Expires = now.Add(time.Hour)
+	monitor.check(now.Add(16*time.Minute), activation, activation.Add(7*time.Minute))
+	if monitor.healthy {
+		t.Error("Expected cookie expiring before the wave to mark the session unhealthy")
+	}
+}
@@ -0,0 +1,506 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Typed models for the store's GraphQL responses. Objects the store may return as
+// null are pointers, so a missing field is reported by its path instead of
+// panicking in the middle of a wave.
+
+// responseError describes a store response that could not be decoded
+type responseError struct {
+	Operation string
+	Path      string // Missing path such as data.store.cart.totals (empty when malformed)
+	Err       error  // JSON error for malformed payloads
+}
+
+func (e *responseError) Error() string {
+	if e.Path != "" {
+		return T("error_response_missing_path", "operation", e.Operation, "path", e.Path)
+	}
+	return T("error_response_malformed", "operation", e.Operation, "err", e.Err)
+}
+
+func (e *responseError) Unwrap() error { return e.Err }
+
+// missingPath reports a required field the store left out or returned as null
+func missingPath(operation, path string) error {
+	return &responseError{Operation: operation, Path: path}
+}
+
+// decodeResponse unmarshals the data of the first reply in a batched GraphQL response
+func decodeResponse[R any](operation, resp string) (*R, error) {
+	var responses []struct {
+		Data *R `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(resp), &responses); err != nil {
+		return nil, &responseError{Operation: operation, Err: err}
+	}
+	if len(responses) == 0 {
+		return nil, missingPath(operation, "[0]")
+	}
+	if responses[0].Data == nil {
+		return nil, missingPath(operation, "data")
+	}
+	return responses[0].Data, nil
+}
+
+// storeMoney is an amount in minor units (cents)
+type storeMoney struct {
+	Amount *float64 `json:"amount"`
+}
+
+// storeTax is one tax line of the cart totals (VAT or sales tax)
+type storeTax struct {
+	Amount float64 `json:"amount"`
+	Name   string  `json:"name"`
+}
+
+type storeCartTotals struct {
+	Total    *float64  `json:"total"`
+	SubTotal *float64  `json:"subTotal"`
+	Tax1     *storeTax `json:"tax1"`
+	Tax2     *storeTax `json:"tax2"`
+	Credits  *struct {
+		Amount        float64  `json:"amount"` // Null until credit is applied
+		MaxApplicable *float64 `json:"maxApplicable"`
+	} `json:"credits"`
+}
+
+type storeLineItem struct {
+	ID    string      `json:"id"`
+	SkuID json.Number `json:"skuId"`
+	Sku   *struct {
+		Title string `json:"title"`
+	} `json:"sku"`
+	UnitPriceWithTax *storeMoney `json:"unitPriceWithTax"`
+	Qty              int         `json:"qty"`
+}
+
+type storeFlowStep struct {
+	Step   string `json:"step"`
+	Action string `json:"action"`
+	Active bool   `json:"active"`
+}
+
+type storeFlow struct {
+	Steps   []storeFlowStep `json:"steps"`
+	Current *struct {
+		OrderCreated bool `json:"orderCreated"`
+	} `json:"current"`
+}
+
+// activeStep returns the step the checkout flow is on ("" when none is active)
+func (f *storeFlow) activeStep() string {
+	for _, step := range f.Steps {
+		if step.Active {
+			return step.Step
+		}
+	}
+	return ""
+}
+
+type storeCart struct {
+	Totals    *storeCartTotals `json:"totals"`
+	LineItems []storeLineItem  `json:"lineItems"`
+	Flow      *storeFlow       `json:"flow"`
+}
+
+type storeLedger struct {
+	Ledger *struct {
+		Amount *struct {
+			Value *float64 `json:"value"`
+		} `json:"amount"`
+	} `json:"ledger"`
+}
+
+// cartResponse covers every query and mutation that answers with the cart
+type cartResponse struct {
+	Store *struct {
+		Cart  *storeCart `json:"cart"`
+		Order *struct {
+			Slug string `json:"slug"`
+		} `json:"order"`
+	} `json:"store"`
+	Customer *storeLedger `json:"customer"`
+}
+
+// cart returns data.store.cart
+func (r *cartResponse) cart(operation string) (*storeCart, error) {
+	if r.Store == nil {
+		return nil, missingPath(operation, "data.store")
+	}
+	if r.Store.Cart == nil {
+		return nil, missingPath(operation, "data.store.cart")
+	}
+	return r.Store.Cart, nil
+}
+
+// ledger returns the customer's store credit balance
+func (r *cartResponse) ledger(operation, currency string) (Money, error) {
+	switch {
+	case r.Customer == nil:
+		return Money{}, missingPath(operation, "data.customer")
+	case r.Customer.Ledger == nil:
+		return Money{}, missingPath(operation, "data.customer.ledger")
+	case r.Customer.Ledger.Amount == nil || r.Customer.Ledger.Amount.Value == nil:
+		return Money{}, missingPath(operation, "data.customer.ledger.amount.value")
+	}
+	return moneyFromStore(*r.Customer.Ledger.Amount.Value, currency), nil
+}
+
+// cartTotals holds the cart totals
+type cartTotals struct {
+	Total         Money
+	Tax           Money // Every tax the store reports for the cart
+	TaxIncluded   bool  // Tax is already part of the line prices (VAT) rather than added on top
+	MaxCredit     Money
+	CreditApplied Money
+}
+
+// totals returns data.store.cart.totals
+func (c *storeCart) totals(operation, currency string) (cartTotals, error) {
+	switch {
+	case c.Totals == nil:
+		return cartTotals{}, missingPath(operation, "data.store.cart.totals")
+	case c.Totals.Total == nil:
+		return cartTotals{}, missingPath(operation, "data.store.cart.totals.total")
+	case c.Totals.Credits == nil:
+		return cartTotals{}, missingPath(operation, "data.store.cart.totals.credits")
+	case c.Totals.Credits.MaxApplicable == nil:
+		return cartTotals{}, missingPath(operation, "data.store.cart.totals.credits.maxApplicable")
+	}
+
+	totals := cartTotals{
+		Total:         moneyFromStore(*c.Totals.Total, currency),
+		Tax:           newMoney(0, currency),
+		TaxIncluded:   true,
+		MaxCredit:     moneyFromStore(*c.Totals.Credits.MaxApplicable, currency),
+		CreditApplied: moneyFromStore(c.Totals.Credits.Amount, currency),
+	}
+	for _, tax := range []*storeTax{c.Totals.Tax1, c.Totals.Tax2} {
+		if tax != nil {
+			sum, err := totals.Tax.Add(moneyFromStore(tax.Amount, currency))
+			if err != nil {
+				return cartTotals{}, err
+			}
+			totals.Tax = sum
+		}
+	}
+
+	// Sales tax shows up as total = subtotal + tax; VAT is inside the subtotal already
+	if c.Totals.SubTotal != nil && totals.Tax.IsPositive() {
+		subTotal := moneyFromStore(*c.Totals.SubTotal, currency)
+		withTax, err := subTotal.Add(totals.Tax)
+		if err != nil {
+			return cartTotals{}, err
+		}
+		beforeCredit, err := totals.Total.Add(totals.CreditApplied)
+		if err != nil {
+			return cartTotals{}, err
+		}
+		totals.TaxIncluded = !withTax.Equal(beforeCredit)
+	}
+	return totals, nil
+}
+
+// items converts the cart's line items, which need a SKU and a price
+func (c *storeCart) items(operation, currency string) ([]CartItem, error) {
+	items := make([]CartItem, 0, len(c.LineItems))
+	for i, lineItem := range c.LineItems {
+		if lineItem.SkuID == "" {
+			return nil, missingPath(operation, fmt.Sprintf("data.store.cart.lineItems[%d].skuId", i))
+		}
+		if lineItem.UnitPriceWithTax == nil || lineItem.UnitPriceWithTax.Amount == nil {
+			return nil, missingPath(operation, fmt.Sprintf("data.store.cart.lineItems[%d].unitPriceWithTax.amount", i))
+		}
+
+		var name string
+		if lineItem.Sku != nil {
+			name = lineItem.Sku.Title
+		}
+
+		items = append(items, CartItem{
+			LineItemID: lineItem.ID,
+			Name:       name,
+			Price:      moneyFromStore(*lineItem.UnitPriceWithTax.Amount, currency),
+			SKUID:      lineItem.SkuID.String(),
+			Quantity:   lineItem.Qty,
+		})
+	}
+	return items, nil
+}
+
+// decodeCartTotals decodes CartSummaryViewQuery: cart totals and the credit ledger
+func decodeCartTotals(resp, currency string) (cartTotals, Money, error) {
+	const operation = "CartSummaryViewQuery"
+
+	data, err := decodeResponse[cartResponse](operation, resp)
+	if err != nil {
+		return cartTotals{}, Money{}, err
+	}
+	cart, err := data.cart(operation)
+	if err != nil {
+		return cartTotals{}, Money{}, err
+	}
+	totals, err := cart.totals(operation, currency)
+	if err != nil {
+		return cartTotals{}, Money{}, err
+	}
+	ledger, err := data.ledger(operation, currency)
+	if err != nil {
+		return cartTotals{}, Money{}, err
+	}
+	return totals, ledger, nil
+}
+
+// decodeCartInfo decodes CombinedCartQuery: totals, line items and the credit ledger
+func decodeCartInfo(resp, currency string) (*CartInfo, error) {
+	const operation = "CombinedCartQuery"
+
+	data, err := decodeResponse[cartResponse](operation, resp)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := data.cart(operation)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := cart.totals(operation, currency)
+	if err != nil {
+		return nil, err
+	}
+	items, err := cart.items(operation, currency)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := data.ledger(operation, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CartInfo{
+		Total:         totals.Total,
+		Tax:           totals.Tax,
+		TaxIncluded:   totals.TaxIncluded,
+		MaxCredit:     totals.MaxCredit,
+		CreditApplied: totals.CreditApplied,
+		Ledger:        ledger,
+		Items:         items,
+	}, nil
+}
+
+// decodeCartItems decodes StepperQuery: the cart's line items
+func decodeCartItems(resp, currency string) ([]CartItem, error) {
+	const operation = "StepperQuery"
+
+	data, err := decodeResponse[cartResponse](operation, resp)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := data.cart(operation)
+	if err != nil {
+		return nil, err
+	}
+	return cart.items(operation, currency)
+}
+
+// decodeCartFlow decodes the checkout flow returned by NextStepMutation and CartFlowQuery
+func decodeCartFlow(operation, resp string) (*storeFlow, error) {
+	data, err := decodeResponse[cartResponse](operation, resp)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := data.cart(operation)
+	if err != nil {
+		return nil, err
+	}
+	if cart.Flow == nil {
+		return nil, missingPath(operation, "data.store.cart.flow")
+	}
+	return cart.Flow, nil
+}
+
+// decodeValidateCart decodes CartValidateCartMutation. The order slug and flow state
+// are optional - the store has been seen to leave them out on success.
+func decodeValidateCart(resp string) (orderSlug string, orderCreated bool, err error) {
+	const operation = "CartValidateCartMutation"
+
+	data, err := decodeResponse[cartResponse](operation, resp)
+	if err != nil {
+		return "", false, err
+	}
+	if data.Store == nil {
+		return "", false, missingPath(operation, "data.store")
+	}
+	if data.Store.Order != nil {
+		orderSlug = data.Store.Order.Slug
+	}
+	if cart := data.Store.Cart; cart != nil && cart.Flow != nil && cart.Flow.Current != nil {
+		orderCreated = cart.Flow.Current.OrderCreated
+	}
+	return orderSlug, orderCreated, nil
+}
+
+// storeSku is one SKU of a listing
+type storeSku struct {
+	ID    json.Number `json:"id"`
+	Title string      `json:"title"`
+}
+
+type skuListingResponse struct {
+	Store *struct {
+		Listing *struct {
+			Skus []storeSku `json:"skus"`
+		} `json:"listing"`
+	} `json:"store"`
+}
+
+// decodeListingSkus decodes GetSkuQuery: the SKUs of a listing
+func decodeListingSkus(resp string) ([]storeSku, error) {
+	const operation = "GetSkuQuery"
+
+	data, err := decodeResponse[skuListingResponse](operation, resp)
+	if err != nil {
+		return nil, err
+	}
+	if data.Store == nil {
+		return nil, missingPath(operation, "data.store")
+	}
+	if data.Store.Listing == nil {
+		return nil, missingPath(operation, "data.store.listing")
+	}
+	return data.Store.Listing.Skus, nil
+}
+
+type skuSearchResponse struct {
+	Store *struct {
+		Search *struct {
+			Resources []struct {
+				ID string `json:"id"`
+			} `json:"resources"`
+		} `json:"search"`
+	} `json:"store"`
+}
+
+// decodeSkuSearch decodes GetSkus: the ids of the SKUs matching a slug search
+func decodeSkuSearch(resp string) ([]string, error) {
+	const operation = "GetSkus"
+
+	data, err := decodeResponse[skuSearchResponse](operation, resp)
+	if err != nil {
+		return nil, err
+	}
+	if data.Store == nil {
+		return nil, missingPath(operation, "data.store")
+	}
+	if data.Store.Search == nil {
+		return nil, missingPath(operation, "data.store.search")
+	}
+
+	ids := make([]string, 0, len(data.Store.Search.Resources))
+	for i, resource := range data.Store.Search.Resources {
+		if resource.ID == "" {
+			return nil, missingPath(operation, fmt.Sprintf("data.store.search.resources[%d].id", i))
+		}
+		ids = append(ids, resource.ID)
+	}
+	return ids, nil
+}
+
+// storeAddress is one address book entry
+type storeAddress struct {
+	ID             string `json:"id"`
+	DefaultBilling bool   `json:"defaultBilling"`
+}
+
+type addressBookResponse struct {
+	Store *struct {
+		AddressBook []storeAddress `json:"addressBook"`
+	} `json:"store"`
+}
+
+// decodeAddressBook decodes AddressBookQuery
+func decodeAddressBook(resp string) ([]storeAddress, error) {
+	const operation = "AddressBookQuery"
+
+	data, err := decodeResponse[addressBookResponse](operation, resp)
+	if err != nil {
+		return nil, err
+	}
+	if data.Store == nil {
+		return nil, missingPath(operation, "data.store")
+	}
+	for i, address := range data.Store.AddressBook {
+		if address.ID == "" {
+			return nil, missingPath(operation, fmt.Sprintf("data.store.addressBook[%d].id", i))
+		}
+	}
+	return data.Store.AddressBook, nil
+}
+
+type orderResponse struct {
+	Store *struct {
+		Order *struct {
+			Slug   string `json:"slug"`
+			Status string `json:"status"`
+			Totals *struct {
+				Total   *float64 `json:"total"`
+				Credits *struct {
+					Amount float64 `json:"amount"`
+				} `json:"credits"`
+			} `json:"totals"`
+			LineItems []storeLineItem `json:"lineItems"`
+		} `json:"order"`
+	} `json:"store"`
+}
+
+// decodeOrder decodes OrderQuery. A null order returns (nil, nil) - the slug is unknown.
+func decodeOrder(resp, currency string) (*orderDetails, error) {
+	const operation = "OrderQuery"
+
+	data, err := decodeResponse[orderResponse](operation, resp)
+	if err != nil {
+		return nil, err
+	}
+	if data.Store == nil {
+		return nil, missingPath(operation, "data.store")
+	}
+	order := data.Store.Order
+	if order == nil {
+		return nil, nil
+	}
+	if order.Totals == nil || order.Totals.Total == nil {
+		return nil, missingPath(operation, "data.store.order.totals.total")
+	}
+
+	details := &orderDetails{
+		Slug:       order.Slug,
+		Status:     order.Status,
+		Total:      moneyFromStore(*order.Totals.Total, currency),
+		CreditUsed: newMoney(0, currency),
+	}
+	if order.Totals.Credits != nil {
+		details.CreditUsed = moneyFromStore(order.Totals.Credits.Amount, currency)
+	}
+
+	for i, line := range order.LineItems {
+		if line.UnitPriceWithTax == nil || line.UnitPriceWithTax.Amount == nil {
+			return nil, missingPath(operation, fmt.Sprintf("data.store.order.lineItems[%d].unitPriceWithTax.amount", i))
+		}
+		var name string
+		if line.Sku != nil {
+			name = line.Sku.Title
+		}
+		details.Lines = append(details.Lines, orderLine{
+			SKUID:     line.SkuID.String(),
+			Name:      name,
+			Quantity:  line.Qty,
+			UnitPrice: moneyFromStore(*line.UnitPriceWithTax.Amount, currency),
+		})
+	}
+
+	return details, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeCartTotals(t *testing.T) {
+	totals, ledger, err := decodeCartTotals(`[{"data":{"store":{"cart":{"totals":{"total":4500,"credits":{"amount":null,"maxApplicable":4500}}}},"customer":{"ledger":{"amount":{"value":100000}}}}}]`, "USD")
+	if err != nil {
+		t.Fatalf("decodeCartTotals failed: %v", err)
+	}
+	if !totals.Total.Equal(usd(4500)) || !totals.MaxCredit.Equal(usd(4500)) || !totals.CreditApplied.IsZero() || !ledger.Equal(usd(100000)) {
+		t.Errorf("Unexpected totals %+v, ledger %s", totals, ledger)
+	}
+	if !totals.TaxIncluded {
+		t.Error("A cart without tax has nothing added on top")
+	}
+}
+
+func TestDecodeCartTax(t *testing.T) {
+	// Sales tax: the total is the subtotal plus tax, so tax comes on top of the line price
+	info, err := decodeCartInfo(`[{"data":{"store":{"cart":{"totals":{"total":4815,"subTotal":4500,"tax1":{"amount":315,"name":"Sales tax"},"tax2":null,"credits":{"amount":0,"maxApplicable":4815}},"lineItems":[{"id":"l1","skuId":222,"unitPriceWithTax":{"amount":4500},"qty":1}]}},"customer":{"ledger":{"amount":{"value":100000}}}}}]`, "USD")
+	if err != nil {
+		t.Fatalf("decodeCartInfo failed: %v", err)
+	}
+	if total, err := info.expectedTotal(info.Items[0]); info.TaxIncluded || !info.Tax.Equal(usd(315)) || err != nil || !total.Equal(usd(4815)) {
+		t.Errorf("Expected sales tax on top, got %+v", info)
+	}
+
+	// VAT: already in the line price and the subtotal
+	info, err = decodeCartInfo(`[{"data":{"store":{"cart":{"totals":{"total":4500,"subTotal":4500,"tax1":{"amount":750,"name":"VAT"},"credits":{"amount":0,"maxApplicable":4500}},"lineItems":[{"id":"l1","skuId":222,"unitPriceWithTax":{"amount":4500},"qty":1}]}},"customer":{"ledger":{"amount":{"value":100000}}}}}]`, "EUR")
+	if err != nil {
+		t.Fatalf("decodeCartInfo failed: %v", err)
+	}
+	if total, err := info.expectedTotal(info.Items[0]); !info.TaxIncluded || err != nil || !total.Equal(newMoney(4500, "EUR")) {
+		t.Errorf("Expected VAT to be included, got %+v", info)
+	}
+}
+
+func TestDecodeMissingPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func(string) error
+		resp   string
+		path   string
+	}{
+		{
+			name:   "null cart",
+			decode: func(resp string) error { _, _, err := decodeCartTotals(resp, "USD"); return err },
+			resp:   `[{"data":{"store":{"cart":null}}}]`,
+			path:   "data.store.cart",
+		},
+		{
+			name:   "null customer",
+			decode: func(resp string) error { _, _, err := decodeCartTotals(resp, "USD"); return err },
+			resp:   `[{"data":{"store":{"cart":{"totals":{"total":0,"credits":{"maxApplicable":0}}}},"customer":null}}]`,
+			path:   "data.customer",
+		},
+		{
+			name:   "null data",
+			decode: func(resp string) error { _, err := decodeCartInfo(resp, "USD"); return err },
+			resp:   `[{"data":null,"errors":[]}]`,
+			path:   "data",
+		},
+		{
+			name:   "empty batch",
+			decode: func(resp string) error { _, err := decodeCartItems(resp, "USD"); return err },
+			resp:   `[]`,
+			path:   "[0]",
+		},
+		{
+			name:   "line item without price",
+			decode: func(resp string) error { _, err := decodeCartItems(resp, "USD"); return err },
+			resp:   `[{"data":{"store":{"cart":{"lineItems":[{"id":"a","skuId":1,"qty":1,"unitPriceWithTax":null}]}}}}]`,
+			path:   "data.store.cart.lineItems[0].unitPriceWithTax.amount",
+		},
+		{
+			name:   "missing flow",
+			decode: func(resp string) error { _, err := decodeCartFlow("NextStepMutation", resp); return err },
+			resp:   `[{"data":{"store":{"cart":{}}}}]`,
+			path:   "data.store.cart.flow",
+		},
+		{
+			name:   "null listing",
+			decode: func(resp string) error { _, err := decodeListingSkus(resp); return err },
+			resp:   `[{"data":{"store":{"listing":null}}}]`,
+			path:   "data.store.listing",
+		},
+		{
+			name:   "order without totals",
+			decode: func(resp string) error { _, err := decodeOrder(resp, "USD"); return err },
+			resp:   `[{"data":{"store":{"order":{"slug":"X","totals":null}}}}]`,
+			path:   "data.store.order.totals.total",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.decode(tt.resp)
+
+			var respErr *responseError
+			if !errors.As(err, &respErr) {
+				t.Fatalf("Expected responseError, got %v", err)
+			}
+			if respErr.Path != tt.path {
+				t.Errorf("Expected missing path %q, got %q", tt.path, respErr.Path)
+			}
+		})
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	_, err := decodeCartInfo(`{"data":`, "USD")
+
+	var respErr *responseError
+	if !errors.As(err, &respErr) || respErr.Operation != "CombinedCartQuery" || respErr.Err == nil {
+		t.Errorf("Expected malformed CombinedCartQuery error, got %v", err)
+	}
+}
+
+func TestDecodeValidateCartOptionalFields(t *testing.T) {
+	slug, created, err := decodeValidateCart(`[{"data":{"store":{"cart":null,"order":null}}}]`)
+	if err != nil || slug != "" || created {
+		t.Errorf("Expected success without slug, got %q, %v, %v", slug, created, err)
+	}
+}
+
+// FuzzStoreDecoders feeds arbitrary payloads to every decoder: they may return errors
+// but must never panic
+func FuzzStoreDecoders(f *testing.F) {
+	seeds := []string{
+		``,
+		`null`,
+		`[]`,
+		`[null]`,
+		`[{"data":null}]`,
+		`[{"data":{"store":null,"customer":null}}]`,
+		`[{"data":{"store":{"cart":{"totals":null,"lineItems":null,"flow":null}}}}]`,
+		`[{"data":{"store":{"cart":{"totals":{"total":"x"}}}}}]`,
+		`[{"data":{"store":{"cart":{"lineItems":[null,{"skuId":null}]}}}}]`,
+		`[{"data":{"store":{"cart":{"totals":{"total":100,"credits":{"amount":0,"maxApplicable":100}},"lineItems":[{"id":"a","skuId":1,"sku":null,"unitPriceWithTax":{"amount":100},"qty":1}],"flow":{"steps":[{"step":"cart","active":true}],"current":null}}},"customer":{"ledger":{"amount":{"value":500}}}}}]`,
+		`[{"data":{"store":{"listing":{"skus":[{"id":null}]},"search":{"resources":[{"id":""}]},"addressBook":[{"id":null}],"order":{"totals":{"total":0},"lineItems":[{}]}}}}]`,
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, resp string) {
+		decodeCartTotals(resp, "USD")
+		if info, err := decodeCartInfo(resp, "USD"); err == nil && info == nil {
+			t.Error("decodeCartInfo returned neither cart nor error")
+		}
+		decodeCartItems(resp, "USD")
+		if flow, err := decodeCartFlow("CartFlowQuery", resp); err == nil {
+			flow.activeStep()
+		}
+		decodeValidateCart(resp)
+		decodeListingSkus(resp)
+		decodeSkuSearch(resp)
+		decodeAddressBook(resp)
+		decodeOrder(resp, "USD")
+	})
+}
@@ -9,15 +9,17 @@ import (
 // TimeSync handles time synchronization with reliable time servers
 type TimeSync struct {
 	offset        time.Duration
+	errorBound    time.Duration // Half the round trip of the last sync request
 	lastSyncTime  time.Time
 	synced        bool
-	debugMode     bool
+	client        *http.Client // Optional shared client; a private one is used when nil
+	reporter      Reporter
 }
 
 // NewTimeSync creates a new TimeSync instance
 func NewTimeSync(debugMode bool) *TimeSync {
 	return &TimeSync{
-		debugMode: debugMode,
+		reporter: NewReporter(&Config{DebugMode: debugMode}, "timesync"),
 	}
 }
 
@@ -28,29 +30,42 @@ func (ts *TimeSync) Sync() error {
 	// Use Amazon time server since CIG infrastructure is hosted on AWS
 	server := "https://www.amazon.com"
 
-	offset, err := ts.getTimeOffset(server)
+	offset, errorBound, err := ts.getTimeOffset(server)
 	if err != nil {
-		if ts.debugMode {
-			fmt.Printf("⚠️  Time sync failed for %s: %v\n", server, err)
-		}
+		ts.reporter.Debug(fmt.Sprintf("⚠️  Time sync failed for %s: %v", server, err))
 		return fmt.Errorf("failed to sync time with Amazon server: %w", err)
 	}
 
 	ts.offset = offset
+	ts.errorBound = errorBound
 	ts.lastSyncTime = time.Now()
 	ts.synced = true
 
-	if ts.debugMode {
-		fmt.Printf("✓ Time synchronized with Amazon (offset: %v)\n", ts.offset)
-	}
+	ts.reporter.Debug(fmt.Sprintf("✓ Time synchronized with Amazon (offset: %v)", ts.offset))
+
+	ts.reporter.TimeSynced(TimeSynced{Offset: offset, ErrorBound: errorBound})
 
 	return nil
 }
 
-// getTimeOffset makes an HTTP HEAD request and calculates time offset
-func (ts *TimeSync) getTimeOffset(url string) (time.Duration, error) {
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+// UseClient makes time sync requests go through the given client (and its transport)
+func (ts *TimeSync) UseClient(client *http.Client) {
+	ts.client = client
+}
+
+// UseReporter sends time sync diagnostics to the run's reporter
+func (ts *TimeSync) UseReporter(reporter Reporter) {
+	ts.reporter = reporter
+}
+
+// getTimeOffset makes an HTTP HEAD request and calculates time offset. The offset is
+// accurate to within the returned error bound (half the round trip).
+func (ts *TimeSync) getTimeOffset(url string) (time.Duration, time.Duration, error) {
+	client := ts.client
+	if client == nil {
+		client = &http.Client{
+			Timeout: 5 * time.Second,
+		}
 	}
 
 	// Record time before request
@@ -58,12 +73,12 @@ func (ts *TimeSync) getTimeOffset(url string) (time.Duration, error) {
 
 	req, err := http.NewRequest("HEAD", url, nil)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer resp.Body.Close()
 
@@ -73,12 +88,12 @@ func (ts *TimeSync) getTimeOffset(url string) (time.Duration, error) {
 	// Parse Date header
 	dateHeader := resp.Header.Get("Date")
 	if dateHeader == "" {
-		return 0, fmt.Errorf("no Date header in response")
+		return 0, 0, fmt.Errorf("no Date header in response")
 	}
 
 	serverTime, err := http.ParseTime(dateHeader)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse Date header: %w", err)
+		return 0, 0, fmt.Errorf("failed to parse Date header: %w", err)
 	}
 
 	// Estimate network latency (round trip time / 2)
@@ -88,7 +103,7 @@ func (ts *TimeSync) getTimeOffset(url string) (time.Duration, error) {
 	localTime := beforeRequest.Add(latency)
 	offset := serverTime.Sub(localTime)
 
-	return offset, nil
+	return offset, latency, nil
 }
 
 // Now returns the current synchronized time
@@ -112,6 +127,11 @@ func (ts *TimeSync) GetOffset() time.Duration {
 	return ts.offset
 }
 
+// GetErrorBound returns how far the offset may be off: half the sync round trip
+func (ts *TimeSync) GetErrorBound() time.Duration {
+	return ts.errorBound
+}
+
 // ShouldResync checks if we should resync (e.g., every hour)
 func (ts *TimeSync) ShouldResync() bool {
 	if !ts.synced {
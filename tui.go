@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// The --tui dashboard: a fixed panel at the top of the terminal with the wave table and
+// live counters, and the usual log scrolling underneath. It's drawn with plain ANSI
+// escapes (a scroll region below the panel) so no terminal library is needed.
+
+// Control is a command given from the dashboard's keyboard
+type Control string
+
+const (
+	ControlSkipWave Control = "skip_wave" // s: give up on the current wave
+	ControlResync   Control = "resync"    // r: synchronize the clock now
+	ControlAbort    Control = "abort"     // q: stop the run
+)
+
+// ControlSource is a reporter that also takes commands from the user
+type ControlSource interface {
+	// Controls starts reading keys; the channel is never closed
+	Controls() <-chan Control
+}
+
+// dashboardRefresh is how often the panel (clock and countdowns) is redrawn
+const dashboardRefresh = 250 * time.Millisecond
+
+// promptActive is set while a terminal prompt waits for input, so keys go to the
+// prompt instead of being taken as dashboard commands
+var promptActive atomic.Bool
+
+// dashboardWave is one row of the wave table
+type dashboardWave struct {
+	Start      time.Time
+	Activation time.Time
+	State      WaveState
+}
+
+// Dashboard is the --tui reporter
+type Dashboard struct {
+	out  io.Writer
+	rows int
+	cols int
+	log  *HumanReporter // Messages and multi-line events go to the scrolling log
+
+	mu            sync.Mutex
+	waves         []dashboardWave
+	synced        bool
+	offset        time.Duration
+	errorBound    time.Duration
+	session       *SessionHealth
+	sessionAt     time.Time
+	pollAttempts  int
+	lastStatus    int
+	validate      map[ValidateErrorClass]int
+	validateTotal int
+
+	panelHeight int // Guarded by outputMutex, like everything written to out
+	controls    chan Control
+	keysOnce    sync.Once
+	closeOnce   sync.Once
+	stop        chan struct{}
+	termState   *term.State // Terminal settings to restore, saved before reading keys
+}
+
+var (
+	dashboardOnce sync.Once
+	dashboard     *Dashboard
+)
+
+// sharedDashboard starts the dashboard on first use; every component of the run then
+// reports to it. It's nil when stdout isn't a terminal.
+func sharedDashboard() *Dashboard {
+	dashboardOnce.Do(func() {
+		if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return
+		}
+		rows, cols := terminalSize()
+		dashboard = newDashboard(os.Stdout, rows, cols)
+		dashboard.start()
+	})
+	return dashboard
+}
+
+func newDashboard(out io.Writer, rows, cols int) *Dashboard {
+	d := &Dashboard{
+		out:      out,
+		rows:     rows,
+		cols:     cols,
+		validate: map[ValidateErrorClass]int{},
+		controls: make(chan Control, 4),
+		stop:     make(chan struct{}),
+	}
+	d.log = &HumanReporter{Out: dashboardLog{d}}
+	return d
+}
+
+// terminalSize asks the terminal for its size, then tries $LINES and $COLUMNS
+func terminalSize() (rows, cols int) {
+	cols, rows, _ = term.GetSize(int(os.Stdout.Fd()))
+	if rows <= 0 {
+		rows, _ = strconv.Atoi(os.Getenv("LINES"))
+	}
+	if cols <= 0 {
+		cols, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	if rows <= 0 {
+		rows = 24
+	}
+	if cols <= 0 {
+		cols = 80
+	}
+	return rows, cols
+}
+
+// stdinFd is the keyboard the dashboard reads keys from
+func stdinFd() int {
+	return int(os.Stdin.Fd())
+}
+
+// start clears the screen, draws the panel and keeps it fresh. Ctrl+C gives the
+// terminal back before exiting.
+func (d *Dashboard) start() {
+	outputMutex.Lock()
+	io.WriteString(d.out, "\033[2J")
+	outputMutex.Unlock()
+	d.draw()
+
+	go func() {
+		ticker := time.NewTicker(dashboardRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				d.draw()
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		<-interrupts
+		d.Close()
+		os.Exit(130)
+	}()
+}
+
+// Close stops redrawing, removes the scroll region and restores the terminal settings
+func (d *Dashboard) Close() error {
+	d.closeOnce.Do(func() {
+		close(d.stop)
+
+		// Restore first: raw mode doesn't turn the final newline into a new line
+		if d.termState != nil {
+			term.Restore(stdinFd(), d.termState)
+		}
+
+		outputMutex.Lock()
+		fmt.Fprintf(d.out, "\033[r\033[%d;1H\n", d.rows)
+		outputMutex.Unlock()
+	})
+	return nil
+}
+
+// Controls switches the keyboard to raw mode, so single keys arrive without ENTER on
+// every platform, and starts reading them. Terminal prompts keep working: while one is
+// active the terminal is back in line mode and its input is passed through to it.
+func (d *Dashboard) Controls() <-chan Control {
+	d.keysOnce.Do(func() {
+		if term.IsTerminal(stdinFd()) {
+			if state, err := term.MakeRaw(stdinFd()); err == nil {
+				d.termState = state
+			}
+		}
+
+		reader, writer := io.Pipe()
+		terminalInput = bufio.NewReader(reader)
+
+		go func() {
+			key := make([]byte, 1)
+			for {
+				n, err := os.Stdin.Read(key)
+				if err != nil {
+					writer.CloseWithError(err)
+					return
+				}
+				if n == 0 {
+					continue
+				}
+				if promptActive.Load() {
+					writer.Write(key)
+					continue
+				}
+				// Raw mode delivers Ctrl+C as a key instead of an interrupt
+				if key[0] == 3 {
+					d.Close()
+					os.Exit(130)
+				}
+				d.handleKey(key[0])
+			}
+		}()
+	})
+	return d.controls
+}
+
+// handleKey turns a key press into a control. Presses are dropped while earlier ones
+// are still waiting to be handled.
+func (d *Dashboard) handleKey(key byte) {
+	var control Control
+	switch key {
+	case 's', 'S':
+		control = ControlSkipWave
+	case 'r', 'R':
+		control = ControlResync
+	case 'q', 'Q':
+		control = ControlAbort
+	default:
+		return
+	}
+
+	select {
+	case d.controls <- control:
+	default:
+	}
+}
+
+// beginTerminalPrompt hands the keyboard to a prompt, back in line mode with echo
+func beginTerminalPrompt() {
+	promptActive.Store(true)
+	if dashboard != nil && dashboard.termState != nil {
+		term.Restore(stdinFd(), dashboard.termState)
+	}
+}
+
+// endTerminalPrompt gives the keyboard back to the dashboard
+func endTerminalPrompt() {
+	if dashboard != nil && dashboard.termState != nil {
+		term.MakeRaw(stdinFd())
+	}
+	promptActive.Store(false)
+}
+
+// draw repaints the panel and keeps the log's scroll region right below it
+func (d *Dashboard) draw() {
+	lines := d.render(time.Now())
+	if max := d.rows - 2; len(lines) > max && max > 0 {
+		lines = lines[:max]
+	}
+
+	var b strings.Builder
+	b.WriteString("\0337") // Save the log's cursor
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	if len(lines) != d.panelHeight {
+		d.panelHeight = len(lines)
+		fmt.Fprintf(&b, "\033[%d;%dr", d.panelHeight+1, d.rows)
+	}
+	for i, line := range lines {
+		fmt.Fprintf(&b, "\033[%d;1H\033[2K%s", i+1, truncateRunes(line, d.cols))
+	}
+	b.WriteString("\0338")
+	io.WriteString(d.out, b.String())
+}
+
+// render builds the panel lines for the current state
+func (d *Dashboard) render(now time.Time) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	synchronizedNow := now.Add(d.offset)
+
+	lines := []string{T("tui_title", "time", synchronizedNow)}
+
+	if d.synced {
+		lines = append(lines, T("tui_sync", "offset", d.offset, "bound", d.errorBound))
+	} else {
+		lines = append(lines, T("tui_sync_pending"))
+	}
+
+	switch {
+	case d.session == nil:
+		lines = append(lines, T("tui_session_unknown"))
+	case d.session.Healthy:
+		lines = append(lines, T("tui_session_healthy", "checked", d.sessionAt))
+	default:
+		lines = append(lines, T("tui_session_problem", "problem", d.session.Problem))
+	}
+
+	lines = append(lines, T("tui_waves_header"))
+	next := -1
+	for i, wave := range d.waves {
+		state := waveStateLabel(wave.State)
+		switch {
+		case wave.State == WaveWaiting && wave.Activation.After(synchronizedNow):
+			lines = append(lines, T("tui_wave_row_countdown", "number", i+1, "start", wave.Start, "state", state, "countdown", wave.Activation.Sub(synchronizedNow)))
+			if next == -1 {
+				next = i
+			}
+		case wave.State == WavePolling:
+			lines = append(lines, T("tui_wave_row_countdown", "number", i+1, "start", wave.Start, "state", state, "countdown", wave.Start.Sub(synchronizedNow)))
+		default:
+			lines = append(lines, T("tui_wave_row", "number", i+1, "start", wave.Start, "state", state))
+		}
+	}
+
+	if next >= 0 {
+		lines = append(lines, T("tui_next_activation", "number", next+1, "countdown", d.waves[next].Activation.Sub(synchronizedNow)))
+	} else {
+		lines = append(lines, T("tui_next_activation_none"))
+	}
+
+	if d.pollAttempts > 0 {
+		lines = append(lines, T("tui_polling", "count", d.pollAttempts, "status", d.lastStatus))
+	} else {
+		lines = append(lines, T("tui_polling_idle"))
+	}
+
+	if d.validateTotal > 0 {
+		var classes []string
+		for _, class := range []ValidateErrorClass{ValidatePayment4227, ValidatePayment4226, ValidateOutOfStock, ValidateRateLimit, ValidateCaptcha, ValidateOther} {
+			if n := d.validate[class]; n > 0 {
+				classes = append(classes, fmt.Sprintf("%s %d", class, n))
+			}
+		}
+		lines = append(lines, T("tui_validate", "count", d.validateTotal, "classes", strings.Join(classes, ", ")))
+	} else {
+		lines = append(lines, T("tui_validate_idle"))
+	}
+
+	lines = append(lines, T("tui_keys"), strings.Repeat("─", d.cols))
+	return lines
+}
+
+func waveStateLabel(state WaveState) string {
+	switch state {
+	case WavePolling:
+		return T("tui_state_polling")
+	case WaveCheckout:
+		return T("tui_state_checkout")
+	case WaveSucceeded:
+		return T("tui_state_succeeded")
+	case WaveFailed:
+		return T("tui_state_failed")
+	case WaveSkipped:
+		return T("tui_state_skipped")
+	case WaveEnded:
+		return T("tui_state_ended")
+	}
+	return T("tui_state_waiting")
+}
+
+// truncateRunes cuts text to at most n characters
+func truncateRunes(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	return string([]rune(text)[:n])
+}
+
+// dashboardLog writes lines at the bottom of the scroll region, which moves the log up
+// under the panel. Callers hold outputMutex (see writeLine).
+type dashboardLog struct {
+	d *Dashboard
+}
+
+func (l dashboardLog) Write(p []byte) (int, error) {
+	text := strings.TrimSuffix(string(p), "\n")
+	for _, line := range strings.Split(text, "\n") {
+		if _, err := fmt.Fprintf(l.d.out, "\033[%d;1H\n%s", l.d.rows, line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (d *Dashboard) WaveScheduled(event WaveScheduled) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for len(d.waves) < event.Total {
+		d.waves = append(d.waves, dashboardWave{State: WaveWaiting})
+	}
+	if event.Wave >= 1 && event.Wave <= len(d.waves) {
+		d.waves[event.Wave-1].Start = event.Start
+		d.waves[event.Wave-1].Activation = event.Activation
+	}
+}
+
+func (d *Dashboard) WaveStateChanged(event WaveStateChanged) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if event.Wave >= 1 && event.Wave <= len(d.waves) {
+		d.waves[event.Wave-1].State = event.State
+	}
+	if event.State == WavePolling {
+		d.pollAttempts, d.lastStatus = 0, 0
+	}
+}
+
+func (d *Dashboard) TimeSynced(event TimeSynced) {
+	d.mu.Lock()
+	d.synced = true
+	d.offset = event.Offset
+	d.errorBound = event.ErrorBound
+	d.mu.Unlock()
+
+	d.log.TimeSynced(event)
+}
+
+func (d *Dashboard) PollProgress(event PollProgress) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pollAttempts = event.Attempt
+	d.lastStatus = event.Status
+}
+
+func (d *Dashboard) SessionHealth(event SessionHealth) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.session = &event
+	d.sessionAt = time.Now()
+}
+
+func (d *Dashboard) ValidateAttempt(event ValidateAttempt) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.validate[event.Class]++
+	d.validateTotal++
+}
+
+func (d *Dashboard) CartWarning(event CartWarning)   { d.log.CartWarning(event) }
+func (d *Dashboard) CheckoutStep(event CheckoutStep) { d.log.CheckoutStep(event) }
+func (d *Dashboard) Success(event Success)           { d.log.Success(event) }
+
+func (d *Dashboard) Info(text string)  { d.log.Info(text) }
+func (d *Dashboard) Warn(text string)  { d.log.Warn(text) }
+func (d *Dashboard) Error(text string) { d.log.Error(text) }
+func (d *Dashboard) Debug(text string) { d.log.Debug(text) }
+func (d *Dashboard) Trace(string)      {}
+
+func (d *Dashboard) With(...interface{}) Reporter { return d }
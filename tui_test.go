@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboardRender(t *testing.T) {
+	withBuiltinLocale(t, "en_US")
+
+	now := time.Date(2026, time.March, 7, 17, 50, 0, 0, time.UTC)
+	d := newDashboard(&bytes.Buffer{}, 30, 80)
+
+	d.WaveScheduled(WaveScheduled{Wave: 1, Total: 2, Start: now.Add(-time.Hour), Activation: now.Add(-62 * time.Minute)})
+	d.WaveScheduled(WaveScheduled{Wave: 2, Total: 2, Start: now.Add(12 * time.Minute), Activation: now.Add(10 * time.Minute)})
+	d.WaveStateChanged(WaveStateChanged{Wave: 1, State: WaveEnded})
+	d.mu.Lock()
+	d.synced, d.offset, d.errorBound = true, 0, 40*time.Millisecond
+	d.mu.Unlock()
+	d.SessionHealth(SessionHealth{Healthy: false, Problem: "logged out"})
+	d.PollProgress(PollProgress{Attempt: 17, Status: 404})
+	d.ValidateAttempt(ValidateAttempt{Attempt: 1, Class: ValidatePayment4227})
+	d.ValidateAttempt(ValidateAttempt{Attempt: 2, Class: ValidatePayment4227})
+	d.ValidateAttempt(ValidateAttempt{Attempt: 3, Class: ValidateOutOfStock})
+
+	panel := strings.Join(d.render(now), "\n")
+	for _, want := range []string{
+		"Clock: offset 0s ± 40ms",
+		"Session: ⚠️  logged out",
+		"1. 16:50:00 UTC  ended",
+		"2. 18:02:00 UTC  waiting  in 10m",
+		"Next activation: wave 2 in 10m",
+		"Polling: 17 attempts, last status 404",
+		"Validate: 3 failed (payment_4227 2, out_of_stock 1)",
+	} {
+		if !strings.Contains(panel, want) {
+			t.Errorf("Panel is missing %q:\n%s", want, panel)
+		}
+	}
+}
+
+// Test that log lines are written at the bottom of the scroll region below the panel
+func TestDashboardLog(t *testing.T) {
+	withLocale(t, nil)
+
+	var out bytes.Buffer
+	d := newDashboard(&out, 30, 80)
+	d.Info("first\nsecond")
+
+	if want := "\033[30;1H\nfirst\033[30;1H\nsecond"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
+
+func TestDashboardKeys(t *testing.T) {
+	d := newDashboard(&bytes.Buffer{}, 30, 80)
+	for _, key := range []byte("sxRq") {
+		d.handleKey(key)
+	}
+
+	var got []Control
+	for len(d.controls) > 0 {
+		got = append(got, <-d.controls)
+	}
+	want := []Control{ControlSkipWave, ControlResync, ControlAbort}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Control %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
+
+// Test that dashboard keys interrupt the dormant wait before a wave
+func TestSleepUntilWithUpdatesControls(t *testing.T) {
+	withLocale(t, nil)
+
+	for _, tt := range []struct {
+		control Control
+		want    error
+	}{
+		{ControlSkipWave, errWaveSkipped},
+		{ControlAbort, errRunAborted},
+	} {
+		controls := make(chan Control, 1)
+		controls <- tt.control
+		mwo := &MultiWaveOrchestrator{
+			config:   DefaultConfig(),
+			timeSync: NewTimeSync(false),
+			reporter: &QuietReporter{Out: &bytes.Buffer{}},
+			controls: controls,
+		}
+
+		if err := mwo.sleepUntilWithUpdates(time.Now().Add(time.Hour)); err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.control, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// WaveStatus is where a configured wave stands at a given time
+type WaveStatus string
+
+const (
+	WaveUpcoming WaveStatus = "upcoming" // Before its activation
+	WaveActive   WaveStatus = "active"   // Between activation and its timeout
+	WavePassed   WaveStatus = "passed"   // Past its timeout
+)
+
+// plannedWave is one sale window with the times the orchestrator derives from it
+type plannedWave struct {
+	Number     int
+	Start      time.Time
+	Activation time.Time // Polling starts (pre_wave_activation_minutes before Start)
+	End        time.Time // The wave is given up (post_wave_timeout_minutes after Start)
+}
+
+// Status tells whether the wave is still ahead, running or over at now
+func (w plannedWave) Status(now time.Time) WaveStatus {
+	switch {
+	case now.Before(w.Activation):
+		return WaveUpcoming
+	case now.Before(w.End):
+		return WaveActive
+	default:
+		return WavePassed
+	}
+}
+
+// planWaves parses the configured sale windows the way a run would
+func planWaves(config *Config) ([]plannedWave, error) {
+	preWave := time.Duration(config.PreWaveActivationMinutes) * time.Minute
+	postWave := time.Duration(config.PostWaveTimeoutMinutes) * time.Minute
+
+	var waves []plannedWave
+	for i, timeStr := range config.SaleWindows {
+		start, err := ParseSaleTime(timeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sale window %d (%s): %w", i+1, timeStr, err)
+		}
+		waves = append(waves, plannedWave{
+			Number:     i + 1,
+			Start:      start,
+			Activation: start.Add(-preWave),
+			End:        start.Add(postWave),
+		})
+	}
+	return waves, nil
+}
+
+// runWavesCommand handles "specter waves": the schedule of the configured waves
+func runWavesCommand(env *commandEnv, args []string) error {
+	if len(args) > 0 {
+		return TError("command_waves_usage")
+	}
+
+	waves, err := planWaves(env.Config)
+	if err != nil {
+		return err
+	}
+	if len(waves) == 0 {
+		return TError("waves_none_configured")
+	}
+
+	now := time.Now()
+	reporter := env.Reporter
+	reporter.Info(T("multiwave_wave_list"))
+	for _, wave := range waves {
+		reporter.Info(T("waves_row", "number", wave.Number, "start", wave.Start, "activation", wave.Activation, "end", wave.End))
+		switch wave.Status(now) {
+		case WaveUpcoming:
+			reporter.Info(T("waves_status_upcoming", "countdown", wave.Activation.Sub(now)))
+		case WaveActive:
+			reporter.Info(T("waves_status_active", "countdown", wave.End.Sub(now)))
+		default:
+			reporter.Info(T("waves_status_ended"))
+		}
+	}
+	return nil
+}
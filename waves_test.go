@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanWaves(t *testing.T) {
+	config := DefaultConfig()
+	config.SaleWindows = []string{"2026-03-07 18:00", "2026-03-07 21:00"}
+	config.PreWaveActivationMinutes = 2
+	config.PostWaveTimeoutMinutes = 5
+
+	waves, err := planWaves(config)
+	if err != nil {
+		t.Fatalf("planWaves failed: %v", err)
+	}
+	if len(waves) != 2 {
+		t.Fatalf("Expected 2 waves, got %d", len(waves))
+	}
+
+	start := time.Date(2026, time.March, 7, 18, 0, 0, 0, time.UTC)
+	wave := waves[0]
+	if wave.Number != 1 || !wave.Activation.Equal(start.Add(-2*time.Minute)) || !wave.End.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("Unexpected wave: %+v", wave)
+	}
+
+	for _, tt := range []struct {
+		at   time.Time
+		want WaveStatus
+	}{
+		{start.Add(-3 * time.Minute), WaveUpcoming},
+		{start.Add(-2 * time.Minute), WaveActive},
+		{start.Add(4 * time.Minute), WaveActive},
+		{start.Add(5 * time.Minute), WavePassed},
+	} {
+		if got := wave.Status(tt.at); got != tt.want {
+			t.Errorf("Status at %v: expected %s, got %s", tt.at, tt.want, got)
+		}
+	}
+
+	config.SaleWindows = append(config.SaleWindows, "tomorrow")
+	if _, err := planWaves(config); err == nil {
+		t.Error("Expected an error for an invalid sale window")
+	}
+}